@@ -0,0 +1,350 @@
+// Package analyzer checks the `se` tags of local structs statically, reporting at build time the paths and types the
+// mapping would reject on its first call. It's a go/analysis analyzer, run with the sevet command, standalone or
+// through go vet:
+//
+//	go install github.com/ilexPar/struct-marshal/cmd/sevet
+//	go vet -vettool=$(which sevet) ./...
+//
+// As foreign types are only known when mapping, the analyzer resolves the tags against the foreign types named by
+// their `types<>` option, found in the package being checked or the ones it imports. The fields of nested structs
+// are checked against the foreign types of the field holding them, under its path. Reported issues:
+//   - tags the mapping rejects regardless of the foreign type, eg setting conflicting options
+//   - paths leading to no foreign field, where no alternative path leads to one either
+//   - fields holding a string, boolean or number mapping a foreign field holding another one, unless both are
+//     numbers or the tag sets an option transforming the value
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+// Analyzer checks the se tags of the structs declared by a package, see the package documentation.
+var Analyzer = &analysis.Analyzer{
+	Name: "setags",
+	Doc:  "check that the paths of se tags lead to fields of the foreign types named by their types<> option",
+	URL:  "https://pkg.go.dev/github.com/ilexPar/struct-marshal/analyzer",
+	Run:  run,
+}
+
+// candidate is a foreign type a local field is checked against, along with the path the field maps in it.
+type candidate struct {
+	foreign *types.Named
+	paths   [][]string // main path followed by the alternative ones
+}
+
+// checker holds the state of the analysis of a package.
+type checker struct {
+	pass    *analysis.Pass
+	visited map[string]bool // structs already checked under a foreign type and path, guarding against cycles
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	tagged := taggedStructs(pass)
+	nested := map[*types.Named]bool{}
+	for _, decl := range tagged {
+		for _, field := range decl.fields {
+			if child := nestedStruct(pass, field); child != nil && child != decl.named {
+				nested[child] = true
+			}
+		}
+	}
+
+	c := &checker{pass: pass, visited: map[string]bool{}}
+	for _, decl := range tagged {
+		if !nested[decl.named] {
+			c.checkStruct(decl.named, tagged, nil, nil)
+		}
+	}
+	return nil, nil
+}
+
+// structDecl is a struct type declared by the package being checked, along with the syntax of its fields.
+type structDecl struct {
+	named  *types.Named
+	fields []*ast.Field
+}
+
+// taggedStructs returns the struct types declared by the package holding at least one field with an `se` tag.
+func taggedStructs(pass *analysis.Pass) map[*types.Named]structDecl {
+	decls := map[*types.Named]structDecl{}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			named, ok := pass.TypesInfo.Defs[spec.Name].Type().(*types.Named)
+			if !ok {
+				return true
+			}
+			if slices.ContainsFunc(st.Fields.List, func(field *ast.Field) bool { return seTag(field) != "" }) {
+				decls[named] = structDecl{named: named, fields: st.Fields.List}
+			}
+			return true
+		})
+	}
+	return decls
+}
+
+// checkStruct checks the tagged fields of a struct against the candidate foreign types inherited from the field
+// holding it, if any, under the parent path.
+func (this *checker) checkStruct(
+	named *types.Named,
+	tagged map[*types.Named]structDecl,
+	inherited []*types.Named,
+	parentPath []string,
+) {
+	decl, ok := tagged[named]
+	if !ok {
+		return
+	}
+	key := named.String() + ":" + strings.Join(parentPath, ".")
+	for _, foreign := range inherited {
+		key += "~" + foreign.String()
+	}
+	if this.visited[key] {
+		return
+	}
+	this.visited[key] = true
+
+	for _, field := range decl.fields {
+		if seTag(field) == "" || len(field.Names) == 0 {
+			continue
+		}
+		name := field.Names[0].Name
+		tag := pkg.ParseTagValue(name, seTag(field))
+		if err := tag.Validate(); err != nil {
+			this.pass.Reportf(field.Tag.Pos(), "invalid se tag of field %v: %v", name, err)
+			continue
+		}
+		if tag.Skip || skipsPathCheck(tag) {
+			continue
+		}
+
+		candidates := this.candidates(tag, inherited)
+		for _, candidate := range candidates {
+			var target types.Type
+			var resolved []string
+			for _, path := range candidate.paths {
+				fullPath, ok := composePath(parentPath, path)
+				if !ok {
+					continue
+				}
+				if target = resolvePath(candidate.foreign, fullPath); target != nil {
+					resolved = fullPath
+					break
+				}
+			}
+			if target == nil {
+				this.pass.Reportf(field.Tag.Pos(), "se tag path %v of field %v not found in %v",
+					strconv.Quote(strings.Join(candidate.paths[0], ".")), name, candidate.foreign.Obj().Name())
+				continue
+			}
+			local := this.pass.TypesInfo.TypeOf(field.Type)
+			if mismatch(local, target) && !transformsValue(tag) {
+				this.pass.Reportf(field.Tag.Pos(), "se tag of field %v maps a %v into %v field of type %v",
+					name, local, candidate.foreign.Obj().Name(), target)
+			}
+			if child := nestedStruct(this.pass, field); child != nil && tag.Opts.Convert == "" {
+				this.checkStruct(child, tagged, []*types.Named{candidate.foreign}, resolved)
+			}
+		}
+		if len(candidates) == 0 {
+			if child := nestedStruct(this.pass, field); child != nil && tag.Path[0] == pkg.DISMISS_NESTED {
+				this.checkStruct(child, tagged, nil, parentPath)
+			}
+		}
+	}
+}
+
+// candidates returns the foreign types a field is checked against: the ones named by its `types<>` option, with
+// the path set by each entry or the main path, or else the inherited ones with the main path. Entries holding
+// patterns and naming types that can't be found are left out.
+func (this *checker) candidates(tag pkg.FieldTag, inherited []*types.Named) []candidate {
+	paths := append([][]string{tag.Path}, tag.Alternatives...)
+	candidates := []candidate{}
+	if len(tag.Opts.MatchTypes) == 0 {
+		if tag.Path[0] == pkg.MULTI_TYPE_NAME {
+			return candidates
+		}
+		for _, foreign := range inherited {
+			candidates = append(candidates, candidate{foreign: foreign, paths: paths})
+		}
+		return candidates
+	}
+	for _, match := range tag.Opts.MatchTypes {
+		if strings.HasPrefix(match.Name, pkg.TYPE_PATTERN_PREFIX) {
+			continue
+		}
+		foreign := this.lookupType(match.Name)
+		if foreign == nil {
+			continue
+		}
+		switch {
+		case len(match.Path) > 0:
+			candidates = append(candidates, candidate{foreign: foreign, paths: [][]string{match.Path}})
+		case tag.Path[0] != pkg.MULTI_TYPE_NAME:
+			candidates = append(candidates, candidate{foreign: foreign, paths: paths})
+		}
+	}
+	return candidates
+}
+
+// lookupType finds the struct type named by a `types<>` entry, eg `Deployment`, `api.Deployment` or
+// `github.com/org/api.Deployment`, among the ones declared by the package being checked or the ones it imports.
+func (this *checker) lookupType(name string) *types.Named {
+	qualifier, typeName := "", name
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		qualifier, typeName = name[:dot], name[dot+1:]
+	}
+	for _, scopePkg := range append([]*types.Package{this.pass.Pkg}, this.pass.Pkg.Imports()...) {
+		if qualifier != "" && qualifier != scopePkg.Name() && qualifier != scopePkg.Path() {
+			continue
+		}
+		obj, ok := scopePkg.Scope().Lookup(typeName).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if named, ok := obj.Type().(*types.Named); ok {
+			if _, isStruct := named.Underlying().(*types.Struct); isStruct {
+				return named
+			}
+		}
+	}
+	return nil
+}
+
+// composePath composes the path of a tag with the path inherited from the field holding the struct, as the mapping
+// does: `->` keeps the inherited path, absolute paths start from the foreign type, and each `^` leading relative
+// paths strips a segment of the inherited path. It returns false when there are no segments left to strip.
+func composePath(parentPath, path []string) ([]string, bool) {
+	switch path[0] {
+	case pkg.DISMISS_NESTED:
+		return slices.Clone(parentPath), true
+	case pkg.ABSOLUTE_PATH:
+		return slices.Clone(path[1:]), true
+	}
+	kept := len(parentPath)
+	for len(path) > 0 && path[0] == pkg.PARENT_PATH {
+		if kept == 0 {
+			return nil, false
+		}
+		kept, path = kept-1, path[1:]
+	}
+	return append(slices.Clone(parentPath[:kept]), path...), true
+}
+
+// resolvePath returns the type of the field a path leads to in a foreign type, going through pointers, slices,
+// arrays and maps, and the structs embedded by it, or nil when there is no such field.
+func resolvePath(foreign types.Type, path []string) types.Type {
+	current := foreign
+	for _, segment := range path {
+		name := segment
+		if bracket := strings.Index(segment, "["); bracket >= 0 {
+			name = segment[:bracket]
+		}
+		if _, ok := unwrap(current).Underlying().(*types.Struct); !ok {
+			return nil
+		}
+		// fields promoted through embedded structs resolve as reflect does, ambiguous ones finding no field
+		obj, _, _ := types.LookupFieldOrMethod(unwrap(current), true, nil, name)
+		found, ok := obj.(*types.Var)
+		if !ok {
+			return nil
+		}
+		current = found.Type()
+		if strings.Contains(segment, "[") {
+			current = unwrap(current)
+		}
+	}
+	return current
+}
+
+// unwrap returns the type of the values held by a type, going through pointers, slices, arrays and maps.
+func unwrap(t types.Type) types.Type {
+	for {
+		switch typed := t.Underlying().(type) {
+		case *types.Pointer:
+			t = typed.Elem()
+		case *types.Slice:
+			t = typed.Elem()
+		case *types.Array:
+			t = typed.Elem()
+		case *types.Map:
+			t = typed.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// mismatch reports whether a local type holding a string, boolean or number maps a foreign type holding another
+// one. Named types are left out, as converters may be registered for them, and so are numbers mapping numbers.
+func mismatch(local, foreign types.Type) bool {
+	localBasic, ok := local.(*types.Basic)
+	if !ok {
+		return false
+	}
+	foreignBasic, ok := foreign.(*types.Basic)
+	if !ok || types.Identical(localBasic, foreignBasic) {
+		return false
+	}
+	numeric := types.IsInteger | types.IsFloat
+	return localBasic.Info()&numeric == 0 || foreignBasic.Info()&numeric == 0
+}
+
+// transformsValue reports whether a tag sets an option transforming the value instead of copying it.
+func transformsValue(tag pkg.FieldTag) bool {
+	return tag.Opts.Convert != "" || tag.Opts.Stringify || tag.Opts.Base64 || tag.Opts.TimeLayout != ""
+}
+
+// skipsPathCheck reports whether a tag doesn't map the field to a path of its own: computed and joined fields,
+// tags selecting the path at runtime by kind or version, and paths traversing interface fields, whose concrete
+// types are only registered at runtime.
+func skipsPathCheck(tag pkg.FieldTag) bool {
+	return tag.Opts.Compute != "" || len(tag.Opts.Join) > 0 || len(tag.Opts.Kinds) > 0 || len(tag.Opts.Versions) > 0 ||
+		len(tag.Opts.In) > 0 || len(tag.Opts.Out) > 0 || len(tag.Opts.As) > 0
+}
+
+// nestedStruct returns the struct type declared by the package a field holds, directly or through pointers,
+// slices, arrays and maps, if any.
+func nestedStruct(pass *analysis.Pass, field *ast.Field) *types.Named {
+	named, ok := unwrap(pass.TypesInfo.TypeOf(field.Type)).(*types.Named)
+	if !ok || named.Obj().Pkg() != pass.Pkg {
+		return nil
+	}
+	if _, isStruct := named.Underlying().(*types.Struct); !isStruct {
+		return nil
+	}
+	return named
+}
+
+// structTag returns the tags of a field, empty when it has none.
+func structTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	literal, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(literal)
+}
+
+// seTag returns the `se` tag of a field, empty when it has none.
+func seTag(field *ast.Field) string {
+	return structTag(field).Get(pkg.FIELD_TAG_KEY)
+}
@@ -0,0 +1,67 @@
+// Command segen generates reflection-free mappers for pairs of local and foreign structs from the `se` tags of the
+// local ones, see the generator package. It's meant to be run by go generate from the package declaring the local
+// structs, with a pair of types as `Local=Foreign` for each mapping to generate:
+//
+//	//go:generate segen -o mappers_se.go SystemStruct=api.APIObject
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/ilexPar/struct-marshal/generator"
+)
+
+func main() {
+	output := flag.String("o", "mappers_se.go", "file the mappers are written to")
+	dir := flag.String("dir", ".", "directory of the package declaring the local structs")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: segen [-o file] [-dir dir] Local=Foreign...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*dir, *output, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "segen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the mappers of the pairs of types of the package found in a directory, and writes them to a file
+// of that directory.
+func run(dir, output string, specs []string) error {
+	pairs := make([]generator.Pair, 0, len(specs))
+	for _, spec := range specs {
+		pair, err := generator.ParsePair(spec)
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, pair)
+	}
+
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedTypes | packages.NeedImports, Dir: dir}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return fmt.Errorf("package in %v has errors", dir)
+	}
+
+	src, err := generator.Generate(pkgs[0].Types, pairs)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(dir, output)
+	}
+	return os.WriteFile(output, src, 0o644)
+}
@@ -0,0 +1,13 @@
+// Command sevet checks the `se` tags of local structs statically, see the analyzer package. It runs standalone,
+// eg `sevet ./...`, or through go vet with `go vet -vettool=$(which sevet) ./...`.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/ilexPar/struct-marshal/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,524 @@
+// Package generator generates reflection-free mappers from the `se` tags of local structs, the functions the segen
+// command writes. For each pair of local and foreign struct types it generates a function encoding local values into
+// foreign ones and another decoding them back, along with an init function registering them with
+// pkg.RegisterGenerated, so Marshal and Unmarshal call them instead of walking the representation of the types:
+//
+//	//go:generate segen -o mappers_se.go SystemStruct=api.APIObject
+//
+// Generated functions map fields just like the default codec does, skipping zero values, and support the subset of
+// tags whose mapping is known at build time: paths to fields of nested structs, held by value or by pointer, the
+// `=`, `->`, relative and absolute paths, nested and embedded local structs, alternative paths, and the `types<>`,
+// `readonly` and `writeonly` options.
+// Fields must hold the same type as the foreign field they map. Pairs of types relying on anything else, like indexes,
+// conversions or conditions, are rejected and keep being mapped by reflection.
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	pathpkg "path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+// importPath is the import path of the package the generated functions are registered with.
+const importPath = "github.com/ilexPar/struct-marshal/pkg"
+
+// Pair names a local struct and the foreign struct its tags map, eg `SystemStruct=api.APIObject` as parsed by
+// ParsePair.
+type Pair struct {
+	Local   string // name of a struct declared by the package the mappers are generated for
+	Foreign string // name of the foreign struct, eg `APIObject`, `api.APIObject` or `github.com/org/api.APIObject`
+}
+
+// ParsePair parses a pair of types written as `Local=Foreign`.
+func ParsePair(spec string) (Pair, error) {
+	local, foreign, ok := strings.Cut(spec, "=")
+	if !ok || local == "" || foreign == "" {
+		return Pair{}, fmt.Errorf("invalid pair %q, expected Local=Foreign", spec)
+	}
+	return Pair{Local: local, Foreign: foreign}, nil
+}
+
+// Generate returns the formatted source of a file declaring the mappers of the given pairs of types and the init
+// function registering them, to be added to the package declaring the local structs.
+//
+// Parameters:
+//   - target: The type-checked package declaring the local structs, the foreign ones may be declared by its imports
+//   - pairs: The pairs of types to generate mappers for
+//
+// Returns:
+//   - []byte: The source of the generated file
+//   - error: An error naming the first type or field the mappers can't be generated for
+func Generate(target *types.Package, pairs []Pair) ([]byte, error) {
+	g := &generator{target: target, imports: map[string]string{importPath: "se"}}
+	var registrations []string
+	for _, pair := range pairs {
+		local := lookupType(target, pair.Local, false)
+		if local == nil {
+			return nil, fmt.Errorf("local struct %v not found in %v", pair.Local, target.Path())
+		}
+		foreign := lookupType(target, pair.Foreign, true)
+		if foreign == nil {
+			return nil, fmt.Errorf("foreign struct %v not found in %v or its imports", pair.Foreign, target.Path())
+		}
+		encode, decode, err := g.mappers(local, foreign)
+		if err != nil {
+			return nil, fmt.Errorf("%v=%v: %w", pair.Local, pair.Foreign, err)
+		}
+		registrations = append(registrations, fmt.Sprintf("se.RegisterGenerated(%v, %v)", encode, decode))
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by segen. DO NOT EDIT.\n\npackage %v\n\nimport (\n", target.Name())
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		if name := g.imports[path]; name != pathpkg.Base(path) {
+			fmt.Fprintf(&src, "%v ", name)
+		}
+		fmt.Fprintf(&src, "%q\n", path)
+	}
+	fmt.Fprintf(&src, ")\n\nfunc init() {\n%v\n}\n", strings.Join(registrations, "\n"))
+	src.Write(g.body.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// generator writes the mappers of the pairs of types of a package.
+type generator struct {
+	target  *types.Package
+	imports map[string]string // name of each package imported by the generated file, by import path
+	body    bytes.Buffer
+}
+
+// step is a segment of a path to a foreign field.
+type step struct {
+	name    string
+	pointer *types.Pointer // type of the field when it holds a pointer to the struct the path goes on in, if so
+}
+
+// assignment is a local field along with the foreign fields it maps.
+type assignment struct {
+	local   []string // selector of the local field, going through nested local structs
+	foreign [][]step // main path followed by the alternative ones, decoded when the previous ones are empty
+	typ     types.Type
+	encode  bool
+	decode  bool
+}
+
+// mappers writes the functions encoding and decoding a pair of types, and returns their names.
+func (this *generator) mappers(local, foreign *types.Named) (string, string, error) {
+	assignments, err := this.assignments(local, foreign, nil, nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	localName, foreignName := local.Obj().Name(), foreign.Obj().Name()
+	if localName == foreignName {
+		foreignName = exported(foreign.Obj().Pkg().Name()) + foreignName
+	}
+	encode := "Encode" + localName + "To" + foreignName
+	decode := "Decode" + foreignName + "To" + localName
+	localType, foreignType := this.typeString(local), this.typeString(foreign)
+
+	fmt.Fprintf(&this.body, "\n// %v encodes %v values into %v values, as Marshal does with the default options.\n",
+		encode, localType, foreignType)
+	fmt.Fprintf(&this.body, "func %v(from *%v, into *%v) error {\n", encode, localType, foreignType)
+	for _, assignment := range assignments {
+		if assignment.encode {
+			this.writeEncode(assignment)
+		}
+	}
+	fmt.Fprintf(&this.body, "return nil\n}\n")
+
+	fmt.Fprintf(&this.body, "\n// %v decodes %v values into %v values, as Unmarshal does with the default options.\n",
+		decode, foreignType, localType)
+	fmt.Fprintf(&this.body, "func %v(from *%v, into *%v) error {\n", decode, foreignType, localType)
+	for _, assignment := range assignments {
+		if assignment.decode {
+			this.writeDecode(assignment)
+		}
+	}
+	fmt.Fprintf(&this.body, "return nil\n}\n")
+	return encode, decode, nil
+}
+
+// assignments returns the fields of a local struct, and of the local structs nested in it, mapping fields of a
+// foreign struct, under the path of the field holding it.
+func (this *generator) assignments(
+	local, foreign *types.Named,
+	selector []string,
+	parentPath []string,
+) ([]assignment, error) {
+	st, ok := local.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a struct", local.Obj().Name())
+	}
+	if local.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("%v is a generic type", local.Obj().Name())
+	}
+
+	assignments := []assignment{}
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		value, tagged := reflect.StructTag(st.Tag(i)).Lookup(pkg.FIELD_TAG_KEY)
+		if !tagged && squashed(field) {
+			value = pkg.DISMISS_NESTED // mapped under the path of the struct embedding it
+		}
+		if value == "" {
+			continue
+		}
+		name := strings.Join(append(slices.Clone(selector), field.Name()), ".")
+		tag := pkg.ParseTagValue(field.Name(), value)
+		if tag.Skip {
+			continue
+		}
+		if err := tag.Validate(); err != nil {
+			return nil, fmt.Errorf("field %v: %w", name, err)
+		}
+		if !field.Exported() && field.Pkg() != this.target {
+			return nil, fmt.Errorf("field %v is not exported", name)
+		}
+		if err := supported(tag); err != nil {
+			return nil, fmt.Errorf("field %v: %w", name, err)
+		}
+		paths, matched, err := tagPaths(tag, field.Name(), foreign)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %w", name, err)
+		}
+		if !matched {
+			continue
+		}
+
+		if nested, ok := field.Type().(*types.Named); ok && taggedStruct(nested) {
+			if len(paths) > 1 {
+				return nil, fmt.Errorf("field %v: alternative paths of nested structs are not supported", name)
+			}
+			nestedPath, err := composePath(parentPath, paths[0])
+			if err != nil {
+				return nil, fmt.Errorf("field %v: %w", name, err)
+			}
+			children, err := this.assignments(nested, foreign, append(slices.Clone(selector), field.Name()), nestedPath)
+			if err != nil {
+				return nil, err
+			}
+			for i := range children {
+				children[i].encode = children[i].encode && !tag.Opts.ReadOnly
+				children[i].decode = children[i].decode && !tag.Opts.WriteOnly
+			}
+			assignments = append(assignments, children...)
+			continue
+		}
+
+		result := assignment{
+			local:  append(slices.Clone(selector), field.Name()),
+			typ:    field.Type(),
+			encode: !tag.Opts.ReadOnly,
+			decode: !tag.Opts.WriteOnly,
+		}
+		if _, err := this.nonZero("", field.Type()); err != nil {
+			return nil, fmt.Errorf("field %v: %w", name, err)
+		}
+		for _, path := range paths {
+			fullPath, err := composePath(parentPath, path)
+			if err != nil {
+				return nil, fmt.Errorf("field %v: %w", name, err)
+			}
+			steps, target, err := resolvePath(foreign, fullPath)
+			if err != nil {
+				return nil, fmt.Errorf("field %v: %w", name, err)
+			}
+			if !types.Identical(target, field.Type()) {
+				return nil, fmt.Errorf("field %v of type %v maps a foreign field of type %v", name, field.Type(), target)
+			}
+			result.foreign = append(result.foreign, steps)
+		}
+		assignments = append(assignments, result)
+	}
+	return assignments, nil
+}
+
+// supported checks that a tag only sets the options generated mappers support.
+func supported(tag pkg.FieldTag) error {
+	opts := tag.Opts
+	opts.MatchTypes, opts.ReadOnly, opts.WriteOnly = nil, false, false
+	if !reflect.DeepEqual(opts, pkg.TagOpts{}) {
+		return fmt.Errorf("tag options other than types<>, readonly and writeonly are not supported")
+	}
+	return nil
+}
+
+// tagPaths returns the paths a tag maps in a foreign type, the main path followed by the alternative ones, or false
+// when its `types<>` option doesn't match the type.
+func tagPaths(tag pkg.FieldTag, fieldName string, foreign *types.Named) ([][]string, bool, error) {
+	paths := append([][]string{tag.Path}, tag.Alternatives...)
+	if len(tag.Opts.MatchTypes) > 0 {
+		match, ok := matchType(tag.Opts.MatchTypes, foreign)
+		if !ok {
+			return nil, false, nil
+		}
+		if len(match.Path) > 0 {
+			paths = [][]string{match.Path}
+		}
+	}
+	for i, path := range paths {
+		switch path[0] {
+		case pkg.MULTI_TYPE_NAME:
+			return nil, false, fmt.Errorf("no path set for %v", foreign.Obj().Name())
+		case pkg.SAME_NAME:
+			paths[i] = []string{fieldName}
+		}
+	}
+	return paths, true, nil
+}
+
+// matchType returns the most specific `types<>` entry matching a foreign type, as the mapping does: entries naming
+// the type by import path win over the ones naming it by package, which win over the ones using its bare name, which
+// win over patterns.
+func matchType(matches []pkg.TypeMatch, foreign *types.Named) (pkg.TypeMatch, bool) {
+	obj := foreign.Obj()
+	names := []string{obj.Pkg().Path() + "." + obj.Name(), obj.Pkg().Name() + "." + obj.Name(), obj.Name()}
+	best, bestRank := pkg.TypeMatch{}, -1
+	for _, match := range matches {
+		rank := -1
+		if pattern, ok := strings.CutPrefix(match.Name, pkg.TYPE_PATTERN_PREFIX); ok {
+			if re, err := regexp.Compile(pattern); err == nil && slices.ContainsFunc(names, re.MatchString) {
+				rank = 0
+			}
+		} else if i := slices.Index(names, match.Name); i >= 0 {
+			rank = len(names) - i
+		}
+		if rank > bestRank {
+			best, bestRank = match, rank
+		}
+	}
+	return best, bestRank >= 0
+}
+
+// composePath composes the path of a tag with the path inherited from the field holding the struct, as the mapping
+// does: `->` keeps the inherited path, absolute paths start from the foreign struct, and each `^` leading relative
+// paths strips a segment of the inherited path.
+func composePath(parentPath, path []string) ([]string, error) {
+	switch path[0] {
+	case pkg.DISMISS_NESTED:
+		return parentPath, nil
+	case pkg.ABSOLUTE_PATH:
+		return path[1:], nil
+	}
+	kept := len(parentPath)
+	for len(path) > 0 && path[0] == pkg.PARENT_PATH {
+		if kept == 0 {
+			return nil, fmt.Errorf("no segment of %q left to strip", strings.Join(parentPath, "."))
+		}
+		kept, path = kept-1, path[1:]
+	}
+	return append(slices.Clone(parentPath[:kept]), path...), nil
+}
+
+// resolvePath returns the steps leading to the field a path names in a foreign struct, going through nested structs
+// held by value or by pointer, along with the type of the field.
+func resolvePath(foreign *types.Named, path []string) ([]step, types.Type, error) {
+	steps := make([]step, 0, len(path))
+	var current types.Type = foreign
+	for i, segment := range path {
+		if i > 0 {
+			if ptr, ok := current.Underlying().(*types.Pointer); ok {
+				steps[i-1].pointer = ptr
+				current = ptr.Elem()
+			}
+		}
+		st, ok := current.Underlying().(*types.Struct)
+		if !ok {
+			return nil, nil, fmt.Errorf("path %v goes through a %v", strings.Join(path, "."), current)
+		}
+		if strings.Contains(segment, "[") {
+			return nil, nil, fmt.Errorf("indexed path %v is not supported", strings.Join(path, "."))
+		}
+		var found *types.Var
+		for j := range st.NumFields() {
+			if st.Field(j).Name() == segment {
+				found = st.Field(j)
+			}
+		}
+		if found == nil {
+			return nil, nil, fmt.Errorf("path %v not found in %v", strings.Join(path, "."), foreign.Obj().Name())
+		}
+		if !found.Exported() && found.Pkg() != foreign.Obj().Pkg() {
+			return nil, nil, fmt.Errorf("path %v goes through unexported field %v", strings.Join(path, "."), segment)
+		}
+		steps = append(steps, step{name: segment})
+		current = found.Type()
+	}
+	return steps, current, nil
+}
+
+// writeEncode writes the statements copying a local field into the main foreign path it maps, allocating the
+// structs held by pointers along the path, unless the local field holds a zero value.
+func (this *generator) writeEncode(assignment assignment) {
+	source := "from." + strings.Join(assignment.local, ".")
+	condition, _ := this.nonZero(source, assignment.typ)
+	fmt.Fprintf(&this.body, "if %v {\n", condition)
+	target := "into"
+	for _, step := range assignment.foreign[0] {
+		target += "." + step.name
+		if step.pointer != nil {
+			fmt.Fprintf(&this.body, "if %v == nil {\n%v = new(%v)\n}\n", target, target, this.typeString(step.pointer.Elem()))
+		}
+	}
+	fmt.Fprintf(&this.body, "%v = %v\n}\n", target, source)
+}
+
+// writeDecode writes the statements copying the first foreign field holding a value other than the zero one, among
+// the paths a local field maps, into the local field. Paths going through nil pointers are skipped.
+func (this *generator) writeDecode(assignment assignment) {
+	target := "into." + strings.Join(assignment.local, ".")
+	for i, steps := range assignment.foreign {
+		var conditions []string
+		source := "from"
+		for _, step := range steps {
+			source += "." + step.name
+			if step.pointer != nil {
+				conditions = append(conditions, source+" != nil")
+			}
+		}
+		condition, _ := this.nonZero(source, assignment.typ)
+		conditions = append(conditions, condition)
+		if i > 0 {
+			fmt.Fprintf(&this.body, "} else ")
+		}
+		fmt.Fprintf(&this.body, "if %v {\n%v = %v\n", strings.Join(conditions, " && "), target, source)
+	}
+	fmt.Fprintf(&this.body, "}\n")
+}
+
+// nonZero returns the expression checking that a value of a type is not the zero one, failing for types whose
+// values can't be compared with it.
+func (this *generator) nonZero(expr string, typ types.Type) (string, error) {
+	switch underlying := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case underlying.Info()&types.IsBoolean != 0:
+			return expr, nil
+		case underlying.Info()&types.IsString != 0:
+			return expr + ` != ""`, nil
+		case underlying.Info()&types.IsNumeric != 0:
+			return expr + " != 0", nil
+		case underlying.Kind() == types.UnsafePointer:
+			return expr + " != nil", nil
+		}
+	case *types.Pointer:
+		return "", fmt.Errorf("pointer fields are not supported")
+	case *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return expr + " != nil", nil
+	case *types.Struct, *types.Array:
+		if types.Comparable(typ) {
+			return fmt.Sprintf("%v != (%v{})", expr, this.typeString(typ)), nil
+		}
+	}
+	return "", fmt.Errorf("values of type %v can't be compared with the zero value", typ)
+}
+
+// typeString returns the expression naming a type in the generated file, importing the packages it refers to.
+func (this *generator) typeString(typ types.Type) string {
+	return types.TypeString(typ, func(other *types.Package) string {
+		if other == this.target {
+			return ""
+		}
+		if name, ok := this.imports[other.Path()]; ok {
+			return name
+		}
+		name := other.Name()
+		for suffix := 2; slices.Contains(importNames(this.imports), name); suffix++ {
+			name = other.Name() + strconv.Itoa(suffix)
+		}
+		this.imports[other.Path()] = name
+		return name
+	})
+}
+
+// importNames returns the names of the packages imported by the generated file.
+func importNames(imports map[string]string) []string {
+	names := make([]string, 0, len(imports))
+	for _, name := range imports {
+		names = append(names, name)
+	}
+	return names
+}
+
+// taggedStruct reports whether a type is a struct holding at least one field with an `se` tag, whose fields are
+// mapped on their own instead of being copied as a whole.
+func taggedStruct(named *types.Named) bool {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := range st.NumFields() {
+		if reflect.StructTag(st.Tag(i)).Get(pkg.FIELD_TAG_KEY) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// squashed reports whether an untagged field embeds a struct holding tagged fields, or a pointer to it, whose fields
+// are mapped as if declared by the struct embedding it. Embedded structs without tagged fields map nothing.
+func squashed(field *types.Var) bool {
+	if !field.Embedded() {
+		return false
+	}
+	typ := field.Type()
+	if ptr, ok := typ.(*types.Pointer); ok {
+		if !field.Exported() {
+			return false
+		}
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	return ok && taggedStruct(named)
+}
+
+// lookupType finds the struct type named by a pair, eg `Deployment`, `api.Deployment` or
+// `github.com/org/api.Deployment`, among the ones declared by a package or, for foreign types, the ones it imports.
+func lookupType(target *types.Package, name string, imported bool) *types.Named {
+	qualifier, typeName := "", name
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		qualifier, typeName = name[:dot], name[dot+1:]
+	}
+	scope := []*types.Package{target}
+	if imported {
+		scope = append(scope, target.Imports()...)
+	}
+	for _, scopePkg := range scope {
+		if qualifier != "" && qualifier != scopePkg.Name() && qualifier != scopePkg.Path() {
+			continue
+		}
+		obj, ok := scopePkg.Scope().Lookup(typeName).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if named, ok := obj.Type().(*types.Named); ok {
+			if _, isStruct := named.Underlying().(*types.Struct); isStruct {
+				return named
+			}
+		}
+	}
+	return nil
+}
+
+// exported returns a name with its first letter upper cased.
+func exported(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
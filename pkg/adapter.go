@@ -0,0 +1,128 @@
+package pkg
+
+import (
+	"reflect"
+)
+
+// adapter maps values of a pair of local and foreign types with plain functions instead of walking their
+// representation, see RegisterAdapter.
+type adapter struct {
+	encode func(local, foreign any) error // receives pointers to the local and foreign values
+	decode func(foreign, local any) error // receives pointers to the foreign and local values
+}
+
+// RegisterAdapter registers a pair of hand-written functions mapping values of a local and a foreign struct type,
+// so Marshal and Unmarshal call them instead of walking the representation of the types, eg for hot pairs of types
+// whose mapping shows up in profiles. Registering a pair of types again replaces the previous functions.
+//
+// Adapters implement the default mapping only: codecs created with options changing which fields are mapped, or
+// how, or observing the skipped ones, keep walking the representation. Lifecycle hooks run around the functions just
+// like around the walk. Either function may be nil, leaving that direction to the walk.
+//
+// Parameters:
+//   - localType: The local struct type, or a pointer to it
+//   - foreignType: The foreign struct type, or a pointer to it
+//   - encode: The function encoding a local value into a foreign one, receiving pointers to both
+//   - decode: The function decoding a foreign value into a local one, receiving pointers to both
+//
+// Returns:
+//   - error: ErrLocalTypeNotStruct or ErrForeignTypeNotStruct when the types aren't structs
+func RegisterAdapter(
+	localType, foreignType reflect.Type,
+	encode func(from, into any) error,
+	decode func(from, into any) error,
+) error {
+	if localType == nil || derefType(localType).Kind() != reflect.Struct {
+		return ErrLocalTypeNotStruct
+	}
+	if foreignType == nil || derefType(foreignType).Kind() != reflect.Struct {
+		return ErrForeignTypeNotStruct
+	}
+	registerAdapter(derefType(localType), derefType(foreignType), adapter{encode: encode, decode: decode})
+	return nil
+}
+
+// RegisterGenerated registers the functions mapping a local type L and a foreign type F generated by the segen
+// command, see RegisterAdapter. Generated files register their functions from an init function, there's usually no
+// need to call it by hand.
+//
+// Parameters:
+//   - encode: The function encoding a local value into a foreign one
+//   - decode: The function decoding a foreign value into a local one
+func RegisterGenerated[L, F any](encode func(from *L, into *F) error, decode func(from *F, into *L) error) {
+	registerAdapter(reflect.TypeFor[L](), reflect.TypeFor[F](), adapter{
+		encode: func(local, foreign any) error { return encode(local.(*L), foreign.(*F)) },
+		decode: func(foreign, local any) error { return decode(foreign.(*F), local.(*L)) },
+	})
+}
+
+// registerAdapter registers the adapter of a pair of local and foreign struct types.
+func registerAdapter(local, foreign reflect.Type, adapter adapter) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.adapters[[2]reflect.Type{local, foreign}] = adapter
+}
+
+// lookupAdapter returns the adapter registered for the types of a local and a foreign value, given by value or
+// pointer, if any.
+func lookupAdapter(local, foreign interface{}) (adapter, bool) {
+	if local == nil || foreign == nil {
+		return adapter{}, false
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	found, ok := registry.adapters[[2]reflect.Type{derefType(reflect.TypeOf(local)), derefType(reflect.TypeOf(foreign))}]
+	return found, ok
+}
+
+// adapt encodes the local value into the foreign one with a registered adapter, running the same hooks run does.
+//
+// Parameters:
+//   - local: The source struct or pointer to struct containing the data to be encoded
+//   - foreign: A pointer to the destination struct where data will be encoded to
+//   - adapter: The adapter registered for their types
+//
+// Returns:
+//   - error: Any validation error, or the error returned by the hooks or the adapter
+func (this *StructEncoder) adapt(local, foreign interface{}, adapter adapter) (err error) {
+	defer recoverPanic(&err, &this.local, &this.current)
+	this.local = reflect.ValueOf(local)
+	this.foreign = reflect.ValueOf(foreign)
+	if err := this.validateInput(); err != nil {
+		return err
+	}
+
+	if err := this.beforeMarshal(); err != nil {
+		return err
+	}
+	if err := adapter.encode(addressable(this.local).Interface(), foreign); err != nil {
+		return err
+	}
+	return this.afterMarshal()
+}
+
+// adapt decodes the foreign value into the local one with a registered adapter, running the same hooks run does.
+//
+// Parameters:
+//   - foreign: The source struct or pointer to struct containing the data to be decoded
+//   - local: A pointer to the destination struct where data will be decoded to
+//   - adapter: The adapter registered for their types
+//
+// Returns:
+//   - error: Any validation error, or the error returned by the hooks or the adapter
+func (this *StructDecoder) adapt(foreign, local interface{}, adapter adapter) (err error) {
+	defer recoverPanic(&err, &this.local, &this.current)
+	this.local = reflect.ValueOf(local)
+	this.foreign = reflect.ValueOf(foreign)
+	if err := this.validateInput(); err != nil {
+		return err
+	}
+
+	if err := this.beforeUnmarshal(); err != nil {
+		return err
+	}
+	if err := adapter.decode(addressable(this.foreign).Interface(), local); err != nil {
+		return err
+	}
+	return this.afterUnmarshal()
+}
@@ -1,23 +1,323 @@
 package pkg
 
-var localRepresentations map[string]StructRepr
+import (
+	"expvar"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
-var foreignRepresentations map[string]TargetField
+// typeCache holds the representations introspected for pairs of local and foreign types, along with the
+// fingerprints of the types involved. Codecs share a single cache unless created with WithPrivateCache.
+//
+// Maps handed out by representations are never written again: introspections missing the cache hold the lock
+// while writing into copies of them, see update, so encoders and decoders read the representations they took
+// without locking.
+type typeCache struct {
+	mu            sync.RWMutex // guards the representation maps and the imported ones
+	locals        map[string]StructRepr
+	foreigns      map[string]TargetField
+	handedOut     atomic.Bool   // whether the current maps were handed out and must be copied before writing them
+	generation    atomic.Uint64 // registry generation the representations were introspected at
+	fingerprintMu sync.Mutex    // guards the fingerprints, computed for the keys looked up without holding mu
+	fingerprints  map[reflect.Type]string
+	imported      representations // read by ImportCache, moved into the cache once their types are requested
+	hits          atomic.Uint64   // lookups finding the representation of a pair of types
+	misses        atomic.Uint64   // lookups requiring the pair of types to be introspected
+	elapsed       atomic.Int64    // nanoseconds spent introspecting the pairs of types missing
+}
+
+// sharedCache is the cache used by the package-level functions and by every codec without a private cache.
+var sharedCache = newTypeCache()
+
+func newTypeCache() *typeCache {
+	cache := &typeCache{}
+	cache.clear()
+	return cache
+}
+
+// clear empties the cache and resets its statistics, replacing its maps so representations taken from it before
+// keep working.
+func (this *typeCache) clear() {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.fingerprintMu.Lock()
+	defer this.fingerprintMu.Unlock()
+	this.locals = map[string]StructRepr{}
+	this.foreigns = map[string]TargetField{}
+	this.handedOut.Store(false)
+	this.generation.Store(registry.generation.Load())
+	this.fingerprints = map[reflect.Type]string{}
+	this.imported = representations{locals: map[string]StructRepr{}, foreigns: map[string]TargetField{}}
+	this.hits.Store(0)
+	this.misses.Store(0)
+	this.elapsed.Store(0)
+}
+
+// merge adds the representations, fingerprints and statistics of another cache, no longer used by anyone else, to
+// the cache.
+func (this *typeCache) merge(other *typeCache) {
+	this.refresh()
+	_ = this.update(func() error {
+		maps.Copy(this.locals, other.locals)
+		maps.Copy(this.foreigns, other.foreigns)
+		return nil
+	})
+	this.fingerprintMu.Lock()
+	maps.Copy(this.fingerprints, other.fingerprints)
+	this.fingerprintMu.Unlock()
+	this.hits.Add(other.hits.Load())
+	this.misses.Add(other.misses.Load())
+	this.elapsed.Add(other.elapsed.Load())
+}
+
+// refresh drops the representations introspected before the latest registration changing how types are
+// introspected, see invalidateRepresentations. Maps handed out by representations are left untouched.
+func (this *typeCache) refresh() {
+	generation := registry.generation.Load()
+	if this.generation.Load() == generation {
+		return
+	}
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if this.generation.Load() != generation {
+		this.locals = map[string]StructRepr{}
+		this.foreigns = map[string]TargetField{}
+		this.handedOut.Store(false)
+		this.generation.Store(generation)
+	}
+}
+
+// update runs a function writing into the representation maps of the cache while holding its lock, on copies of
+// the maps when the current ones were handed out, so representations taken before never change. The function must
+// access the maps directly, without calling the methods locking the cache.
+func (this *typeCache) update(write func() error) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if this.handedOut.Swap(false) {
+		this.locals = maps.Clone(this.locals)
+		this.foreigns = maps.Clone(this.foreigns)
+	}
+	return write()
+}
+
+// lookup returns the cached representation of the given key, if any.
+func (this *typeCache) lookup(key string) (StructRepr, bool) {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	repr, ok := this.locals[key]
+	return repr, ok
+}
+
+// held returns the representations held by the cache to the functions run by update, still being written.
+func (this *typeCache) held() representations {
+	return representations{locals: this.locals, foreigns: this.foreigns}
+}
+
+// representations returns the representations held by the cache, which are never written again, see update.
+func (this *typeCache) representations() representations {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	this.handedOut.Store(true)
+	return representations{locals: this.locals, foreigns: this.foreigns}
+}
+
+// representations holds the local and foreign representations encoders and decoders resolve the references of
+// fields with: the cached ones, or the private ones carried by a CompiledMapper.
+type representations struct {
+	locals   map[string]StructRepr
+	foreigns map[string]TargetField
+}
+
+// snapshot copies the representations referenced by the fields of a representation, deep through its children,
+// so they can be used without depending on the introspection cache.
+func (this representations) snapshot(repr StructRepr) representations {
+	copied := representations{locals: map[string]StructRepr{}, foreigns: map[string]TargetField{}}
+	this.copyReferenced(repr, copied)
+	return copied
+}
 
-func cacheInit() {
-	if localRepresentations == nil {
-		localRepresentations = map[string]StructRepr{}
+// copyReferenced copies into dst the foreign fields referenced by the fields of a representation, and the
+// representations of its children along with the foreign fields they reference.
+func (this representations) copyReferenced(repr StructRepr, dst representations) {
+	for _, field := range repr.Fields {
+		for _, ref := range append([]string{field.TargetRef, field.ConditionRef}, field.JoinRefs...) {
+			if target, ok := this.foreigns[ref]; ok {
+				dst.foreigns[ref] = target
+			}
+		}
+		child, ok := this.locals[field.ChildRef]
+		if _, copied := dst.locals[field.ChildRef]; !ok || copied {
+			continue
+		}
+		dst.locals[field.ChildRef] = child
+		this.copyReferenced(child, dst)
 	}
-	if foreignRepresentations == nil {
-		foreignRepresentations = map[string]TargetField{}
+}
+
+// hasField reports whether a local field, named by its path without the indexes and keys of slices and maps, is
+// mapped or dismissed by a representation or the representations of its children.
+func (this representations) hasField(repr StructRepr, path string) bool {
+	name, rest, nested := strings.Cut(path, ".")
+	for _, fields := range [][]SourceField{repr.Fields, repr.Unmatched} {
+		for _, field := range fields {
+			if field.Name != name {
+				continue
+			}
+			if !nested {
+				return true
+			}
+			if child, ok := this.child(field); ok && this.hasField(child, rest) {
+				return true
+			}
+		}
 	}
+	return false
 }
 
-// ClearTypeCache empties the internal cache of type representations,
-// resetting both localRepresentations and foreignRepresentations maps to empty maps.
+// ClearTypeCache empties the internal cache of type representations shared by the package-level functions and by
+// every Codec created without WithPrivateCache, along with the type fingerprints.
 // This can be useful when the type information needs to be refreshed or when
 // freeing up memory in long-running applications.
 func ClearTypeCache() {
-	localRepresentations = map[string]StructRepr{}
-	foreignRepresentations = map[string]TargetField{}
+	sharedCache.clear()
+}
+
+// ClearTypeCache empties the cache of type representations of the codec, see the package-level ClearTypeCache.
+// For codecs created with WithPrivateCache only their own cache is emptied, otherwise the shared one is.
+func (this *Codec) ClearTypeCache() {
+	this.opts.cache.clear()
+}
+
+// CacheStatistics describes how the introspection cache has been used since the process started, or since it was last
+// cleared: how many lookups found the representation of a pair of types and how many required introspecting them,
+// along with the time spent doing so.
+type CacheStatistics struct {
+	LocalEntries      int
+	ForeignEntries    int
+	Hits              uint64
+	Misses            uint64
+	IntrospectionTime time.Duration
+}
+
+// CacheStats returns the statistics of the shared introspection cache, so operators can monitor how much time is
+// spent introspecting types in production. See PublishCacheStats to export them with the expvar package.
+func CacheStats() CacheStatistics {
+	return sharedCache.stats()
+}
+
+// CacheStats returns the statistics of the cache of the codec, see the package-level CacheStats.
+func (this *Codec) CacheStats() CacheStatistics {
+	return this.opts.cache.stats()
+}
+
+// PublishCacheStats exports the statistics of the shared introspection cache as an expvar variable of the given
+// name, served along with the other variables of the process at /debug/vars. Like expvar.Publish, it panics when
+// the name is already in use.
+func PublishCacheStats(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return CacheStats() }))
+}
+
+// stats returns the statistics of the cache, see CacheStats.
+func (this *typeCache) stats() CacheStatistics {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	return CacheStatistics{
+		LocalEntries:      len(this.locals),
+		ForeignEntries:    len(this.foreigns),
+		Hits:              this.hits.Load(),
+		Misses:            this.misses.Load(),
+		IntrospectionTime: time.Duration(this.elapsed.Load()),
+	}
+}
+
+// CacheUsage describes how much memory is held by the introspection cache.
+// ApproxBytes is an estimate accounting for the cached structures, their keys, names and paths,
+// it doesn't include the overhead of the maps themselves nor memory owned by the reflect package.
+type CacheUsage struct {
+	LocalEntries       int
+	ForeignEntries     int
+	FingerprintEntries int
+	ApproxBytes        int
+}
+
+// CacheMemoryUsage returns an estimate of the memory held by the shared introspection cache, so operators of
+// long-running processes can decide when to call ClearTypeCache.
+func CacheMemoryUsage() CacheUsage {
+	return sharedCache.usage()
+}
+
+// CacheMemoryUsage returns an estimate of the memory held by the cache of the codec, see the package-level
+// CacheMemoryUsage.
+func (this *Codec) CacheMemoryUsage() CacheUsage {
+	return this.opts.cache.usage()
+}
+
+// usage estimates the memory held by the cache, see CacheMemoryUsage.
+func (this *typeCache) usage() CacheUsage {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	this.fingerprintMu.Lock()
+	defer this.fingerprintMu.Unlock()
+	usage := CacheUsage{
+		LocalEntries:       len(this.locals),
+		ForeignEntries:     len(this.foreigns),
+		FingerprintEntries: len(this.fingerprints),
+	}
+
+	for key, repr := range this.locals {
+		usage.ApproxBytes += len(key) + structReprSize(repr)
+	}
+	for key, target := range this.foreigns {
+		usage.ApproxBytes += len(key) + targetFieldSize(target)
+	}
+	for _, fingerprint := range this.fingerprints {
+		usage.ApproxBytes += int(reflect.TypeOf((*reflect.Type)(nil)).Elem().Size()) + len(fingerprint)
+	}
+
+	return usage
+}
+
+func structReprSize(repr StructRepr) int {
+	size := int(reflect.TypeOf(repr).Size()) + len(repr.ForeignRootType)
+	for _, field := range slices.Concat(repr.Fields, repr.Unmatched) {
+		size += int(reflect.TypeOf(field).Size()) + len(field.Name) + len(field.ChildRef) + len(field.TargetRef)
+		size += len(field.Tag.TargetType) + stringsSize(field.Tag.Path) + len(field.Tag.Match.Name)
+		size += stringsSize(field.Tag.Match.Path)
+		size += len(field.ConditionRef) + len(field.Tag.Opts.MergeKey) + len(field.Tag.Opts.Const)
+		size += len(field.Tag.Opts.Compute) + len(field.Tag.Opts.Convert) + len(field.Tag.Opts.TimeLayout)
+		size += stringsSize(field.Tag.Opts.In) + stringsSize(field.Tag.Opts.Out)
+		size += stringsSize(field.JoinRefs)
+		for _, part := range field.Tag.Opts.Join {
+			size += int(reflect.TypeOf(part).Size()) + stringsSize(part.Path) + len(part.Literal)
+		}
+		if when := field.Tag.Opts.When; when != nil {
+			size += int(reflect.TypeOf(*when).Size()) + stringsSize(when.Path) + len(when.Value)
+		}
+		for _, match := range field.Tag.Opts.MatchTypes {
+			size += int(reflect.TypeOf(match).Size()) + len(match.Name) + stringsSize(match.Path)
+		}
+	}
+	return size
+}
+
+func targetFieldSize(target TargetField) int {
+	size := int(reflect.TypeOf(target).Size()) + len(target.TypeName) + stringsSize(target.Path)
+	size += len(target.IndexPath) * int(reflect.TypeOf(0).Size())
+	for _, elements := range target.Elements {
+		size += int(reflect.TypeOf(elements).Size()) + len(elements)*int(reflect.TypeOf(0).Size())
+	}
+	return size
+}
+
+func stringsSize(values []string) int {
+	size := len(values) * int(reflect.TypeOf("").Size())
+	for _, value := range values {
+		size += len(value)
+	}
+	return size
 }
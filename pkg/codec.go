@@ -0,0 +1,162 @@
+package pkg
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Mapper describes the translation layer between local and foreign structs.
+// Services can depend on this interface and receive a *Codec, so the translation can be mocked in unit tests
+// instead of calling the package-level functions.
+type Mapper interface {
+	Marshal(from interface{}, into interface{}) error
+	Unmarshal(from interface{}, into interface{}) error
+}
+
+// Codec is the package's Mapper implementation, the package-level Marshal and Unmarshal functions
+// delegate to a default Codec.
+type Codec struct {
+	opts *options
+}
+
+var _ Mapper = (*Codec)(nil)
+
+var defaultCodec = NewCodec()
+
+// NewCodec creates a new Codec ready to be used or injected as a Mapper.
+// Its behavior can be tuned by providing options, e.g. NewCodec(WithLogger(slog.Default())).
+func NewCodec(opts ...Option) *Codec {
+	return &Codec{opts: newOptions(opts...)}
+}
+
+// Unmarshal decodes a source object into a destination object, see the package-level Unmarshal.
+func (this *Codec) Unmarshal(from interface{}, into interface{}) error {
+	_, _, err := this.unmarshal(from, into, this.opts.strict, false)
+	return err
+}
+
+// unmarshal decodes a source object, or every source object of a []any, into a destination object, keeping track
+// of the fields that could not be transferred and of the populated ones when requested. Keeping track of either
+// rules out the adapter registered for the types, if any, just like WithStrict does.
+//
+// Parameters:
+//   - from: The source object, or a []any holding several ones
+//   - into: The destination object
+//   - recordSkips: Whether to keep track of the fields that could not be transferred
+//   - recordPresence: Whether to keep track of the local fields populated from the source objects
+//
+// Returns:
+//   - []FieldSkip: The fields that could not be transferred when recordSkips is set
+//   - FieldSet: The fields populated from any of the source objects when recordPresence is set
+//   - error: Any error found decoding, a *StrictError when the codec was created with WithStrict and any field was
+//     skipped
+func (this *Codec) unmarshal(
+	from interface{},
+	into interface{},
+	recordSkips, recordPresence bool,
+) ([]FieldSkip, FieldSet, error) {
+	if sources, ok := from.([]interface{}); ok {
+		return this.unmarshalAll(sources, into, recordSkips, recordPresence)
+	}
+	decoder := acquireDecoder(this.opts, recordSkips)
+	defer decoder.release()
+	decoder.recordPresence = recordPresence
+	adapter, ok := lookupAdapter(into, from)
+	if ok && adapter.decode != nil && this.opts.adaptable() && !recordSkips && !recordPresence {
+		return nil, nil, decoder.adapt(from, into, adapter)
+	}
+
+	if err := decoder.init(from, into); err != nil {
+		return nil, nil, err
+	}
+
+	if err := decoder.run(); err != nil {
+		return nil, nil, err
+	}
+	return decoder.skips, decoder.presence, this.opts.strictError(decoder.skips)
+}
+
+// Marshal encodes a source object into a destination object, see the package-level Marshal.
+func (this *Codec) Marshal(from interface{}, into interface{}) error {
+	_, err := this.marshal(from, into, this.opts.strict)
+	return err
+}
+
+// marshal encodes a source object into a destination object, keeping track of the fields that could not be
+// transferred when requested. Keeping track of them rules out the adapter registered for the types, if any, just
+// like WithStrict does.
+//
+// Parameters:
+//   - from: The source object
+//   - into: The destination object
+//   - recordSkips: Whether to keep track of the fields that could not be transferred
+//
+// Returns:
+//   - []FieldSkip: The fields that could not be transferred when recordSkips is set
+//   - error: Any error found encoding, a *StrictError when the codec was created with WithStrict and any field was
+//     skipped
+func (this *Codec) marshal(from interface{}, into interface{}, recordSkips bool) ([]FieldSkip, error) {
+	encoder := acquireEncoder(this.opts, recordSkips)
+	defer encoder.release()
+	adapter, ok := lookupAdapter(from, into)
+	if ok && adapter.encode != nil && this.opts.adaptable() && !recordSkips {
+		return nil, encoder.adapt(from, into, adapter)
+	}
+
+	if err := encoder.init(from, into); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.run(); err != nil {
+		return nil, err
+	}
+	return encoder.skips, this.opts.strictError(encoder.skips)
+}
+
+// unmarshalAll decodes every source object into the same destination object, in order, returning the first error
+// along with the position and type of the source that failed. The fields skipped decoding every source, and the
+// ones populated from any of them, are kept track of as requested, see unmarshal.
+func (this *Codec) unmarshalAll(
+	sources []interface{},
+	into interface{},
+	recordSkips, recordPresence bool,
+) ([]FieldSkip, FieldSet, error) {
+	var skips []FieldSkip
+	var presence FieldSet
+	if recordPresence {
+		presence = FieldSet{}
+	}
+	for i, src := range sources {
+		skipped, populated, err := this.unmarshal(src, into, recordSkips, recordPresence)
+		if err != nil {
+			return nil, nil, fmt.Errorf("source %v (%T): %w", i, src, err)
+		}
+		skips = append(skips, skipped...)
+		maps.Copy(presence, populated)
+	}
+	return skips, presence, nil
+}
+
+// MarshalAll encodes a source object into every destination object, see the package-level MarshalAll.
+func (this *Codec) MarshalAll(from interface{}, into ...interface{}) error {
+	for i, dst := range into {
+		if err := this.Marshal(from, dst); err != nil {
+			return fmt.Errorf("destination %v (%T): %w", i, dst, err)
+		}
+	}
+	return nil
+}
+
+// AutoMapped introspects the provided local and foreign objects and returns the set of local fields that were
+// mapped to the foreign field of the same name because the codec was created with WithAutoMap, leaving out
+// the ones explicitly tagged. It helps reviewing what an auto-mapping codec is going to transfer.
+func (this *Codec) AutoMapped(local, foreign interface{}) (FieldSet, error) {
+	repr := &StructRepr{}
+	if err := repr.introspect(local, foreign, this.opts); err != nil {
+		return nil, err
+	}
+
+	fields := FieldSet{}
+	collectAutoMappedFields(*repr, "", fields, this.opts.cache.representations().locals)
+	return fields, nil
+}
@@ -0,0 +1,290 @@
+package pkg
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"sync"
+)
+
+// MarshalSlice encodes every element of a slice of local structs into a newly allocated slice of foreign structs.
+// Introspection and input validation are performed only once for the L/F type pair, then the same representation
+// is used to map every element, which is considerably cheaper than calling Marshal in a loop.
+// Large batches can be split among goroutines with the WithParallelism option.
+//
+// Parameters:
+//   - src: The slice of local structs to encode
+//   - opts: The options tuning the encoding, see Option
+//
+// Returns:
+//   - []F: A slice with the same length as src holding the encoded foreign structs
+//   - error: An error if the types are invalid or if encoding any of the elements fails
+func MarshalSlice[L, F any](src []L, opts ...Option) ([]F, error) {
+	var local L
+	var foreign F
+	o := newOptions(opts...)
+	prototype := &StructEncoder{opts: o}
+	if err := prototype.init(&local, &foreign); err != nil {
+		return nil, err
+	}
+	if o.parallelism > 1 {
+		prototype.reprs = prototype.reprs.snapshot(*prototype.representation)
+	}
+
+	out := make([]F, len(src))
+	err := mapElements(len(src), o.parallelism, func() func(int) error {
+		encoder := *prototype
+		encoder.recordSkips = o.strict
+		return func(i int) error {
+			encoder.local = reflect.ValueOf(&src[i])
+			encoder.foreign = reflect.ValueOf(&out[i])
+			if err := encoder.run(); err != nil {
+				return err
+			}
+			return o.strictError(encoder.skips)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// UnmarshalSlice decodes every element of a slice of foreign structs into a newly allocated slice of local structs.
+// Introspection and input validation are performed only once for the L/F type pair, then the same representation
+// is used to map every element, which is considerably cheaper than calling Unmarshal in a loop.
+// Large batches can be split among goroutines with the WithParallelism option.
+//
+// Parameters:
+//   - src: The slice of foreign structs to decode
+//   - opts: The options tuning the decoding, see Option
+//
+// Returns:
+//   - []L: A slice with the same length as src holding the decoded local structs
+//   - error: An error if the types are invalid or if decoding any of the elements fails
+func UnmarshalSlice[L, F any](src []F, opts ...Option) ([]L, error) {
+	var local L
+	var foreign F
+	o := newOptions(opts...)
+	prototype := &StructDecoder{opts: o}
+	if err := prototype.init(&foreign, &local); err != nil {
+		return nil, err
+	}
+	if o.parallelism > 1 {
+		prototype.reprs = prototype.reprs.snapshot(*prototype.representation)
+	}
+
+	out := make([]L, len(src))
+	err := mapElements(len(src), o.parallelism, func() func(int) error {
+		decoder := *prototype
+		decoder.recordSkips = o.strict
+		return func(i int) error {
+			decoder.foreign = reflect.ValueOf(&src[i])
+			decoder.local = reflect.ValueOf(&out[i])
+			if err := decoder.run(); err != nil {
+				return err
+			}
+			return o.strictError(decoder.skips)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// MarshalMap encodes every value of a map of local structs into a newly allocated map of foreign structs holding
+// the same keys, useful for indexed caches of API objects. Introspection and input validation are performed only
+// once for the L/F type pair, then the same representation is used to map every value.
+//
+// Parameters:
+//   - src: The map of local structs to encode
+//   - opts: The options tuning the encoding, see Option
+//
+// Returns:
+//   - map[K]F: A map with the keys of src holding the encoded foreign structs
+//   - error: An error if the types are invalid or if encoding any of the values fails
+func MarshalMap[L, F any, K comparable](src map[K]L, opts ...Option) (map[K]F, error) {
+	var local L
+	var foreign F
+	o := newOptions(opts...)
+	encoder := &StructEncoder{opts: o, recordSkips: o.strict}
+	if err := encoder.init(&local, &foreign); err != nil {
+		return nil, err
+	}
+
+	out := make(map[K]F, len(src))
+	for key, value := range src {
+		var encoded F
+		encoder.local = reflect.ValueOf(&value)
+		encoder.foreign = reflect.ValueOf(&encoded)
+		if err := encoder.run(); err != nil {
+			return nil, fmt.Errorf("key %v: %w", key, err)
+		}
+		if err := o.strictError(encoder.skips); err != nil {
+			return nil, fmt.Errorf("key %v: %w", key, err)
+		}
+		out[key] = encoded
+	}
+
+	return out, nil
+}
+
+// UnmarshalMap decodes every value of a map of foreign structs into a newly allocated map of local structs holding
+// the same keys, useful for indexed caches of API objects. Introspection and input validation are performed only
+// once for the L/F type pair, then the same representation is used to map every value.
+//
+// Parameters:
+//   - src: The map of foreign structs to decode
+//   - opts: The options tuning the decoding, see Option
+//
+// Returns:
+//   - map[K]L: A map with the keys of src holding the decoded local structs
+//   - error: An error if the types are invalid or if decoding any of the values fails
+func UnmarshalMap[L any, K comparable, F any](src map[K]F, opts ...Option) (map[K]L, error) {
+	var local L
+	var foreign F
+	o := newOptions(opts...)
+	decoder := &StructDecoder{opts: o, recordSkips: o.strict}
+	if err := decoder.init(&foreign, &local); err != nil {
+		return nil, err
+	}
+
+	out := make(map[K]L, len(src))
+	for key, value := range src {
+		var decoded L
+		decoder.foreign = reflect.ValueOf(&value)
+		decoder.local = reflect.ValueOf(&decoded)
+		if err := decoder.run(); err != nil {
+			return nil, fmt.Errorf("key %v: %w", key, err)
+		}
+		if err := o.strictError(decoder.skips); err != nil {
+			return nil, fmt.Errorf("key %v: %w", key, err)
+		}
+		out[key] = decoded
+	}
+
+	return out, nil
+}
+
+// mapElements maps the elements of a collection by index, splitting them in contiguous chunks among the given
+// number of goroutines, or mapping them in order on the calling goroutine when it isn't greater than one.
+//
+// Parameters:
+//   - length: The number of elements to map
+//   - workers: The number of goroutines to split the elements among
+//   - newWorker: Returns the function mapping an element, called once per goroutine so each one holds its own
+//     encoder or decoder
+//
+// Returns:
+//   - error: The error mapping the element with the lowest index among the failed ones, if any
+func mapElements(length, workers int, newWorker func() func(int) error) error {
+	if workers <= 1 || length <= 1 {
+		mapOne := newWorker()
+		for i := 0; i < length; i++ {
+			if err := mapOne(i); err != nil {
+				return fmt.Errorf("element %v: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	return mapChunks(length, workers, func(_, from, to int) error {
+		mapOne := newWorker()
+		for i := from; i < to; i++ {
+			if err := mapOne(i); err != nil {
+				return fmt.Errorf("element %v: %w", i, err)
+			}
+		}
+		return nil
+	})
+}
+
+// mapChunks splits the indexes of a collection in contiguous chunks among the given number of goroutines, and
+// waits for every chunk to be mapped.
+//
+// Parameters:
+//   - length: The number of elements to map
+//   - workers: The number of goroutines to split the elements among, at least one
+//   - mapChunk: Maps the elements from the index `from` up to the index `to`, excluded, on the goroutine `worker`
+//
+// Returns:
+//   - error: The error of the first failed chunk, if any
+func mapChunks(length, workers int, mapChunk func(worker, from, to int) error) error {
+	workers = min(workers, length)
+	chunk := (length + workers - 1) / workers
+	errs := make([]error, workers)
+	wg := sync.WaitGroup{}
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[w] = mapChunk(w, w*chunk, min((w+1)*chunk, length))
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnmarshalSeq lazily decodes every foreign value produced by an iterator into a local struct, so the package
+// integrates with the standard iterator ecosystem without materializing intermediate slices.
+// The representation is computed once and reused for every value.
+//
+// Parameters:
+//   - src: The iterator producing foreign values
+//
+// Returns:
+//   - iter.Seq2[L, error]: An iterator yielding each decoded local struct, or the zero value along with the
+//     error when decoding fails, in which case the iteration stops
+func UnmarshalSeq[L, F any](src iter.Seq[F]) iter.Seq2[L, error] {
+	return func(yield func(L, error) bool) {
+		decoder := &StructDecoder{opts: defaultCodec.opts}
+		for foreign := range src {
+			var local L
+			if err := decoder.decodeNext(foreign, &local); err != nil {
+				var empty L
+				yield(empty, err)
+				return
+			}
+			if !yield(local, nil) {
+				return
+			}
+		}
+	}
+}
+
+// UnmarshalSeq2 lazily decodes every foreign value produced by a keyed iterator (like maps.All) into a local
+// struct, preserving the keys. The representation is computed once and reused for every value.
+//
+// Parameters:
+//   - src: The iterator producing keys along with foreign values
+//
+// Returns:
+//   - iter.Seq2[K, L]: An iterator yielding each key along with its decoded local struct, the iteration stops
+//     when decoding fails
+//   - func() error: Returns the error that stopped the iteration, if any, once it's done
+func UnmarshalSeq2[L, K, F any](src iter.Seq2[K, F]) (iter.Seq2[K, L], func() error) {
+	var err error
+	seq := func(yield func(K, L) bool) {
+		err = nil
+		decoder := &StructDecoder{opts: defaultCodec.opts}
+		for key, foreign := range src {
+			var local L
+			if err = decoder.decodeNext(foreign, &local); err != nil {
+				return
+			}
+			if !yield(key, local) {
+				return
+			}
+		}
+	}
+	return seq, func() error { return err }
+}
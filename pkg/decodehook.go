@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// The signatures of the decode hooks of the mapstructure package. Hooks are matched by signature rather than by
+// type, so the named types mapstructure declares for them are accepted without depending on it.
+var (
+	decodeHookTypeFunc  = reflect.TypeFor[func(reflect.Type, reflect.Type, any) (any, error)]()
+	decodeHookKindFunc  = reflect.TypeFor[func(reflect.Kind, reflect.Kind, any) (any, error)]()
+	decodeHookValueFunc = reflect.TypeFor[func(reflect.Value, reflect.Value) (any, error)]()
+)
+
+// FromDecodeHook adapts a mapstructure DecodeHookFunc into a function converting values of type From into values
+// of type To, which can be registered as either side of a converter, see RegisterConverter and
+// RegisterNamedConverter. Hooks taking types (DecodeHookFuncType), kinds (DecodeHookFuncKind) or values
+// (DecodeHookFuncValue) are supported, including the ones composed with ComposeDecodeHookFunc.
+//
+// Parameters:
+//   - hook: The mapstructure decode hook
+//
+// Returns:
+//   - func(From) (To, error): The conversion function, failing when the hook fails or its result is not a To
+//   - error: An error if the hook is not a function with the signature of a decode hook
+func FromDecodeHook[From, To any](hook any) (func(From) (To, error), error) {
+	exec, err := decodeHookExec(hook)
+	if err != nil {
+		return nil, err
+	}
+	target := reflect.TypeFor[To]()
+	return func(value From) (To, error) {
+		var result To
+		from := reflect.ValueOf(&value).Elem()
+		if from.Kind() == reflect.Interface && !from.IsNil() {
+			from = from.Elem() // hooks are handed the dynamic type of the value, as mapstructure does
+		}
+		out, err := exec(from, reflect.New(target).Elem())
+		if err != nil || out == nil {
+			return result, err
+		}
+		converted, ok := out.(To)
+		if !ok {
+			return result, fmt.Errorf("%w %T is not %v", ErrDecodeHookResult, out, target)
+		}
+		return converted, nil
+	}, nil
+}
+
+// RegisterDecodeHook registers a mapstructure DecodeHookFunc as the converter between a local type L and a foreign
+// type F, see RegisterConverter. The hook is used in both directions, so it must handle converting L into F as well
+// as F into L; use FromDecodeHook to pair a hook handling a single direction with another function.
+//
+// Parameters:
+//   - hook: The mapstructure decode hook
+//
+// Returns:
+//   - error: An error if the hook is not a function with the signature of a decode hook
+func RegisterDecodeHook[L, F any](hook any) error {
+	encode, err := FromDecodeHook[L, F](hook)
+	if err != nil {
+		return err
+	}
+	decode, err := FromDecodeHook[F, L](hook)
+	if err != nil {
+		return err
+	}
+	RegisterConverter(encode, decode)
+	return nil
+}
+
+// decodeHookExec turns a decode hook into a function taking the value to convert and the value it is converted
+// into, whatever the signature of the hook.
+func decodeHookExec(hook any) (func(from, to reflect.Value) (any, error), error) {
+	fn := reflect.ValueOf(hook)
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		return nil, fmt.Errorf("%w %T", ErrInvalidDecodeHook, hook)
+	}
+	switch {
+	case fn.Type().ConvertibleTo(decodeHookTypeFunc):
+		typed := fn.Convert(decodeHookTypeFunc).Interface().(func(reflect.Type, reflect.Type, any) (any, error))
+		return func(from, to reflect.Value) (any, error) {
+			return typed(from.Type(), to.Type(), from.Interface())
+		}, nil
+	case fn.Type().ConvertibleTo(decodeHookKindFunc):
+		typed := fn.Convert(decodeHookKindFunc).Interface().(func(reflect.Kind, reflect.Kind, any) (any, error))
+		return func(from, to reflect.Value) (any, error) {
+			return typed(from.Kind(), to.Kind(), from.Interface())
+		}, nil
+	case fn.Type().ConvertibleTo(decodeHookValueFunc):
+		typed := fn.Convert(decodeHookValueFunc).Interface().(func(reflect.Value, reflect.Value) (any, error))
+		return typed, nil
+	}
+	return nil, fmt.Errorf("%w %T", ErrInvalidDecodeHook, hook)
+}
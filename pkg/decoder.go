@@ -2,7 +2,10 @@ package pkg
 
 import (
 	"errors"
+	"fmt"
+	"maps"
 	"reflect"
+	"strings"
 )
 
 // StructDecoder provides functionality for decoding data between struct types
@@ -16,15 +19,23 @@ type StructDecoder struct {
 	local          reflect.Value // destination for a decoder
 	foreign        reflect.Value // source for a decoder
 	representation *StructRepr
+	reprs          representations // resolves the references of the representation fields
+	opts           *options
+	recordSkips    bool        // keep track of every field that could not be transferred
+	skips          []FieldSkip // fields that could not be transferred when recordSkips is set
+	current        fieldPath   // the field being decoded, reported when mapping it panics
+	recordPresence bool        // keep track of every field populated from the foreign object
+	presence       FieldSet    // fields populated from the foreign object when recordPresence is set
+	worker         bool        // decoding a chunk of a slice split among goroutines, see WithParallelSlices
 }
 
 func (this *StructDecoder) validateInput() error {
 	if this.local.Kind() != reflect.Pointer || this.local.IsNil() {
-		return errors.New(ErrUnmarshalDestType)
+		return ErrUnmarshalDestType
 	}
 
-	if this.foreign.Kind() == reflect.Ptr && this.foreign.IsNil() {
-		return errors.New(ErrUnmarshalSrcType)
+	if !this.foreign.IsValid() || this.foreign.Kind() == reflect.Ptr && this.foreign.IsNil() {
+		return ErrUnmarshalSrcType
 	}
 
 	return nil
@@ -44,36 +55,108 @@ func (this *StructDecoder) validateInput() error {
 func (this *StructDecoder) init(foreign interface{}, local interface{}) error {
 	this.local = reflect.ValueOf(local)
 	this.foreign = reflect.ValueOf(foreign)
+	if this.opts == nil {
+		this.opts = newOptions()
+	}
 
 	if err := this.validateInput(); err != nil {
 		return err
 	}
 
 	this.representation = &StructRepr{}
-	err := this.representation.introspect(local, foreign)
+	err := this.representation.introspect(local, foreign, this.opts)
 	if err != nil {
 		return this.unwrapIntrospectErr(err)
 	}
+	this.reprs = this.opts.cache.representations()
 
 	return nil
 }
 
-func (this StructDecoder) unwrapIntrospectErr(err error) error {
-	msg := err.Error()
+// bind points an already initialized decoder to new foreign and local values, validating them, so the
+// representation computed by init can be reused for values of the same types.
+func (this *StructDecoder) bind(foreign interface{}, local interface{}) error {
+	this.local = reflect.ValueOf(local)
+	this.foreign = reflect.ValueOf(foreign)
+	return this.validateInput()
+}
 
-	if msg == ErrLocalTypeNotStruct {
-		return errors.New(ErrUnmarshalDestType)
+// decodeNext decodes a foreign value into a local one reusing the representation computed for previous values,
+// the decoder is only initialized again when the type of the foreign value changes.
+func (this *StructDecoder) decodeNext(foreign interface{}, local interface{}) error {
+	var err error
+	if this.representation == nil || reflect.TypeOf(foreign) != this.foreign.Type() {
+		err = this.init(foreign, local)
+	} else {
+		err = this.bind(foreign, local)
+	}
+	if err != nil {
+		this.representation = nil
+		return err
+	}
+	return this.run()
+}
+
+func (this StructDecoder) unwrapIntrospectErr(err error) error {
+	if errors.Is(err, ErrLocalTypeNotStruct) {
+		return ErrUnmarshalDestType
 	}
 
-	if msg == ErrForeignTypeNotStruct {
-		return errors.New(ErrUnmarshalSrcType)
+	if errors.Is(err, ErrForeignTypeNotStruct) {
+		return ErrUnmarshalSrcType
 	}
 
 	return err
 }
 
-func (this *StructDecoder) run() error {
-	return setLocalFieldsValue(this.foreign, this.local, this.representation.Fields)
+func (this *StructDecoder) run() (err error) {
+	defer recoverPanic(&err, &this.local, &this.current)
+	if this.recordSkips || this.opts.skipHandler != nil {
+		unmatched := this.reprs.unmatchedFields(*this.representation, "")
+		this.opts.notifySkips(unmatched...)
+		if this.recordSkips {
+			this.skips = unmatched
+		}
+	}
+	if this.recordPresence {
+		this.presence = FieldSet{}
+	}
+	if err := this.beforeUnmarshal(); err != nil {
+		return err
+	}
+	if err := this.setLocalFieldsValue(this.foreign, this.local, this.representation.Fields, "", 0); err != nil {
+		return this.current.wrap(this.local, err)
+	}
+	return this.afterUnmarshal()
+}
+
+// skip logs a field that could not be transferred, notifies the skip handler, and keeps track of it when the decoder
+// was requested to do so. Fields marked as required can't be skipped, an error describing the field is returned
+// instead.
+func (this *StructDecoder) skip(prefix string, field SourceField, target TargetField, reason SkipReason) error {
+	if this.opts.logger != nil {
+		this.opts.logger.Debug("field skipped", "field", prefix+field.Name, "path", target.Path, "reason", reason)
+	}
+	skip := FieldSkip{
+		Field:  prefix + field.Name,
+		Path:   target.Path,
+		Reason: reason,
+	}
+	if field.Tag.Opts.Required {
+		return fmt.Errorf("%w %v", ErrRequiredField, skip)
+	}
+	this.opts.notifySkips(skip)
+	if this.recordSkips {
+		this.skips = append(this.skips, skip)
+	}
+	return nil
+}
+
+// populate keeps track of a local field populated from the foreign object, when the decoder was requested to do so.
+func (this *StructDecoder) populate(field string) {
+	if this.recordPresence {
+		this.presence[field] = struct{}{}
+	}
 }
 
 // setLocalFieldsValue recursively copies data from source fields to destination fields based on mapping information.
@@ -82,16 +165,24 @@ func (this *StructDecoder) run() error {
 //   - src: The source (foreign) value to copy data from
 //   - dst: The destination (local) value to copy data to
 //   - reprFields: The field mapping rules that define how to copy between structures
+//   - prefix: The local path of the parent field, used to report skipped fields
+//   - offset: How many elements of the foreign index paths were already walked to reach src
 //
 // The function handles:
 //   - Dereferencing pointers in both source and destination
 //   - Creating new objects for nil destination pointers
 //   - Handling nested struct fields by recursion
-//   - Array/slice fields in structures
+//   - Array/slice fields in structures, decoding every element when mapped to a foreign slice or a `[*]` path
+//   - Map fields mapped to foreign maps, copying every entry
 //   - Reading data from source fields using provided field indices
 //
 // Returns an error if accessing or setting field values fails.
-func setLocalFieldsValue(src, dst reflect.Value, reprFields []SourceField) error {
+func (this *StructDecoder) setLocalFieldsValue(
+	src, dst reflect.Value,
+	reprFields []SourceField,
+	prefix string,
+	offset int,
+) error {
 	source := src
 	if source.Kind() == reflect.Ptr {
 		source = source.Elem()
@@ -101,27 +192,72 @@ func setLocalFieldsValue(src, dst reflect.Value, reprFields []SourceField) error
 	targetKind := target.Kind()
 	if targetKind == reflect.Ptr {
 		if target.IsNil() {
+			if this.opts.logger != nil {
+				this.opts.logger.Debug("allocating local value", "field", strings.TrimSuffix(prefix, "."))
+			}
 			target.Set(reflect.New(target.Type().Elem()))
 		}
 		target = target.Elem()
 	}
 
-	for _, field := range reprFields {
-		child, hasChild := localRepresentations[field.ChildRef]
-		foreign := foreignRepresentations[field.TargetRef]
+	for i, field := range reprFields {
+		if field.Tag.Fallback {
+			continue // decoded along with the field mapping the main path
+		}
+		this.current = fieldPath{prefix, this.selectAlternative(reprFields[i:], source, offset)}
+		field = *this.current.field
+		child, hasChild := this.reprs.child(field)
+		foreign := this.reprs.target(field)
+		split, elementWise := foreign.splitElements(offset)
 
-		if hasChild {
-			err := setLocalChildValue(field, child, src, target)
+		if !field.Tag.decodes() || !this.opts.selected(prefix+field.Name) {
+			continue
+		}
+		if !this.reprs.conditionHolds(field, source, offset) {
+			continue
+		}
+		if field.Tag.Opts.Compute != "" {
+			err := this.setLocalComputedValue(field, source, target, prefix, offset)
+			if err != nil {
+				return err
+			}
+		} else if field.Converted {
+			err := this.setLocalConvertedValue(field, source, target, foreign, prefix, offset)
+			if err != nil {
+				return err
+			}
+		} else if field.JoinRefs != nil {
+			err := this.setLocalJoinedValue(field, source, target, prefix, offset)
+			if err != nil {
+				return err
+			}
+		} else if field.IsArray && elementWise && (hasChild || foreign.Wildcard > offset) {
+			err := this.setLocalSliceValue(field, child, source, target, foreign, prefix, offset, split)
+			if err != nil {
+				return err
+			}
+		} else if field.IsMap && foreign.IsMap {
+			err := this.setLocalMapValue(field, child, source, target, foreign, prefix, offset)
+			if err != nil {
+				return err
+			}
+		} else if hasChild {
+			err := this.setLocalChildValue(field, child, src, target, prefix, offset)
 			if err != nil {
 				return err
 			}
 		} else {
-			data, err := getForeignFieldData(foreign.IndexPath, source)
+			data, reason, err := getForeignFieldData(foreign.IndexPath[offset:], foreign.Elements[offset:], source, this.opts)
 			if err != nil {
 				return err
 			}
-			if data != nil {
-				target.Field(field.Id).Set(reflect.ValueOf(data))
+			if data.IsValid() {
+				if err := assignValue(target.Field(field.Id), data); err != nil {
+					return err
+				}
+				this.populate(prefix + field.Name)
+			} else if err := this.skip(prefix, field, foreign, reason); err != nil {
+				return err
 			}
 		}
 	}
@@ -137,16 +273,34 @@ func setLocalFieldsValue(src, dst reflect.Value, reprFields []SourceField) error
 //   - child: The representation of the child struct with its own field mappings
 //   - src: The source (foreign) value that may contain data for the child structure
 //   - target: The target (local) value where the child structure should be populated
+//   - prefix: The local path of the parent field, used to report skipped fields
+//   - offset: How many elements of the foreign index paths were already walked to reach src
 //
 // The function supports:
-//   - Creating and populating slices of structs when field.IsArray is true
+//   - Creating and populating slices of structs when field.IsArray is true, or populating the first element
+//     of fixed-size arrays and of slices whose elements are preserved
 //   - Setting values on direct struct fields when field.IsArray is false
 //   - Recursively populating child structs by calling setLocalFieldsValue
 //
 // Returns an error if setting field values fails during recursion.
-func setLocalChildValue(field SourceField, child StructRepr, src, target reflect.Value) error {
+func (this *StructDecoder) setLocalChildValue(
+	field SourceField,
+	child StructRepr,
+	src, target reflect.Value,
+	prefix string,
+	offset int,
+) error {
 	var childTarget reflect.Value
-	if field.IsArray && field.Kind == reflect.Struct {
+	existing := target.Field(field.Id)
+	preserve := field.Type.Kind() == reflect.Array || this.opts.preserveSlice(field.Tag)
+	if field.IsArray && field.Kind == reflect.Struct && preserve && existing.Len() > 0 {
+		childTarget = existing.Index(0)
+	} else if field.IsArray && field.Kind == reflect.Struct && field.Type.Kind() == reflect.Array {
+		return nil
+	} else if field.IsArray && field.Kind == reflect.Struct {
+		if this.opts.logger != nil {
+			this.opts.logger.Debug("allocating local slice", "field", prefix+field.Name)
+		}
 		slice := reflect.MakeSlice(field.Type, 0, 1)
 		ptr := reflect.New(field.Type.Elem())
 		slice = reflect.Append(slice, ptr.Elem())
@@ -158,7 +312,7 @@ func setLocalChildValue(field SourceField, child StructRepr, src, target reflect
 		if !field.IsArray {
 			childTarget = target.Field(field.Id)
 		}
-		err := setLocalFieldsValue(src, childTarget, child.Fields)
+		err := this.setLocalFieldsValue(src, childTarget, child.Fields, prefix+field.Name+".", offset)
 		if err != nil {
 			return err
 		}
@@ -166,54 +320,545 @@ func setLocalChildValue(field SourceField, child StructRepr, src, target reflect
 	return nil
 }
 
+// setLocalJoinedValue sets a local string field setting the `join<>` tag option to the value of every joined
+// foreign field, joined with the literals of the option. The field is skipped when a joined foreign field can't be
+// reached or every one of them is zero.
+func (this *StructDecoder) setLocalJoinedValue(
+	field SourceField,
+	source, target reflect.Value,
+	prefix string,
+	offset int,
+) error {
+	joined, reason := this.reprs.joinForeignValues(field, source, offset, this.opts)
+	if reason != "" {
+		return this.skip(prefix, field, TargetField{Path: field.Tag.Path}, reason)
+	}
+	target.Field(field.Id).SetString(joined)
+	this.populate(prefix + field.Name)
+	return nil
+}
+
+// setLocalComputedValue sets a local field setting the `compute<name>` tag option to the value the registered
+// function derives from the foreign value found at the field path. The field is skipped when the foreign value
+// can't be reached or the function returns nil.
+func (this *StructDecoder) setLocalComputedValue(
+	field SourceField,
+	source, target reflect.Value,
+	prefix string,
+	offset int,
+) error {
+	foreign := this.reprs.target(field)
+	value, reason := this.reprs.resolveComputeSource(field, source, offset)
+	if reason != "" {
+		return this.skip(prefix, field, foreign, reason)
+	}
+	data, err := computeValue(field, value)
+	if err != nil {
+		return err
+	}
+	if !data.IsValid() {
+		return this.skip(prefix, field, foreign, SkipZeroValue)
+	}
+	target.Field(field.Id).Set(data)
+	this.populate(prefix + field.Name)
+	return nil
+}
+
+// setLocalConvertedValue sets a converted local field to the value of the foreign field it maps, converted by the
+// converter named by its `convert<name>` tag option or registered for their types. The field is skipped when the
+// foreign value can't be reached or is zero.
+func (this *StructDecoder) setLocalConvertedValue(
+	field SourceField,
+	source, target reflect.Value,
+	foreign TargetField,
+	prefix string,
+	offset int,
+) error {
+	value, reason := resolveForeignValue(foreign.IndexPath[offset:], foreign.Elements[offset:], source)
+	if reason == "" && this.opts.isEmpty(value) {
+		reason = SkipZeroValue
+	}
+	if reason != "" {
+		return this.skip(prefix, field, foreign, reason)
+	}
+	conv, _ := field.converter(foreign.Type)
+	data, err := conv.convertValue(value, false)
+	if err != nil {
+		return fmt.Errorf("%w in %v", err, prefix+field.Name)
+	}
+	target.Field(field.Id).Set(data)
+	this.populate(prefix + field.Name)
+	return nil
+}
+
+// resolveComputeSource returns the foreign value a field setting the `compute<name>` tag option is derived from:
+// the value found at the field path, or the foreign value itself on the top level when the path is `+`.
+func (this representations) resolveComputeSource(
+	field SourceField,
+	from reflect.Value,
+	offset int,
+) (reflect.Value, SkipReason) {
+	if field.TargetRef == "" {
+		return resolveForeignValue(nil, nil, from)
+	}
+	foreign := this.target(field)
+	return resolveForeignValue(foreign.IndexPath[offset:], foreign.Elements[offset:], from)
+}
+
+// joinForeignValues joins the value of every foreign field listed in the `join<>` tag option of a field with the
+// literals of the option.
+//
+// Parameters:
+//   - field: The local field setting the `join<>` option
+//   - from: The foreign value holding the joined fields
+//   - offset: How many elements of the foreign index paths were already walked to reach from
+//   - opts: The options holding the zero-value semantics
+//
+// Returns:
+//   - string: The joined value
+//   - SkipReason: Not empty if a joined field can't be reached, or every one of them is zero
+func (this representations) joinForeignValues(
+	field SourceField,
+	from reflect.Value,
+	offset int,
+	opts *options,
+) (string, SkipReason) {
+	b := strings.Builder{}
+	empty := true
+	for i, part := range field.Tag.Opts.Join {
+		if part.Path == nil {
+			b.WriteString(part.Literal)
+			continue
+		}
+		target := this.joined(field, i)
+		value, reason := resolveForeignValue(target.IndexPath[offset:], target.Elements[offset:], from)
+		if reason == "" && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				reason = SkipNilPointer
+			} else {
+				value = value.Elem()
+			}
+		}
+		if reason != "" {
+			return "", reason
+		}
+		empty = empty && opts.isEmpty(value)
+		b.WriteString(fmt.Sprint(value.Interface()))
+	}
+	if empty {
+		return "", SkipZeroValue
+	}
+	return b.String(), ""
+}
+
+// selectAlternative picks which path of a field listing alternative paths is decoded: the first one whose foreign
+// value isn't empty, or the main path when all of them are.
+//
+// Parameters:
+//   - fields: The representation fields starting at the one mapping the main path, followed by its fallbacks
+//   - source: The foreign value holding the fields
+//   - offset: How many elements of the foreign index paths were already walked to reach source
+//
+// Returns:
+//   - *SourceField: The field mapping the selected path, pointing into fields
+func (this *StructDecoder) selectAlternative(fields []SourceField, source reflect.Value, offset int) *SourceField {
+	if len(fields[0].Tag.Alternatives) == 0 {
+		return &fields[0]
+	}
+	for i, field := range fields {
+		if i > 0 && (!field.Tag.Fallback || field.Id != fields[0].Id) {
+			break
+		}
+		target := this.reprs.target(field)
+		value, reason := resolveForeignValue(target.IndexPath[offset:], target.Elements[offset:], source)
+		if reason == "" && !this.opts.isEmpty(value) {
+			return &fields[i]
+		}
+	}
+	return &fields[0]
+}
+
+// setLocalSliceValue decodes every element of a foreign slice into a newly allocated local slice,
+// preserving order and length.
+//
+// Parameters:
+//   - field: The local slice field being decoded
+//   - child: The representation of the local slice element struct, empty when the elements aren't structs
+//   - source: The source (foreign) value reached after walking `offset` elements of the index path
+//   - target: The target (local) struct holding the slice field
+//   - foreign: The foreign field the local slice maps to
+//   - prefix: The local path of the parent field, used to report skipped fields
+//   - offset: How many elements of the foreign index paths were already walked to reach source
+//   - split: How many elements of the foreign index path lead to the foreign slice
+//
+// The index paths of the element fields are absolute, as they share the path of the slice as prefix
+// the elements are decoded using split as offset. When the foreign path goes on after a `[*]` segment,
+// the rest of the path is read from every element of the foreign slice.
+// Nil pointer elements of the foreign slice, or elements with nothing to read, are left as zero values
+// in the local slice. Local fixed-size arrays are zeroed before decoding, and must be able to hold every element.
+//
+// When the local slice elements are preserved, the foreign elements are decoded over the existing ones, and
+// elements beyond the foreign slice length are kept.
+// When the field sets the `mergekey<>` option and the local slice already holds elements, the elements are
+// merged by key instead, see mergeLocalSliceValue.
+//
+// Returns an error if the foreign slice doesn't fit a local array or decoding any of the elements fails.
+func (this *StructDecoder) setLocalSliceValue(
+	field SourceField,
+	child StructRepr,
+	source, target reflect.Value,
+	foreign TargetField,
+	prefix string,
+	offset, split int,
+) error {
+	elems, reason := resolveForeignValue(foreign.IndexPath[offset:split], foreign.Elements[offset:split], source)
+	if reason == "" && elems.Len() == 0 {
+		reason = SkipEmptySlice
+	}
+	if reason != "" {
+		return this.skip(prefix, field, foreign, reason)
+	}
+
+	existing := target.Field(field.Id)
+	if field.Tag.Opts.MergeKey != "" && existing.Kind() == reflect.Slice && existing.Len() > 0 {
+		return this.mergeLocalSliceValue(field, child, elems, existing, prefix, split)
+	}
+
+	length := elems.Len()
+	slice := reflect.New(field.Type).Elem()
+	preserve := this.opts.preserveSlice(field.Tag)
+	if field.Type.Kind() == reflect.Array {
+		if length > slice.Len() {
+			return fmt.Errorf("%w %v elements into %v%v", ErrArrayOverflow, length, prefix, field.Name)
+		}
+		if preserve {
+			slice.Set(existing)
+		}
+	} else if preserve {
+		slice.Set(existing)
+		if slice.Len() < length {
+			if this.opts.logger != nil {
+				this.opts.logger.Debug("allocating local slice", "field", prefix+field.Name, "length", length)
+			}
+			slice.Set(reflect.AppendSlice(slice, reflect.MakeSlice(field.Type, length-slice.Len(), length-slice.Len())))
+		}
+	} else {
+		if this.opts.logger != nil {
+			this.opts.logger.Debug("allocating local slice", "field", prefix+field.Name, "length", length)
+		}
+		slice.Set(reflect.MakeSlice(field.Type, length, length))
+	}
+
+	// the closure may run in other goroutines, capturing the field, child and foreign field would move them to the heap
+	name, childRef, childFields := field.Name, field.ChildRef, child.Fields
+	indexPath, elements := foreign.IndexPath[split:], foreign.Elements[split:]
+	err := this.forEachElement(length, func(decoder *StructDecoder, i int) error {
+		elem := elems.Index(i)
+		elemPrefix := elementPath(prefix, name, i)
+		if elem.Kind() == reflect.Pointer && elem.IsNil() {
+			if decoder.opts.logger != nil {
+				decoder.opts.logger.Debug("field skipped", "field", strings.TrimSuffix(elemPrefix, "."), "reason", SkipNilPointer)
+			}
+			return nil
+		}
+		if childRef == "" {
+			data, reason, err := getForeignFieldData(indexPath, elements, elem, decoder.opts)
+			if err != nil {
+				return err
+			}
+			if !data.IsValid() {
+				if decoder.opts.logger != nil {
+					decoder.opts.logger.Debug("field skipped", "field", strings.TrimSuffix(elemPrefix, "."), "reason", reason)
+				}
+				return nil
+			}
+			slice.Index(i).Set(data)
+			decoder.populate(strings.TrimSuffix(elemPrefix, "."))
+			return nil
+		}
+		return decoder.setLocalFieldsValue(elem, slice.Index(i), childFields, elemPrefix, split)
+	})
+	if err != nil {
+		return err
+	}
+	target.Field(field.Id).Set(slice)
+	this.populate(prefix + field.Name)
+
+	return nil
+}
+
+// forEachElement decodes the elements of a slice by index, splitting them among goroutines when the decoder was
+// created with WithParallelSlices and the slice is large enough. Every goroutine decodes its chunk with its own copy
+// of the decoder, the fields they skip and populate are added to the decoder once every chunk is decoded.
+//
+// Parameters:
+//   - length: The number of elements to decode
+//   - decodeOne: Decodes the element of the given index with the given decoder
+//
+// Returns:
+//   - error: The error decoding the element with the lowest index among the failed ones, if any
+func (this *StructDecoder) forEachElement(length int, decodeOne func(*StructDecoder, int) error) error {
+	workers := this.opts.sliceParallelism(length)
+	if this.worker || workers <= 1 {
+		for i := range length {
+			if err := decodeOne(this, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	decoders := make([]StructDecoder, workers)
+	for w := range decoders {
+		decoders[w] = *this
+		decoders[w].worker = true
+		decoders[w].skips = nil
+		if this.recordPresence {
+			decoders[w].presence = FieldSet{}
+		}
+	}
+	err := mapChunks(length, workers, func(w, from, to int) (err error) {
+		defer recoverPanic(&err, &decoders[w].local, &decoders[w].current)
+		for i := from; i < to; i++ {
+			if err := decodeOne(&decoders[w], i); err != nil {
+				return decoders[w].current.wrap(decoders[w].local, err)
+			}
+		}
+		return nil
+	})
+	for _, decoder := range decoders {
+		this.skips = append(this.skips, decoder.skips...)
+		maps.Copy(this.presence, decoder.presence)
+	}
+	return err
+}
+
+// mergeLocalSliceValue decodes every element of a foreign slice into a local slice already holding elements,
+// matching them by the merge key field of the local elements.
+//
+// Parameters:
+//   - field: The local slice field being decoded, setting the `mergekey<>` option
+//   - child: The representation of the local slice element struct
+//   - elems: The foreign slice
+//   - existing: The local slice holding elements
+//   - prefix: The local path of the parent field, used to report skipped fields
+//   - split: How many elements of the foreign index path lead to the foreign slice
+//
+// Foreign elements whose key matches a local element are decoded over it, keeping the local fields the mapping
+// doesn't touch, and keep its position. Foreign elements without a match are appended in order, while local
+// elements without a foreign match are dropped.
+//
+// Returns an error if decoding any of the elements fails.
+func (this *StructDecoder) mergeLocalSliceValue(
+	field SourceField,
+	child StructRepr,
+	elems, existing reflect.Value,
+	prefix string,
+	split int,
+) error {
+	keyField, _ := child.fieldByName(field.Tag.Opts.MergeKey)
+	keyTarget := this.reprs.target(keyField)
+
+	updated := make([]reflect.Value, existing.Len())
+	appended := make([]reflect.Value, 0)
+	for i := range elems.Len() {
+		elem := elems.Index(i)
+		if elem.Kind() == reflect.Pointer && elem.IsNil() {
+			continue
+		}
+		key, _, err := getForeignFieldData(keyTarget.IndexPath[split:], keyTarget.Elements[split:], elem, this.opts)
+		if err != nil {
+			return err
+		}
+
+		value := reflect.New(field.Type.Elem()).Elem()
+		match := findElementByKey(existing, keyField, key, updated)
+		if match >= 0 {
+			value.Set(existing.Index(match))
+		}
+		elemPrefix := elementPath(prefix, field.Name, i)
+		if err := this.setLocalFieldsValue(elem, value, child.Fields, elemPrefix, split); err != nil {
+			return err
+		}
+		if match >= 0 {
+			updated[match] = value
+		} else {
+			appended = append(appended, value)
+		}
+	}
+
+	if this.opts.logger != nil {
+		this.opts.logger.Debug("merging local slice", "field", prefix+field.Name, "key", keyField.Name)
+	}
+	merged := reflect.MakeSlice(field.Type, 0, elems.Len())
+	for _, value := range updated {
+		if value.IsValid() {
+			merged = reflect.Append(merged, value)
+		}
+	}
+	merged = reflect.Append(merged, appended...)
+	existing.Set(merged)
+	this.populate(prefix + field.Name)
+
+	return nil
+}
+
+// findElementByKey returns the index of the first element of a local slice whose key field holds the given key
+// and that wasn't matched yet, or -1 if there is none. Elements with an empty key never match.
+func findElementByKey(slice reflect.Value, keyField SourceField, key reflect.Value, matched []reflect.Value) int {
+	if !key.IsValid() {
+		return -1
+	}
+	for i := range slice.Len() {
+		elem := slice.Index(i)
+		if elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+		if !matched[i].IsValid() && reflect.DeepEqual(elem.Field(keyField.Id).Interface(), key.Interface()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// setLocalMapValue copies every entry of a foreign map into a newly allocated local map, merging the entries
+// already held by the local map according to the field merge mode.
+//
+// Parameters:
+//   - field: The local map field being decoded
+//   - child: The representation of the local map value struct, empty when the values aren't structs
+//   - source: The source (foreign) value reached after walking `offset` elements of the index path
+//   - target: The target (local) struct holding the map field
+//   - foreign: The foreign map field the local map maps to
+//   - prefix: The local path of the parent field, used to report skipped fields
+//   - offset: How many elements of the foreign index paths were already walked to reach source
+//
+// Struct values are decoded using the length of the foreign map index path as offset, as the index paths
+// of their fields share the path of the map as prefix. Nil pointer values are copied as zero values.
+// When merging, struct values are decoded over a copy of the local value found under the same key, and
+// keys already present are left untouched with the MapMergeKeep mode.
+//
+// Returns an error if the map keys can't be converted or decoding any of the values fails.
+func (this *StructDecoder) setLocalMapValue(
+	field SourceField,
+	child StructRepr,
+	source, target reflect.Value,
+	foreign TargetField,
+	prefix string,
+	offset int,
+) error {
+	entries, reason := resolveForeignValue(foreign.IndexPath[offset:], foreign.Elements[offset:], source)
+	if reason == "" && this.opts.isEmpty(entries) {
+		reason = SkipZeroValue
+	}
+	if reason != "" {
+		return this.skip(prefix, field, foreign, reason)
+	}
+	if entries.IsNil() {
+		target.Field(field.Id).SetZero()
+		this.populate(prefix + field.Name)
+		return nil
+	}
+
+	if this.opts.logger != nil {
+		this.opts.logger.Debug("allocating local map", "field", prefix+field.Name, "length", entries.Len())
+	}
+	mode := this.opts.mapMergeMode(field.Tag)
+	existing := target.Field(field.Id)
+	local := newMergedMap(existing, entries.Len(), mode)
+	iter := entries.MapRange()
+	for iter.Next() {
+		key, err := convertMapKey(iter.Key(), field.Type.Key())
+		if err != nil {
+			return err
+		}
+		current := existing.MapIndex(key)
+		if mode == MapMergeKeep && current.IsValid() {
+			continue
+		}
+		value := reflect.New(field.Type.Elem()).Elem()
+		if mode != MapReplace && current.IsValid() {
+			value.Set(current)
+		}
+		elem := iter.Value()
+		if field.ChildRef == "" {
+			value.Set(elem)
+		} else if elem.Kind() != reflect.Pointer || !elem.IsNil() {
+			elemPrefix := fmt.Sprintf("%v%v[%v].", prefix, field.Name, key)
+			err := this.setLocalFieldsValue(elem, value, child.Fields, elemPrefix, len(foreign.IndexPath))
+			if err != nil {
+				return err
+			}
+		}
+		local.SetMapIndex(key, value)
+	}
+	target.Field(field.Id).Set(local)
+	this.populate(prefix + field.Name)
+
+	return nil
+}
+
 // descendIntoForeignArrayField traverses into array or slice fields in foreign structures.
 //
 // Parameters:
 //   - from: The source value to examine, which may be an array or slice
-//   - finalValue: Whether this is the final value being accessed in a field path
+//   - elements: The element to traverse on each collection level, the first one or the last one when set
+//     to lastElement, levels without an entry traverse the first element
 //
 // Returns:
 //   - A reflect.Value that is either the same as the input (if not an array/slice),
-//     or the first element of the array/slice
-//   - A boolean indicating whether processing should be skipped (true for nil or empty arrays)
+//     or the requested element of the innermost array/slice
+//   - A SkipReason, not empty when processing should be skipped (nil or empty arrays)
 //
 // This function handles special cases for arrays and slices:
 // - Returns a skip flag for nil or empty collections
-// - For non-empty collections, returns the first element
-// - Has special behavior when the collection contains struct elements and is the final value
-func descendIntoForeignArrayField(from reflect.Value, finalValue bool) (reflect.Value, bool) {
-	kind := from.Kind()
-	if kind == reflect.Slice || kind == reflect.Array {
-		if from.IsNil() {
-			return from, true
+// - For non-empty collections, returns the requested element
+// - Nested collections, eg slices of slices, are traversed level by level
+func descendIntoForeignArrayField(from reflect.Value, elements []int) (reflect.Value, SkipReason) {
+	for level := 0; from.Kind() == reflect.Slice || from.Kind() == reflect.Array; level++ {
+		if from.Len() == 0 {
+			return from, SkipEmptySlice
 		}
 
-		if from.Len() > 0 {
-			// TODO: support non 0 indexes maybe?
-			if finalValue {
-				sliceElemKind := from.Type().Elem().Kind()
-				if sliceElemKind == reflect.Struct {
-					return from.Index(0), false
-				}
-			} else {
-				return from.Index(0), false
-			}
-		} else {
-			return from, true
+		element := 0
+		if level < len(elements) {
+			element = elements[level]
 		}
+		if element == lastElement {
+			element = from.Len() - 1
+		}
+		from = from.Index(element)
 	}
 
-	return from, false
+	return from, ""
+}
+
+// assertConcreteValue returns the pointer held by an interface field, see RegisterConcreteType, or the reason to
+// skip the field when the interface is nil or holds another type.
+func assertConcreteValue(from reflect.Value, concrete reflect.Type) (reflect.Value, SkipReason) {
+	if from.IsNil() {
+		return from, SkipNilPointer
+	}
+	if from.Elem().Type() != concrete {
+		return from, SkipConcrete
+	}
+	return from.Elem(), ""
 }
 
 // getForeignFieldData extracts data from a nested field path in a foreign structure.
 //
 // Parameters:
 //   - fieldIndexes: An array of field indices representing the path to the desired field
+//   - elements: The element traversed in each collection found along the path, see TargetField.Elements
 //   - from: The source value to extract data from
+//   - opts: The options holding the zero-value semantics
 //
 // Returns:
-//   - interface{}: The extracted field value, or nil if the field is nil/zero/invalid
+//   - reflect.Value: The extracted field value, or the invalid Value if the field is nil/zero/invalid
+//   - SkipReason: Why the extracted value is nil, empty when a value was found
 //   - error: Any error encountered during the extraction process
 //
 // This function navigates through a structure following the provided field indices path.
@@ -223,38 +868,76 @@ func descendIntoForeignArrayField(from reflect.Value, finalValue bool) (reflect.
 //   - Nested field access
 //   - Nil pointer and zero value detection
 //
-// When it reaches the final field in the path, it returns the field's interface value.
-// If any field along the path is nil, invalid, or zero, nil is returned.
-func getForeignFieldData(fieldIndexes []int, from reflect.Value) (interface{}, error) {
+// When it reaches the final field in the path, it returns the field's value, the value held by
+// interface fields. If any field along the path is nil, invalid, or zero, the invalid Value is returned.
+// Zero values are returned when their kind is configured to be copied.
+func getForeignFieldData(
+	fieldIndexes []int,
+	elements [][]int,
+	from reflect.Value,
+	opts *options,
+) (reflect.Value, SkipReason, error) {
+	from, skip := resolveForeignValue(fieldIndexes, elements, from)
+	if skip != "" {
+		return reflect.Value{}, skip, nil
+	}
+	if len(fieldIndexes) == 0 || opts.isEmpty(from) {
+		return reflect.Value{}, SkipZeroValue, nil
+	}
+	if from.Kind() == reflect.Interface {
+		from = from.Elem()
+	}
+	return from, "", nil
+}
+
+// resolveForeignValue walks a path of field indices through a foreign structure, returning the value of
+// the final field.
+//
+// Parameters:
+//   - fieldIndexes: An array of field indices representing the path to the desired field
+//   - elements: The element traversed in each collection found along the path, see TargetField.Elements
+//   - from: The source value to walk, a struct or a pointer to a struct
+//
+// Returns:
+//   - reflect.Value: The value of the field at the end of the path, or from itself when the path is empty
+//   - SkipReason: Not empty if a nil pointer or an empty slice was found along the path, or an interface holding
+//     another type than the one asserted with the `as<>` option
+func resolveForeignValue(fieldIndexes []int, elements [][]int, from reflect.Value) (reflect.Value, SkipReason) {
 	if from.Kind() == reflect.Pointer {
+		if from.IsNil() {
+			return from, SkipNilPointer
+		}
 		from = from.Elem()
 	}
 
 	// evaluation order is highly important
 	for idx, fieldId := range fieldIndexes {
-		var skip bool
-		from, skip = descendIntoForeignArrayField(from, idx == len(fieldIndexes)-1)
-		if skip {
-			return nil, nil
+		var skip SkipReason
+		var levels []int
+		if idx > 0 {
+			levels = elements[idx-1] // from holds the field reached on the previous step
+		}
+		from, skip = descendIntoForeignArrayField(from, levels)
+		if skip != "" {
+			return from, skip
+		}
+		if fieldId < 0 {
+			if from, skip = assertConcreteValue(from, concreteType(fieldId)); skip != "" {
+				return from, skip
+			}
+			continue
 		}
 
 		if from.Kind() == reflect.Pointer {
 			if from.IsNil() {
 				// Ignore nil pointers
-				return nil, nil
+				return from, SkipNilPointer
 			}
 			from = from.Elem()
 		}
 
 		from = from.Field(fieldId)
-
-		if idx == len(fieldIndexes)-1 {
-			if !from.IsValid() || from.IsZero() {
-				return nil, nil
-			}
-			return from.Interface(), nil
-		}
 	}
 
-	return nil, nil
+	return from, ""
 }
@@ -2,7 +2,9 @@ package pkg
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 )
 
 // StructEncoder is a utility for encoding data from a local (source) structure to a foreign (destination) structure.
@@ -13,15 +15,21 @@ type StructEncoder struct {
 	local          reflect.Value
 	foreign        reflect.Value
 	representation *StructRepr
+	reprs          representations // resolves the references of the representation fields
+	opts           *options
+	recordSkips    bool        // keep track of every field that could not be transferred
+	skips          []FieldSkip // fields that could not be transferred when recordSkips is set
+	current        fieldPath   // the field being encoded, reported when mapping it panics
+	worker         bool        // encoding a chunk of a slice split among goroutines, see WithParallelSlices
 }
 
 func (this *StructEncoder) validateInput() error {
 	if this.foreign.Kind() != reflect.Pointer || this.foreign.IsNil() {
-		return errors.New(ErrUnmarshalDestType)
+		return ErrUnmarshalDestType
 	}
 
-	if this.local.Kind() == reflect.Ptr && this.local.IsNil() {
-		return errors.New(ErrUnmarshalSrcType)
+	if !this.local.IsValid() || this.local.Kind() == reflect.Ptr && this.local.IsNil() {
+		return ErrUnmarshalSrcType
 	}
 
 	return nil
@@ -44,36 +52,74 @@ func (this *StructEncoder) validateInput() error {
 func (this *StructEncoder) init(local interface{}, foreign interface{}) error {
 	this.local = reflect.ValueOf(local)
 	this.foreign = reflect.ValueOf(foreign)
+	if this.opts == nil {
+		this.opts = newOptions()
+	}
 
 	if err := this.validateInput(); err != nil {
 		return err
 	}
 
 	this.representation = &StructRepr{}
-	err := this.representation.introspect(local, foreign)
+	err := this.representation.introspect(local, foreign, this.opts)
 	if err != nil {
 		return this.unwrapIntrospectErr(err)
 	}
+	this.reprs = this.opts.cache.representations()
 
 	return nil
 }
 
 func (this *StructEncoder) unwrapIntrospectErr(err error) error {
-	msg := err.Error()
-
-	if msg == ErrForeignTypeNotStruct {
-		return errors.New(ErrUnmarshalDestType)
+	if errors.Is(err, ErrForeignTypeNotStruct) {
+		return ErrUnmarshalDestType
 	}
 
-	if msg == ErrLocalTypeNotStruct {
-		return errors.New(ErrUnmarshalSrcType)
+	if errors.Is(err, ErrLocalTypeNotStruct) {
+		return ErrUnmarshalSrcType
 	}
 
 	return err
 }
 
-func (this *StructEncoder) run() error {
-	return setForeingFieldsValue(this.local, this.foreign, this.representation.Fields)
+func (this *StructEncoder) run() (err error) {
+	defer recoverPanic(&err, &this.local, &this.current)
+	if this.recordSkips || this.opts.skipHandler != nil {
+		unmatched := this.reprs.unmatchedFields(*this.representation, "")
+		this.opts.notifySkips(unmatched...)
+		if this.recordSkips {
+			this.skips = unmatched
+		}
+	}
+	if err := this.beforeMarshal(); err != nil {
+		return err
+	}
+	if err := this.setForeingFieldsValue(this.local, this.foreign, this.representation.Fields, "", 0); err != nil {
+		return this.current.wrap(this.local, err)
+	}
+	return this.afterMarshal()
+}
+
+// skip logs a field that could not be transferred, notifies the skip handler, and keeps track of it when the encoder
+// was requested to do so. Fields marked as required can't be skipped, an error describing the field is returned
+// instead.
+func (this *StructEncoder) skip(field string, path []string, required bool, reason SkipReason) error {
+	if this.opts.logger != nil {
+		this.opts.logger.Debug("field skipped", "field", field, "path", path, "reason", reason)
+	}
+	skip := FieldSkip{
+		Field:  field,
+		Path:   path,
+		Reason: reason,
+	}
+	if required {
+		return fmt.Errorf("%w %v", ErrRequiredField, skip)
+	}
+	this.opts.notifySkips(skip)
+	if this.recordSkips {
+		this.skips = append(this.skips, skip)
+	}
+	return nil
 }
 
 // setForeignFieldsValue copies values from the source struct to the destination struct
@@ -83,6 +129,8 @@ func (this *StructEncoder) run() error {
 //   - src: The reflect.Value of the source struct containing the data to be encoded
 //   - dst: The reflect.Value of the destination struct where data will be encoded to
 //   - reprFields: A slice of SourceField structs that define the mapping between fields
+//   - prefix: The local path of the parent field, used to report skipped fields
+//   - offset: How many elements of the foreign index paths were already walked to reach dst
 //
 // Returns:
 //   - error: Any error that occurred during the operation
@@ -92,14 +140,22 @@ func (this *StructEncoder) run() error {
 // 2. Properly processes slices and arrays (taking first element if non-empty)
 // 3. Iterates through each field in the representation mapping
 // 4. For nested structs, recursively calls itself
-// 5. For simple fields, uses setForeignFieldData to copy the value
+// 5. For slices of structs mapped to a foreign slice, or slices mapped through a `[*]` path, uses
+// setForeignSliceValue to encode every element
+// 6. For maps mapped to a foreign map, uses setForeignMapValue to copy every entry
+// 7. For simple fields, uses setForeignFieldData to copy the value
 //
 // This function is the core of the encoding process, mapping source values to
 // their corresponding destination fields according to the predefined representation.
-func setForeingFieldsValue(src, dst reflect.Value, reprFields []SourceField) error {
-	source, empty := digIntoLocalSource(src)
-	if empty {
-		return nil
+func (this *StructEncoder) setForeingFieldsValue(
+	src, dst reflect.Value,
+	reprFields []SourceField,
+	prefix string,
+	offset int,
+) error {
+	source, reason := digIntoLocalSource(src)
+	if reason != "" {
+		return this.skip(strings.TrimSuffix(prefix, "."), nil, false, reason)
 	}
 
 	target := dst
@@ -111,18 +167,72 @@ func setForeingFieldsValue(src, dst reflect.Value, reprFields []SourceField) err
 		target = target.Elem()
 	}
 
-	for _, field := range reprFields {
-		child, hasChild := localRepresentations[field.ChildRef]
-		foreign := foreignRepresentations[field.TargetRef]
+	for i, field := range reprFields {
+		this.current = fieldPath{prefix, &reprFields[i]}
+		child, hasChild := this.reprs.child(field)
+		foreign := this.reprs.target(field)
+		split, elementWise := foreign.splitElements(offset)
 
-		if hasChild {
-			err := setForeingFieldsValue(source.Field(field.Id), target, child.Fields)
+		if !field.Tag.encodes() || !this.opts.selected(prefix+field.Name) {
+			continue
+		}
+		if !this.reprs.conditionHolds(field, target, offset) {
+			continue
+		}
+		if field.Tag.Opts.Const != "" {
+			data, err := parseConstValue(field.Tag.Opts.Const, field.Type)
+			if err != nil {
+				return err
+			}
+			err = this.setForeignFieldData(foreign.IndexPath[offset:], foreign.Elements[offset:], target, data)
+			if err != nil {
+				return err
+			}
+		} else if field.Tag.Opts.Compute != "" {
+			err := this.setForeignComputedValue(field, source.Field(field.Id), target, foreign, prefix, offset)
+			if err != nil {
+				return err
+			}
+		} else if field.Converted {
+			err := this.setForeignConvertedValue(field, source.Field(field.Id), target, foreign, prefix, offset)
+			if err != nil {
+				return err
+			}
+		} else if field.JoinRefs != nil {
+			err := this.setForeignJoinedValue(field, source.Field(field.Id), target, prefix, offset)
+			if err != nil {
+				return err
+			}
+		} else if field.IsArray && elementWise && (hasChild || foreign.Wildcard > offset) {
+			err := this.setForeignSliceValue(field, child, source.Field(field.Id), target, foreign, prefix, offset, split)
+			if err != nil {
+				return err
+			}
+		} else if field.IsMap && foreign.IsMap {
+			err := this.setForeignMapValue(field, child, source.Field(field.Id), target, foreign, prefix, offset)
+			if err != nil {
+				return err
+			}
+		} else if hasChild {
+			if _, reason := digIntoLocalSource(source.Field(field.Id)); reason != "" {
+				if err := this.skip(prefix+field.Name, field.Tag.Path, field.Tag.Opts.Required, reason); err != nil {
+					return err
+				}
+				continue
+			}
+			err := this.setForeingFieldsValue(source.Field(field.Id), target, child.Fields, prefix+field.Name+".", offset)
 			if err != nil {
 				return err
 			}
 		} else {
-			data := source.Field(field.Id)
-			err := setForeignFieldData(foreign.IndexPath, target, data)
+			data, reason := digIntoLocalData(source.Field(field.Id), this.opts)
+			if reason != "" {
+				if err := this.skip(prefix+field.Name, foreign.Path, field.Tag.Opts.Required, reason); err != nil {
+					return err
+				}
+				continue
+			}
+			err := this.setForeignFieldData(foreign.IndexPath[offset:], foreign.Elements[offset:], target, data)
 			if err != nil {
 				return err
 			}
@@ -132,6 +242,280 @@ func setForeingFieldsValue(src, dst reflect.Value, reprFields []SourceField) err
 	return nil
 }
 
+// setForeignSliceValue encodes every element of a local slice into the corresponding element
+// of the foreign slice the field maps to, growing the foreign slice as needed.
+//
+// Parameters:
+//   - field: The local slice field being encoded
+//   - child: The representation of the slice element struct, empty when the elements aren't structs
+//   - src: The reflect.Value of the local slice
+//   - target: The reflect.Value of the foreign struct reached after walking `offset` elements of the index path
+//   - foreign: The foreign field the local slice maps to
+//   - prefix: The local path of the parent field, used to report skipped fields
+//   - offset: How many elements of the foreign index paths were already walked to reach target
+//   - split: How many elements of the foreign index path lead to the foreign slice
+//
+// Returns:
+//   - error: Any error that occurred encoding the elements
+//
+// The index paths of the element fields are absolute, as they share the path of the slice as prefix
+// the elements are encoded using split as offset. When the foreign path goes on after a `[*]` segment,
+// every local element is written to the rest of the path inside the corresponding foreign element.
+func (this *StructEncoder) setForeignSliceValue(
+	field SourceField,
+	child StructRepr,
+	src, target reflect.Value,
+	foreign TargetField,
+	prefix string,
+	offset, split int,
+) error {
+	if src.Len() == 0 {
+		return this.skip(prefix+field.Name, foreign.Path, field.Tag.Opts.Required, SkipEmptySlice)
+	}
+
+	dst := this.resolveForeignField(foreign.IndexPath[offset:split], foreign.Elements[offset:split], target)
+	length, err := this.growForeignSlice(dst, src.Len())
+	if err != nil {
+		return err
+	}
+	// the closure may run in other goroutines, capturing the field, child and foreign field would move them to the heap
+	name, childRef, childFields := field.Name, field.ChildRef, child.Fields
+	path, indexPath, elements := foreign.Path, foreign.IndexPath[split:], foreign.Elements[split:]
+	return this.forEachElement(length, func(encoder *StructEncoder, i int) error {
+		elemPrefix := elementPath(prefix, name, i)
+		if childRef == "" {
+			data, reason := digIntoLocalData(src.Index(i), encoder.opts)
+			if reason != "" {
+				return encoder.skip(strings.TrimSuffix(elemPrefix, "."), path, false, reason)
+			}
+			return encoder.setForeignFieldData(indexPath, elements, dst.Index(i), data)
+		}
+		return encoder.setForeingFieldsValue(src.Index(i), dst.Index(i), childFields, elemPrefix, split)
+	})
+}
+
+// forEachElement encodes the elements of a slice by index, splitting them among goroutines when the encoder was
+// created with WithParallelSlices and the slice is large enough. Every goroutine encodes its chunk with its own copy
+// of the encoder, the fields they skip are added to the encoder once every chunk is encoded.
+//
+// Parameters:
+//   - length: The number of elements to encode
+//   - encodeOne: Encodes the element of the given index with the given encoder
+//
+// Returns:
+//   - error: The error encoding the element with the lowest index among the failed ones, if any
+func (this *StructEncoder) forEachElement(length int, encodeOne func(*StructEncoder, int) error) error {
+	workers := this.opts.sliceParallelism(length)
+	if this.worker || workers <= 1 {
+		for i := range length {
+			if err := encodeOne(this, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	encoders := make([]StructEncoder, workers)
+	for w := range encoders {
+		encoders[w] = *this
+		encoders[w].worker = true
+		encoders[w].skips = nil
+	}
+	err := mapChunks(length, workers, func(w, from, to int) (err error) {
+		defer recoverPanic(&err, &encoders[w].local, &encoders[w].current)
+		for i := from; i < to; i++ {
+			if err := encodeOne(&encoders[w], i); err != nil {
+				return encoders[w].current.wrap(encoders[w].local, err)
+			}
+		}
+		return nil
+	})
+	for _, encoder := range encoders {
+		this.skips = append(this.skips, encoder.skips...)
+	}
+	return err
+}
+
+// setForeignJoinedValue splits the value of a local string field setting the `join<>` tag option on the literals of
+// the option, and writes every piece into the corresponding foreign field, converted into its type. An error is
+// returned when the value doesn't match the literals.
+func (this *StructEncoder) setForeignJoinedValue(
+	field SourceField,
+	src, target reflect.Value,
+	prefix string,
+	offset int,
+) error {
+	if this.opts.isEmpty(src) {
+		return this.skip(prefix+field.Name, field.Tag.Path, field.Tag.Opts.Required, SkipZeroValue)
+	}
+
+	pieces, ok := splitJoined(src.String(), field.Tag.Opts.Join)
+	if !ok {
+		return fmt.Errorf("%w %q in %v", ErrJoinMismatch, src.String(), prefix+field.Name)
+	}
+	for i, ref := range field.JoinRefs {
+		if ref == "" {
+			continue
+		}
+		foreign := this.reprs.joined(field, i)
+		dst := this.resolveForeignField(foreign.IndexPath[offset:], foreign.Elements[offset:], target)
+		data, err := parseConstValue(pieces[i], dst.Type())
+		if err != nil {
+			return err
+		}
+		if err := assignValue(dst, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setForeignComputedValue writes the value of a local field setting the `compute<name>` tag option into the
+// foreign value found at the field path using the inverse function registered under the same name, if any.
+// Fields computed by a function without an inverse are left out when encoding.
+func (this *StructEncoder) setForeignComputedValue(
+	field SourceField,
+	src, target reflect.Value,
+	foreign TargetField,
+	prefix string,
+	offset int,
+) error {
+	inverse, ok := lookupInverseFunc(field.Tag.Opts.Compute)
+	if !ok {
+		return nil
+	}
+	if this.opts.isEmpty(src) {
+		return this.skip(prefix+field.Name, field.Tag.Path, field.Tag.Opts.Required, SkipZeroValue)
+	}
+
+	dst := target
+	if field.TargetRef != "" {
+		dst = this.resolveForeignField(foreign.IndexPath[offset:], foreign.Elements[offset:], target)
+	} else if dst.Kind() == reflect.Pointer {
+		dst = dst.Elem()
+	}
+	if err := inverse(src.Interface(), dst.Addr().Interface()); err != nil {
+		return fmt.Errorf("%w %v for %v: %w", ErrComputeFunc, field.Tag.Opts.Compute, prefix+field.Name, err)
+	}
+	return nil
+}
+
+// setForeignConvertedValue writes the value of a converted local field into the foreign field it maps, converted by
+// the converter named by its `convert<name>` tag option or registered for their types. Zero values are skipped.
+func (this *StructEncoder) setForeignConvertedValue(
+	field SourceField,
+	src, target reflect.Value,
+	foreign TargetField,
+	prefix string,
+	offset int,
+) error {
+	if this.opts.isEmpty(src) {
+		return this.skip(prefix+field.Name, foreign.Path, field.Tag.Opts.Required, SkipZeroValue)
+	}
+	conv, _ := field.converter(foreign.Type)
+	data, err := conv.convertValue(src, true)
+	if err != nil {
+		return fmt.Errorf("%w in %v", err, prefix+field.Name)
+	}
+	return this.setForeignFieldData(foreign.IndexPath[offset:], foreign.Elements[offset:], target, data)
+}
+
+// setForeignMapValue replaces the foreign map the field maps to with a new map holding every entry of the
+// local map, merged with the entries already held by the foreign map according to the field merge mode.
+//
+// Parameters:
+//   - field: The local map field being encoded
+//   - child: The representation of the map value struct, empty when the values aren't structs
+//   - src: The reflect.Value of the local map
+//   - target: The reflect.Value of the foreign struct reached after walking `offset` elements of the index path
+//   - foreign: The foreign map field the local map maps to
+//   - prefix: The local path of the parent field, used to report skipped fields
+//   - offset: How many elements of the foreign index paths were already walked to reach target
+//
+// Returns:
+//   - error: Any error that occurred converting the keys or encoding the values
+//
+// Struct values are encoded over a copy of the foreign value found under the same key, if any, so foreign
+// fields not mapped by the local struct are kept. They use the length of the foreign map index path as offset,
+// as the index paths of their fields share the path of the map as prefix. Keys already present in the foreign
+// map are left untouched with the MapMergeKeep mode.
+func (this *StructEncoder) setForeignMapValue(
+	field SourceField,
+	child StructRepr,
+	src, target reflect.Value,
+	foreign TargetField,
+	prefix string,
+	offset int,
+) error {
+	if this.opts.isEmpty(src) {
+		return this.skip(prefix+field.Name, foreign.Path, field.Tag.Opts.Required, SkipZeroValue)
+	}
+
+	dst := this.resolveForeignField(foreign.IndexPath[offset:], foreign.Elements[offset:], target)
+	if src.IsNil() {
+		dst.SetZero()
+		return nil
+	}
+
+	if this.opts.logger != nil {
+		this.opts.logger.Debug("allocating foreign map", "type", dst.Type().String(), "length", src.Len())
+	}
+	mode := this.opts.mapMergeMode(field.Tag)
+	entries := newMergedMap(dst, src.Len(), mode)
+	iter := src.MapRange()
+	for iter.Next() {
+		key, err := convertMapKey(iter.Key(), dst.Type().Key())
+		if err != nil {
+			return err
+		}
+		existing := dst.MapIndex(key)
+		if mode == MapMergeKeep && existing.IsValid() {
+			continue
+		}
+		value := reflect.New(dst.Type().Elem()).Elem()
+		if field.ChildRef == "" {
+			value.Set(iter.Value())
+		} else {
+			if existing.IsValid() {
+				value.Set(existing)
+			}
+			elemPrefix := fmt.Sprintf("%v%v[%v].", prefix, field.Name, iter.Key())
+			err := this.setForeingFieldsValue(iter.Value(), value, child.Fields, elemPrefix, len(foreign.IndexPath))
+			if err != nil {
+				return err
+			}
+		}
+		entries.SetMapIndex(key, value)
+	}
+	dst.Set(entries)
+
+	return nil
+}
+
+// growForeignSlice makes sure a foreign slice holds at least `length` elements, allocating a bigger slice
+// and copying the existing elements when needed. Arrays can't grow, so their length is the limit.
+//
+// Returns:
+//   - int: The number of elements that can be written into the collection
+//   - error: An error if the elements don't fit a foreign fixed-size array
+func (this *StructEncoder) growForeignSlice(dst reflect.Value, length int) (int, error) {
+	if dst.Kind() == reflect.Array {
+		if length > dst.Len() {
+			return 0, fmt.Errorf("%w %v elements into %v", ErrArrayOverflow, length, dst.Type())
+		}
+		return length, nil
+	}
+	if dst.Len() < length {
+		if this.opts.logger != nil {
+			this.opts.logger.Debug("allocating foreign slice", "type", dst.Type().String(), "length", length)
+		}
+		grown := reflect.MakeSlice(dst.Type(), length, length)
+		reflect.Copy(grown, dst)
+		dst.Set(grown)
+	}
+	return length, nil
+}
+
 // digIntoLocalSource handles pointer and collection types in the source value.
 // It dereferences pointers and extracts the first element from slices/arrays.
 //
@@ -140,7 +524,7 @@ func setForeingFieldsValue(src, dst reflect.Value, reprFields []SourceField) err
 //
 // Returns:
 //   - reflect.Value: The processed value after dereferencing and element extraction
-//   - bool: True if the source is empty (nil pointer or empty collection), false otherwise
+//   - SkipReason: Not empty if the source is empty (nil pointer or empty collection)
 //
 // The function:
 // 1. Dereferences pointers (returning empty flag if nil)
@@ -149,20 +533,20 @@ func setForeingFieldsValue(src, dst reflect.Value, reprFields []SourceField) err
 //
 // This is used to normalize source values before mapping them to destination fields,
 // ensuring consistent handling of various input types.
-func digIntoLocalSource(source reflect.Value) (reflect.Value, bool) {
+func digIntoLocalSource(source reflect.Value) (reflect.Value, SkipReason) {
 	if source.Kind() == reflect.Ptr {
 		if source.IsNil() {
-			return source, true // ignore empty pointers
+			return source, SkipNilPointer // ignore empty pointers
 		}
 		source = source.Elem()
 	}
 	if source.Kind() == reflect.Slice || source.Kind() == reflect.Array {
 		if source.Len() == 0 {
-			return source, true // ignore empty slices
+			return source, SkipEmptySlice // ignore empty slices
 		}
 		source = source.Index(0)
 	}
-	return source, false
+	return source, ""
 }
 
 // setForeignFieldData sets a value from the source struct to a field in the destination struct.
@@ -170,24 +554,40 @@ func digIntoLocalSource(source reflect.Value) (reflect.Value, bool) {
 //
 // Parameters:
 //   - path: A slice of field indices representing the path to the target field in the destination struct
+//   - elements: The element traversed in each collection found along the path, see TargetField.Elements
 //   - target: The reflect.Value of the destination struct
-//   - data: The reflect.Value containing the data to be set
+//   - data: The reflect.Value containing the data to be set, already processed by digIntoLocalData
 //
 // Returns:
 //   - error: Any error that occurred during the operation
 //
 // The function follows these steps:
-// 1. Checks if the data is valid (not zero or nil)
-// 2. Handles pointer dereferencing for both source and destination
-// 3. Navigates through the path to the target field
-// 4. Creates necessary structures (slices, maps) if they don't exist
-// 5. Sets the data to the target field
-func setForeignFieldData(path []int, target reflect.Value, data reflect.Value) error {
-	data, empty := digIntoLocalData(data)
-	if empty {
-		return nil
-	}
+// 1. Handles pointer dereferencing for the destination
+// 2. Navigates through the path to the target field
+// 3. Creates necessary structures (slices, maps) if they don't exist
+// 4. Sets the data to the target field, copying the elements when transferring between slices and arrays
+func (this *StructEncoder) setForeignFieldData(
+	path []int,
+	elements [][]int,
+	target reflect.Value,
+	data reflect.Value,
+) error {
+	dst := this.resolveForeignField(path, elements, target)
+	return assignValue(dst, data)
+}
 
+// resolveForeignField walks a path of field indices from the destination struct, creating any nil pointer, empty
+// slice or concrete value asserted by the `as<>` option found along the way, and returns the settable value of the
+// final field.
+//
+// Parameters:
+//   - path: A slice of field indices representing the path to the target field in the destination struct
+//   - elements: The element traversed in each collection found along the path, see TargetField.Elements
+//   - target: The reflect.Value of the destination struct
+//
+// Returns:
+//   - reflect.Value: The value of the field at the end of the path, or target itself when the path is empty
+func (this *StructEncoder) resolveForeignField(path []int, elements [][]int, target reflect.Value) reflect.Value {
 	// handle target if pointer
 	if target.Kind() == reflect.Ptr {
 		if target.IsNil() {
@@ -198,23 +598,41 @@ func setForeignFieldData(path []int, target reflect.Value, data reflect.Value) e
 
 	dst := target
 	for idx, fieldId := range path {
-		dst = descendIntoLocalArrayField(dst)
+		var levels []int
+		if idx > 0 {
+			levels = elements[idx-1] // dst holds the field reached on the previous step
+		}
+		dst = this.descendIntoLocalArrayField(dst, levels)
+		if fieldId < 0 {
+			dst = this.allocateConcreteValue(dst, concreteType(fieldId))
+			continue
+		}
 
 		if dst.Kind() == reflect.Pointer {
 			if dst.IsNil() {
+				if this.opts.logger != nil {
+					this.opts.logger.Debug("allocating foreign value", "type", dst.Type().String())
+				}
 				dst.Set(reflect.New(dst.Type().Elem()))
 			}
 			dst = dst.Elem()
 		}
 		dst = dst.Field(fieldId)
+	}
+
+	return dst
+}
 
-		if idx == len(path)-1 {
-			dst.Set(data)
-			return nil
+// allocateConcreteValue returns the struct pointed by the value an interface field holds, see RegisterConcreteType,
+// setting the field to a newly allocated one when it's nil or holds a value of another type.
+func (this *StructEncoder) allocateConcreteValue(dst reflect.Value, concrete reflect.Type) reflect.Value {
+	if dst.IsNil() || dst.Elem().Type() != concrete || dst.Elem().IsNil() {
+		if this.opts.logger != nil {
+			this.opts.logger.Debug("allocating foreign concrete value", "type", concrete.String())
 		}
+		dst.Set(reflect.New(concrete.Elem()))
 	}
-
-	return nil
+	return dst.Elem().Elem()
 }
 
 // digIntoLocalData handles nil and zero values in the source data.
@@ -222,29 +640,31 @@ func setForeignFieldData(path []int, target reflect.Value, data reflect.Value) e
 //
 // Parameters:
 //   - data: The reflect.Value to be processed
+//   - opts: The options holding the zero-value semantics
 //
 // Returns:
 //   - reflect.Value: The processed value after dereferencing
-//   - bool: True if the data is empty (invalid, zero, or nil pointer), false otherwise
+//   - SkipReason: Not empty if the data is empty (invalid, zero, or nil pointer)
 //
 // The function:
-// 1. Checks if the value is valid or zero (returning empty flag if invalid/zero)
+// 1. Checks if the value is valid or zero (returning empty flag if invalid/zero, unless its kind zero value
+// is configured to be copied)
 // 2. Dereferences pointers (returning empty flag if nil)
 // 3. Returns the resulting value and whether it should be considered empty
 //
 // This is used to ensure that only valid, non-empty values are set on the destination,
 // avoiding attempts to set invalid or zero values which could cause errors.
-func digIntoLocalData(data reflect.Value) (reflect.Value, bool) {
-	if !data.IsValid() || data.IsZero() {
-		return data, true
+func digIntoLocalData(data reflect.Value, opts *options) (reflect.Value, SkipReason) {
+	if opts.isEmpty(data) {
+		return data, SkipZeroValue
 	}
 	if data.Kind() == reflect.Ptr {
 		if data.IsNil() {
-			return data, true // empty data, do nothing
+			return data, SkipNilPointer // empty data, do nothing
 		}
 		data = data.Elem()
 	}
-	return data, false
+	return data, ""
 }
 
 // descendIntoLocalArrayField handles complex data structures like maps, arrays, and slices
@@ -252,27 +672,32 @@ func digIntoLocalData(data reflect.Value) (reflect.Value, bool) {
 //
 // Parameters:
 //   - dst: The reflect.Value representing the current destination field
+//   - elements: The element to traverse on each collection level, the first one or the last one when set
+//     to lastElement, levels without an entry traverse the first element
 //
 // Returns:
 //   - reflect.Value: That is either same as the input (if not an array/slice)
-//     or the first element of the array/slice
+//     or the requested element of the innermost array/slice
 //
 // The function:
 // 1. Checks if the current value is a map, array, or slice
-// 2. If empty/nil, initializes it appropriately
-// 3. For empty collections, creates and appends a new element
-// 4. Returns the first element for further traversal
+// 2. If empty/nil, initializes it appropriately (fixed-size arrays are left as they are)
+// 3. For empty slices, creates and appends a new element
+// 4. Returns the requested element for further traversal, going through every level of nested collections
 //
 // This allows the encoder to properly navigate through nested collections
 // while ensuring all necessary structures are created along the path.
-func descendIntoLocalArrayField(dst reflect.Value) reflect.Value {
-	kind := dst.Kind()
-	if kind == reflect.Slice || kind == reflect.Array {
-		if dst.IsNil() {
+func (this *StructEncoder) descendIntoLocalArrayField(dst reflect.Value, elements []int) reflect.Value {
+	for level := 0; dst.Kind() == reflect.Slice || dst.Kind() == reflect.Array; level++ {
+		kind := dst.Kind()
+		if kind == reflect.Slice && dst.IsNil() {
+			if this.opts.logger != nil {
+				this.opts.logger.Debug("allocating foreign slice", "type", dst.Type().String())
+			}
 			slice := reflect.MakeSlice(dst.Type(), 0, 1)
 			dst.Set(slice)
 		}
-		if dst.Len() == 0 {
+		if kind == reflect.Slice && dst.Len() == 0 {
 			var newType reflect.Type
 			var isPointer bool
 			if dst.Type().Elem().Kind() == reflect.Pointer {
@@ -287,7 +712,18 @@ func descendIntoLocalArrayField(dst reflect.Value) reflect.Value {
 			}
 			dst.Set(reflect.Append(dst, new))
 		}
-		dst = dst.Index(0)
+		if dst.Len() == 0 {
+			break // empty fixed-size arrays can't be traversed
+		}
+
+		element := 0
+		if level < len(elements) {
+			element = elements[level]
+		}
+		if element == lastElement {
+			element = dst.Len() - 1
+		}
+		dst = dst.Index(element)
 	}
 
 	return dst
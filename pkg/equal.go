@@ -0,0 +1,279 @@
+package pkg
+
+import (
+	"reflect"
+)
+
+// MappedEqual reports whether the foreign object already reflects the values of the local struct, that is,
+// whether calling Marshal(local, foreign) would leave every mapped foreign field unchanged. Reconcile loops can
+// use it to cheaply skip no-op API updates.
+//
+// Parameters:
+//   - local: The struct (or non-nil pointer to struct) annotated with `se` tags
+//   - foreign: The struct (or non-nil pointer to struct) to compare against
+//
+// Returns:
+//   - bool: true if every mapped foreign field holds the same value as its local counterpart
+//   - error: An error if the types are invalid or introspection fails
+//
+// Local values skipped by Marshal (zero values, nil pointers and empty slices) are ignored, as encoding
+// them would not modify the foreign object either.
+func MappedEqual(local, foreign interface{}) (bool, error) {
+	l := reflect.ValueOf(local)
+	f := reflect.ValueOf(foreign)
+	if l.Kind() == reflect.Pointer && l.IsNil() {
+		return false, ErrLocalTypeNotStruct
+	}
+	if f.Kind() == reflect.Pointer && f.IsNil() {
+		return false, ErrForeignTypeNotStruct
+	}
+
+	repr := &StructRepr{}
+	if err := repr.introspect(local, foreign, defaultCodec.opts); err != nil {
+		return false, err
+	}
+
+	return mappedFieldsEqual(l, f, repr.Fields, 0)
+}
+
+// mappedFieldsEqual compares every mapped local field with the foreign field it would be encoded into,
+// walking the representation the same way setForeingFieldsValue does.
+//
+// Parameters:
+//   - src: The reflect.Value of the local struct
+//   - dst: The reflect.Value of the foreign struct
+//   - reprFields: A slice of SourceField structs that define the mapping between fields
+//   - offset: How many elements of the foreign index paths were already walked to reach dst
+//
+// Returns:
+//   - bool: true if no mapped foreign field differs from the local value
+//   - error: Any error that occurred reading the foreign fields
+func mappedFieldsEqual(src, dst reflect.Value, reprFields []SourceField, offset int) (bool, error) {
+	source, reason := digIntoLocalSource(src)
+	if reason != "" {
+		return true, nil
+	}
+
+	for _, field := range reprFields {
+		child, hasChild := defaultCodec.opts.cache.representations().child(field)
+		foreign := defaultCodec.opts.cache.representations().target(field)
+		split, elementWise := foreign.splitElements(offset)
+
+		if !field.Tag.encodes() {
+			continue
+		}
+		if !defaultCodec.opts.cache.representations().conditionHolds(field, dst, offset) {
+			continue
+		}
+		if field.Tag.Opts.Const != "" {
+			equal, err := mappedConstEqual(field, dst, foreign.IndexPath[offset:], foreign.Elements[offset:])
+			if err != nil || !equal {
+				return equal, err
+			}
+			continue
+		}
+
+		if field.Tag.Opts.Compute != "" {
+			equal, err := mappedComputedEqual(source.Field(field.Id), dst, field, offset)
+			if err != nil || !equal {
+				return equal, err
+			}
+			continue
+		}
+
+		if field.Converted {
+			equal, err := mappedConvertedEqual(source.Field(field.Id), dst, field, foreign, offset)
+			if err != nil || !equal {
+				return equal, err
+			}
+			continue
+		}
+
+		if field.JoinRefs != nil {
+			equal := mappedJoinedEqual(source.Field(field.Id), dst, field, offset)
+			if !equal {
+				return false, nil
+			}
+			continue
+		}
+
+		if field.IsArray && elementWise && (hasChild || foreign.Wildcard > offset) {
+			equal, err := mappedSliceEqual(source.Field(field.Id), dst, field, child, foreign, offset, split)
+			if err != nil || !equal {
+				return equal, err
+			}
+			continue
+		}
+
+		if field.IsMap && foreign.IsMap {
+			equal, err := mappedMapEqual(source.Field(field.Id), dst, field, child, foreign, offset)
+			if err != nil || !equal {
+				return equal, err
+			}
+			continue
+		}
+
+		if hasChild {
+			equal, err := mappedFieldsEqual(source.Field(field.Id), dst, child.Fields, offset)
+			if err != nil || !equal {
+				return equal, err
+			}
+			continue
+		}
+
+		equal, err := mappedValueEqual(source.Field(field.Id), dst, foreign.IndexPath[offset:], foreign.Elements[offset:])
+		if err != nil || !equal {
+			return equal, err
+		}
+	}
+
+	return true, nil
+}
+
+// mappedMapEqual compares every entry of a local map with the entry found under the same key in the foreign map
+// it would be encoded into. Foreign entries missing from the local map are ignored only when the local map would
+// be skipped or merged into the foreign map, as otherwise encoding replaces the whole foreign map. When keeping
+// existing entries on merge, only the presence of the local keys is checked.
+func mappedMapEqual(
+	src, dst reflect.Value,
+	field SourceField,
+	child StructRepr,
+	foreign TargetField,
+	offset int,
+) (bool, error) {
+	if defaultCodec.opts.isEmpty(src) {
+		return true, nil
+	}
+	mode := defaultCodec.opts.mapMergeMode(field.Tag)
+	entries, reason := resolveForeignValue(foreign.IndexPath[offset:], foreign.Elements[offset:], dst)
+	if reason != "" || (mode == MapReplace && entries.Len() != src.Len()) {
+		return false, nil
+	}
+	iter := src.MapRange()
+	for iter.Next() {
+		key, err := convertMapKey(iter.Key(), entries.Type().Key())
+		if err != nil {
+			return false, err
+		}
+		elem := entries.MapIndex(key)
+		if !elem.IsValid() {
+			return false, nil
+		}
+		if mode == MapMergeKeep {
+			continue
+		}
+		if field.ChildRef == "" {
+			if !reflect.DeepEqual(iter.Value().Interface(), elem.Interface()) {
+				return false, nil
+			}
+			continue
+		}
+		equal, err := mappedFieldsEqual(iter.Value(), elem, child.Fields, len(foreign.IndexPath))
+		if err != nil || !equal {
+			return equal, err
+		}
+	}
+	return true, nil
+}
+
+// mappedValueEqual compares a local value with the foreign field found walking path from dst. Local values
+// skipped by the encoder are considered equal.
+func mappedValueEqual(src, dst reflect.Value, path []int, elements [][]int) (bool, error) {
+	data, reason := digIntoLocalData(src, defaultCodec.opts)
+	if reason != "" {
+		return true, nil
+	}
+	foreignData, _, err := getForeignFieldData(path, elements, dst, defaultCodec.opts)
+	if err != nil {
+		return false, err
+	}
+	return foreignData.IsValid() && reflect.DeepEqual(data.Interface(), foreignData.Interface()), nil
+}
+
+// mappedConstEqual compares the value of a field's `const<>` option with the foreign field it would be written into.
+func mappedConstEqual(field SourceField, dst reflect.Value, path []int, elements [][]int) (bool, error) {
+	data, err := parseConstValue(field.Tag.Opts.Const, field.Type)
+	if err != nil {
+		return false, err
+	}
+	foreignData, reason := resolveForeignValue(path, elements, dst)
+	if reason != "" {
+		return false, nil
+	}
+	return reflect.DeepEqual(data.Interface(), foreignData.Interface()), nil
+}
+
+// mappedJoinedEqual compares the value of a local field setting the `join<>` tag option with the value joined from
+// the foreign fields it would be split into.
+func mappedJoinedEqual(src, dst reflect.Value, field SourceField, offset int) bool {
+	if defaultCodec.opts.isEmpty(src) {
+		return true
+	}
+	joined, reason := defaultCodec.opts.cache.representations().joinForeignValues(field, dst, offset, defaultCodec.opts)
+	return reason == "" && joined == src.String()
+}
+
+// mappedComputedEqual compares the value of a local field setting the `compute<name>` tag option with the value
+// the registered function derives from the foreign value. Fields computed by a function without an inverse
+// aren't encoded, so they're ignored.
+func mappedComputedEqual(src, dst reflect.Value, field SourceField, offset int) (bool, error) {
+	if _, ok := lookupInverseFunc(field.Tag.Opts.Compute); !ok || defaultCodec.opts.isEmpty(src) {
+		return true, nil
+	}
+	value, reason := defaultCodec.opts.cache.representations().resolveComputeSource(field, dst, offset)
+	if reason != "" {
+		return false, nil
+	}
+	data, err := computeValue(field, value)
+	if err != nil || !data.IsValid() {
+		return false, err
+	}
+	return reflect.DeepEqual(src.Interface(), data.Interface()), nil
+}
+
+// mappedConvertedEqual compares the value of a converted local field, converted by its converter, with the foreign
+// field it would be written into.
+func mappedConvertedEqual(src, dst reflect.Value, field SourceField, foreign TargetField, offset int) (bool, error) {
+	if defaultCodec.opts.isEmpty(src) {
+		return true, nil
+	}
+	conv, _ := field.converter(foreign.Type)
+	data, err := conv.convertValue(src, true)
+	if err != nil {
+		return false, err
+	}
+	foreignData, reason := resolveForeignValue(foreign.IndexPath[offset:], foreign.Elements[offset:], dst)
+	return reason == "" && reflect.DeepEqual(data.Interface(), foreignData.Interface()), nil
+}
+
+// mappedSliceEqual compares every element of a local slice with the corresponding element of the
+// foreign slice it would be encoded into.
+func mappedSliceEqual(
+	src, dst reflect.Value,
+	field SourceField,
+	child StructRepr,
+	foreign TargetField,
+	offset, split int,
+) (bool, error) {
+	if src.Len() == 0 {
+		return true, nil
+	}
+	elems, reason := resolveForeignValue(foreign.IndexPath[offset:split], foreign.Elements[offset:split], dst)
+	if reason != "" || elems.Len() < src.Len() {
+		return false, nil
+	}
+	for i := range src.Len() {
+		if field.ChildRef == "" {
+			equal, err := mappedValueEqual(src.Index(i), elems.Index(i), foreign.IndexPath[split:], foreign.Elements[split:])
+			if err != nil || !equal {
+				return equal, err
+			}
+			continue
+		}
+		equal, err := mappedFieldsEqual(src.Index(i), elems.Index(i), child.Fields, split)
+		if err != nil || !equal {
+			return equal, err
+		}
+	}
+	return true, nil
+}
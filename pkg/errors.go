@@ -0,0 +1,189 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldNotFoundError is returned during introspection when the path of a local field tag
+// doesn't exist in the foreign type. Use errors.As to react to specific missing paths
+// without parsing error strings.
+type FieldNotFoundError struct {
+	LocalField  string   // name of the local struct field holding the tag
+	Path        []string // foreign path that could not be resolved
+	ForeignType string   // name of the foreign type the path was resolved against
+}
+
+func (this *FieldNotFoundError) Error() string {
+	return fmt.Sprintf("%v %v (local field %v, foreign type %v)",
+		ErrForeignTypeMissingField, this.Path, this.LocalField, this.ForeignType)
+}
+
+// Unwrap returns ErrForeignTypeMissingField, so missing paths can be checked with errors.Is too.
+func (this *FieldNotFoundError) Unwrap() error {
+	return ErrForeignTypeMissingField
+}
+
+// PathError is returned by GetPath and SetPath when a path leads to no field, naming the segment that could not be
+// resolved, and the index it holds when that's the reason. It wraps the *FieldNotFoundError found resolving the path,
+// along with ErrUnsupportedIndex when the index isn't supported.
+type PathError struct {
+	Path    string // path that could not be resolved
+	Type    string // name of the struct type the path was resolved against
+	Segment string // segment of the path leading to no field
+	Index   string // unsupported index of the segment, eg `[3]`, if any
+	Err     error
+}
+
+func (this *PathError) Error() string {
+	if this.Index != "" {
+		return fmt.Sprintf("%v %v in segment %v of path %v (type %v)",
+			ErrUnsupportedIndex, this.Index, this.Segment, this.Path, this.Type)
+	}
+	return fmt.Sprintf("%v segment %v of path %v (type %v)",
+		ErrForeignTypeMissingField, this.Segment, this.Path, this.Type)
+}
+
+// Unwrap returns the error found resolving the path, along with ErrUnsupportedIndex when the index is the reason.
+func (this *PathError) Unwrap() []error {
+	if this.Index != "" {
+		return []error{ErrUnsupportedIndex, this.Err}
+	}
+	return []error{this.Err}
+}
+
+// newPathError wraps the *FieldNotFoundError found resolving a path into a *PathError, naming the first index of
+// the segment that isn't supported, if any. Any other error is returned as is.
+//
+// Parameters:
+//   - path: The path that could not be resolved
+//   - err: The error found resolving it
+//
+// Returns:
+//   - error: The *PathError describing the path, or err
+func newPathError(path string, err error) error {
+	var notFound *FieldNotFoundError
+	if !errors.As(err, &notFound) || len(notFound.Path) == 0 {
+		return err
+	}
+	pathErr := &PathError{Path: path, Type: notFound.ForeignType, Segment: notFound.Path[0], Err: err}
+	for _, index := range pathIndexesRegex.FindAllString(pathErr.Segment, -1) {
+		if !pathIndexRegex.MatchString(index) {
+			pathErr.Index = index
+			break
+		}
+	}
+	return pathErr
+}
+
+// FieldError wraps an error found introspecting the tag of a local field, or mapping its value, identifying the
+// field, its tag and the foreign path it maps. errors.Is and errors.As keep matching the wrapped error.
+type FieldError struct {
+	LocalType string // local struct type the field path starts at
+	Field     string // path of the field from the local type, eg `Items[2].Name` when mapping values
+	Tag       string // tag of the field, as declared or registered
+	Path      string // foreign path the field maps, resolved under the paths of its parents
+	Err       error
+}
+
+func (this *FieldError) Error() string {
+	return fmt.Sprintf("%v (field %v of %v, tag %q, path %v)", this.Err, this.Field, this.LocalType, this.Tag, this.Path)
+}
+
+func (this *FieldError) Unwrap() error {
+	return this.Err
+}
+
+// newFieldError wraps an error found with a field into a *FieldError, unless it already identifies a field, eg when
+// found in a nested struct.
+//
+// Parameters:
+//   - local: The local struct type the field path starts at
+//   - field: The path of the field from the local type
+//   - tag: The tag of the field
+//   - err: The error found
+//
+// Returns:
+//   - error: The wrapped error
+func newFieldError(local reflect.Type, field string, tag FieldTag, err error) error {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return err
+	}
+	return &FieldError{
+		LocalType: derefType(local).String(),
+		Field:     field,
+		Tag:       tag.Raw,
+		Path:      strings.Join(tag.Path, "."),
+		Err:       err,
+	}
+}
+
+// nestFieldError wraps an error found introspecting the struct held by a field, prefixing the path of the nested
+// field it identifies with the name of the field, so it's identified from the outer local type.
+//
+// Parameters:
+//   - local: The local struct type holding the field
+//   - field: The name of the field holding the nested struct
+//   - tag: The tag of the field
+//   - err: The error found
+//
+// Returns:
+//   - error: The wrapped error
+func nestFieldError(local reflect.Type, field string, tag FieldTag, err error) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap() // every invalid field of the nested struct, see WithAllErrors
+		nested := make([]error, len(errs))
+		for i, err := range errs {
+			nested[i] = nestFieldError(local, field, tag, err)
+		}
+		return errors.Join(nested...)
+	}
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		return newFieldError(local, field, tag, err)
+	}
+	fieldErr.LocalType = derefType(local).String()
+	fieldErr.Field = field + "." + fieldErr.Field
+	return err
+}
+
+// flattenErrors returns the errors joined by errors.Join, or the error itself.
+func flattenErrors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// fieldPath is the local path of the field being mapped, kept as the path of its parent struct and the field so it's
+// tracked without building the whole path for every field.
+type fieldPath struct {
+	prefix string
+	field  *SourceField
+}
+
+// wrap wraps an error found mapping the field into a *FieldError, see newFieldError.
+func (this fieldPath) wrap(local reflect.Value, err error) error {
+	if this.field == nil || err == nil {
+		return err
+	}
+	return newFieldError(local.Type(), this.prefix+this.field.Name, this.field.Tag, err)
+}
+
+// recoverPanic turns a panic raised while mapping values into an error wrapping ErrMappingPanic, identifying the
+// field being mapped, so failures surface as errors instead of crashing the caller. It must be deferred.
+//
+// Parameters:
+//   - err: The error returned by the function deferring it, set when a panic is recovered
+//   - local: The local value being mapped
+//   - field: The field being mapped, read once the panic is recovered
+func recoverPanic(err *error, local *reflect.Value, field *fieldPath) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	*err = field.wrap(*local, fmt.Errorf("%w %v", ErrMappingPanic, recovered))
+}
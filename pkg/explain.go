@@ -0,0 +1,287 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Explain introspects the provided local and foreign objects and returns a human readable description of how
+// every tagged local field maps into the foreign type, one field per line and indented by nesting level.
+//
+// Parameters:
+//   - local: The source object whose structure will be analyzed for mapping based on `se` tag.
+//   - foreign: The target object whose structure will receive mapped data.
+//
+// Returns:
+//   - string: The description of the mapping
+//   - error: An error if the introspection process fails, nil on success.
+//
+// For fields using the type matching option the selected `types<>` entry is shown along with the precedence
+// it was selected by, and fields dismissed because no entry matched are listed as skipped.
+func Explain(local, foreign interface{}) (string, error) {
+	repr := &StructRepr{}
+	if err := repr.introspect(local, foreign, defaultCodec.opts); err != nil {
+		return "", err
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%v -> %v\n", derefType(reflect.TypeOf(local)), derefType(reflect.TypeOf(foreign)))
+	explainFields(b, *repr, defaultCodec.opts.cache.representations(), 1)
+
+	return b.String(), nil
+}
+
+// Describe introspects the provided local and foreign objects and returns the representation of how every tagged
+// local field maps into the foreign type. Representations can be encoded as JSON, see StructRepr.MarshalJSON, so
+// the computed mapping of critical pairs of types can be snapshotted in golden tests.
+//
+// Parameters:
+//   - local: The source object whose structure will be analyzed for mapping based on `se` tag.
+//   - foreign: The target object whose structure will receive mapped data.
+//   - opts: The options the mapping is computed with, see Option
+//
+// Returns:
+//   - StructRepr: The representation of the mapping
+//   - error: An error if the introspection process fails, nil on success.
+func Describe(local, foreign interface{}, opts ...Option) (StructRepr, error) {
+	repr := StructRepr{}
+	o := newOptions(opts...)
+	err := repr.introspect(local, foreign, o)
+	repr.reprs = o.cache.representations()
+	return repr, err
+}
+
+// IntrospectRepr introspects the provided local and foreign objects just like Introspect, and returns the
+// representation of the mapping along with the representations it references, so it can be inspected with its
+// accessor methods even after the introspection cache is cleared.
+//
+// Parameters:
+//   - local: The source object whose structure will be analyzed for mapping based on `se` tag.
+//   - foreign: The target object whose structure will receive mapped data.
+//   - opts: The options the mapping is computed with, see Option
+//
+// Returns:
+//   - *StructRepr: The representation of the mapping
+//   - error: An error if the introspection process fails, nil on success.
+func IntrospectRepr(local, foreign interface{}, opts ...Option) (*StructRepr, error) {
+	repr := &StructRepr{}
+	o := newOptions(opts...)
+	if err := repr.introspect(local, foreign, o); err != nil {
+		return nil, err
+	}
+	repr.reprs = o.cache.representations().snapshot(*repr)
+	return repr, nil
+}
+
+// ForeignType returns the foreign struct type the representation maps, nil for representations decoded from JSON.
+func (this StructRepr) ForeignType() reflect.Type {
+	return this.foreignType
+}
+
+// Field returns the mapped local field of the given name, its path through nested structs for their fields, eg
+// `Spec.Replicas`. For fields mapped by more than one entry, eg when setting alternative paths, the first one is
+// returned.
+func (this StructRepr) Field(name string) (SourceField, bool) {
+	parent, rest, nested := strings.Cut(name, ".")
+	for _, field := range this.Fields {
+		if field.Name != parent {
+			continue
+		}
+		if !nested {
+			return field, true
+		}
+		child, ok := this.references().locals[field.ChildRef]
+		if !ok {
+			return SourceField{}, false
+		}
+		child.reprs = this.reprs
+		return child.Field(rest)
+	}
+	return SourceField{}, false
+}
+
+// PathFor returns the foreign path a local field maps, its path through nested structs for their fields, eg
+// `Spec.Replicas`. Fields computed from, or joining, several foreign fields have no path of their own.
+func (this StructRepr) PathFor(name string) ([]string, bool) {
+	field, ok := this.Field(name)
+	if !ok {
+		return nil, false
+	}
+	target, ok := this.references().foreigns[field.TargetRef]
+	if !ok {
+		return nil, false
+	}
+	return target.Path, true
+}
+
+// references returns the representations the fields of the representation reference.
+func (this StructRepr) references() representations {
+	if this.reprs.locals != nil {
+		return this.reprs
+	}
+	return sharedCache.representations()
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// explainFields writes a line for each mapped and dismissed field of a representation, in declaration order,
+// recursing into the representations of nested structs resolved with reprs.
+func explainFields(b *strings.Builder, repr StructRepr, reprs representations, depth int) {
+	indent := strings.Repeat("  ", depth)
+	fields := slices.Concat(repr.Fields, repr.Unmatched)
+	slices.SortFunc(fields, func(a, b SourceField) int { return a.Id - b.Id })
+
+	for _, field := range fields {
+		if field.Tag.Unmatched {
+			fmt.Fprintf(b, "%v%v: skipped, no types<> entry matched %v\n", indent, field.Name, repr.ForeignRootType)
+			continue
+		}
+		if field.Tag.Missing {
+			path := strings.Join(field.Tag.Path, ".")
+			fmt.Fprintf(b, "%v%v: skipped, optional path %v not found in %v\n", indent, field.Name, path, repr.ForeignRootType)
+			continue
+		}
+
+		path := strings.Join(field.Tag.Path, ".")
+		if path == "" {
+			path = "(root)"
+		}
+		fmt.Fprintf(b, "%v%v -> %v", indent, field.Name, path)
+		if match := field.Tag.Match; match.Precedence != NoTypeMatch {
+			fmt.Fprintf(b, " [types<%v> matched by %v]", match.Name, match.Precedence)
+		}
+		if field.Tag.Opts.Const != "" {
+			fmt.Fprintf(b, " [const<%v>]", field.Tag.Opts.Const)
+		}
+		if when := field.Tag.Opts.When; when != nil {
+			fmt.Fprintf(b, " [when<%v=%v>]", strings.Join(when.Path, "."), when.Value)
+		}
+		if field.Tag.Opts.Compute != "" {
+			fmt.Fprintf(b, " [compute<%v>]", field.Tag.Opts.Compute)
+		}
+		if field.Tag.Opts.Convert != "" {
+			fmt.Fprintf(b, " [convert<%v>]", field.Tag.Opts.Convert)
+		} else if field.Converted {
+			fmt.Fprintf(b, " [converted from %v]", reprs.foreigns[field.TargetRef].Type)
+		}
+		if len(field.Tag.Opts.Join) > 0 {
+			fmt.Fprintf(b, " [join<%v>]", joinDescription(field.Tag.Opts.Join))
+		}
+		if field.Tag.Fallback {
+			b.WriteString(" [fallback]")
+		}
+		if field.Tag.Opts.ReadOnly {
+			b.WriteString(" [readonly]")
+		}
+		if field.Tag.Opts.WriteOnly {
+			b.WriteString(" [writeonly]")
+		}
+		b.WriteString("\n")
+
+		if child, ok := reprs.locals[field.ChildRef]; ok {
+			explainFields(b, child, reprs, depth+1)
+		}
+	}
+}
+
+// joinDescription describes the parts of a `join<>` tag option the way they are declared.
+func joinDescription(parts []JoinPart) string {
+	items := make([]string, len(parts))
+	for i, part := range parts {
+		items[i] = strconv.Quote(part.Literal)
+		if part.Path != nil {
+			items[i] = strings.Join(part.Path, ".")
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// DOT renders the representation as a Graphviz graph, so complex mappings, eg between several foreign types, can be
+// reviewed visually: each local field is linked to the foreign paths it maps, labeled with the options selecting
+// the path, and to the fields of its nested struct. Fields dismissed because no `types<>` entry matched are drawn
+// dotted. The representations of nested structs are read from the introspection cache, see Describe.
+//
+// Returns:
+//   - string: The graph in the DOT language, eg to be rendered with `dot -Tsvg`
+func (this StructRepr) DOT() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "digraph %v {\n", strconv.Quote(this.ForeignRootType))
+	b.WriteString("\trankdir=LR\n")
+	b.WriteString("\tnode [shape=box]\n")
+	foreigns := map[string]bool{}
+	dotFields(b, this, "", this.references(), foreigns)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotFields writes the nodes and edges of the fields of a representation, recursing into the representations of
+// nested structs. Local nodes are named after the path of the field from the root local struct, and foreign
+// nodes after their path, each foreign node being declared once.
+func dotFields(b *strings.Builder, repr StructRepr, parent string, reprs representations, foreigns map[string]bool) {
+	for _, field := range repr.Unmatched {
+		local := strconv.Quote("local:" + parent + field.Name)
+		fmt.Fprintf(b, "\t%v [label=%v, style=dotted]\n", local, strconv.Quote(field.Name))
+	}
+	for _, field := range repr.Fields {
+		local := strconv.Quote("local:" + parent + field.Name)
+		fmt.Fprintf(b, "\t%v [label=%v]\n", local, strconv.Quote(field.Name))
+
+		refs := append([]string{field.TargetRef}, field.JoinRefs...)
+		for _, ref := range refs {
+			target, ok := reprs.foreigns[ref]
+			if !ok {
+				continue
+			}
+			path := strings.Join(target.Path, ".")
+			foreign := strconv.Quote("foreign:" + path)
+			if !foreigns[path] {
+				fmt.Fprintf(b, "\t%v [label=%v, shape=ellipse]\n", foreign, strconv.Quote(path))
+				foreigns[path] = true
+			}
+			fmt.Fprintf(b, "\t%v -> %v", local, foreign)
+			if label := dotEdgeLabel(field); label != "" {
+				fmt.Fprintf(b, " [label=%v]", strconv.Quote(label))
+			}
+			b.WriteString("\n")
+		}
+
+		child, ok := reprs.locals[field.ChildRef]
+		if !ok {
+			continue
+		}
+		prefix := parent + field.Name + "."
+		for _, nested := range child.Fields {
+			fmt.Fprintf(b, "\t%v -> %v [style=dashed]\n", local, strconv.Quote("local:"+prefix+nested.Name))
+		}
+		dotFields(b, child, prefix, reprs, foreigns)
+	}
+}
+
+// dotEdgeLabel describes the options selecting the foreign path a field is mapped to, if any.
+func dotEdgeLabel(field SourceField) string {
+	labels := []string{}
+	if match := field.Tag.Match; match.Precedence != NoTypeMatch {
+		labels = append(labels, fmt.Sprintf("types<%v>", match.Name))
+	}
+	if when := field.Tag.Opts.When; when != nil {
+		labels = append(labels, fmt.Sprintf("when<%v=%v>", strings.Join(when.Path, "."), when.Value))
+	}
+	if field.Tag.Fallback {
+		labels = append(labels, "fallback")
+	}
+	if field.Tag.Opts.ReadOnly {
+		labels = append(labels, "readonly")
+	}
+	if field.Tag.Opts.WriteOnly {
+		labels = append(labels, "writeonly")
+	}
+	return strings.Join(labels, ", ")
+}
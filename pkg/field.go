@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"strings"
+	"time"
 )
 
 // StructRepr represents a structure representation that stores information about
@@ -12,7 +14,10 @@ import (
 // mapping between different struct types in the application.
 type StructRepr struct {
 	Fields          []SourceField
+	Unmatched       []SourceField // fields dismissed because of the type matching option, or their optional path
 	ForeignRootType string
+	foreignType     reflect.Type
+	reprs           representations // referenced representations carried by IntrospectRepr, the cached ones otherwise
 }
 
 // SourceField represents a field in the source structure that needs to be mapped to
@@ -23,21 +28,53 @@ type StructRepr struct {
 // should be mapped to fields in another structure, tracking properties like whether
 // the field is a pointer, array, map, or has nested structures.
 type SourceField struct {
-	Id        int
-	Name      string
-	Kind      reflect.Kind
-	Type      reflect.Type
-	IsPointer bool
-	IsArray   bool
-	IsMap     bool
-	ChildRef  string
-	TargetRef string
-	Tag       FieldTag
+	Id           int
+	Name         string
+	Kind         reflect.Kind
+	Type         reflect.Type
+	IsPointer    bool
+	IsArray      bool
+	IsMap        bool
+	ChildRef     string
+	TargetRef    string
+	ConditionRef string   // ref key of the foreign field compared by the `when<>` tag option, if any
+	JoinRefs     []string // ref key of the foreign field of each part of the `join<>` tag option, empty for literals
+	Converted    bool     // the value is transformed by a registered converter instead of copied, see converter
+	Tag          FieldTag
+	plan         *fieldPlan // references resolved once the representation is cached, see link
+}
+
+// conditionHolds evaluates the `when<>` option of a field, if any, against the live foreign value, reporting
+// whether the field has to be mapped. A condition whose foreign field can't be reached doesn't hold.
+//
+// Parameters:
+//   - field: The field setting the option
+//   - foreign: The foreign value holding the field, as passed to the walkers
+//   - offset: How many elements of the foreign index paths were already walked to reach foreign
+func (this representations) conditionHolds(field SourceField, foreign reflect.Value, offset int) bool {
+	if field.ConditionRef == "" {
+		return true
+	}
+	condition := this.condition(field)
+	value, reason := resolveForeignValue(condition.IndexPath[offset:], condition.Elements[offset:], foreign)
+	if reason != "" {
+		return false
+	}
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return false
+		}
+		value = value.Elem()
+	}
+	return fmt.Sprint(value.Interface()) == field.Tag.Opts.When.Value
 }
 
 // TargetField represents a field in the target structure that will receive mapped data.
 // It contains information necessary to locate and manipulate the target field,
 // such as its ID, kind (type), path in the struct hierarchy, and index path.
+// Elements holds, for every field of the index path, which element is traversed on each level of collections
+// found in the middle of the path: the first one (also when no level is given), or the last one when set to
+// lastElement.
 //
 // TargetField is used to store information about how to access specific fields
 // in the target structure during the mapping process, tracking both the string path
@@ -48,6 +85,39 @@ type TargetField struct {
 	Path      []string
 	IndexPath []int
 	TypeName  string
+	Type      reflect.Type // type of the foreign field, before unwrapping pointers and collections
+	IsArray   bool         // the field is a slice or array addressed as a whole, without an element index
+	IsMap     bool         // the field is a map, its entries are copied one by one
+	ArrayLen  int          // number of elements when the field is a fixed-size array addressed as a whole
+	Wildcard  int          // number of IndexPath elements leading to the slice addressed with `[*]`, 0 when there is none
+	Elements  [][]int
+}
+
+// lastElement is the value of TargetField.Elements for collections traversed through their last element.
+const lastElement = -1
+
+// splitElements returns how many elements of the index path lead to the foreign slice whose elements are mapped
+// one by one to the elements of a local slice, and whether there is such a slice beyond the given offset.
+// A `[*]` segment splits the path at the wildcard slice, while a path ending in a slice without index is
+// split at its end.
+func (this TargetField) splitElements(offset int) (int, bool) {
+	if this.Wildcard > offset {
+		return this.Wildcard, true
+	}
+	if this.IsArray {
+		return len(this.IndexPath), true
+	}
+	return 0, false
+}
+
+// fieldByName looks up a mapped field of the representation by its local name.
+func (this StructRepr) fieldByName(name string) (SourceField, bool) {
+	for _, field := range this.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return SourceField{}, false
 }
 
 // describe creates a new StructRepr instance by analyzing the provided local and foreign types.
@@ -58,42 +128,62 @@ type TargetField struct {
 //   - local: The reflect.Type of the source structure to be analyzed.
 //   - foreign: The reflect.Type of the target structure that fields will be mapped to.
 //   - name: A name identifier for the representation, typically the field name in a parent struct.
+//   - depth: How many structs the local type is nested in, see WithMaxDepth.
+//   - opts: The options of the Codec requesting the representation.
 //   - parentPath: Optional path elements that indicate the hierarchical location in nested structures.
 //
 // Returns:
 //   - error: An error if the mapping generation fails, nil on success.
 //
-// The function uses caching to avoid redundant analysis of previously processed type combinations.
+// The function uses caching to avoid redundant analysis of previously processed type combinations, it must run
+// holding the lock of the cache, see typeCache.update.
 // It handles pointer types, resolves nested structures, and validates type compatibility between
 // mapped fields.
 func (this *StructRepr) describe(
 	local, foreign reflect.Type,
 	name string,
+	depth int,
+	opts *options,
 	parentPath ...string,
 ) error {
+	if opts.exceedsDepth(depth) {
+		return fmt.Errorf("%w %v nested %v levels deep", ErrMaxDepth, local, depth)
+	}
 	if foreign.Kind() == reflect.Pointer {
 		foreign = foreign.Elem()
 	}
 	this.ForeignRootType = foreign.Name()
+	this.foreignType = foreign
 
 	if local.Kind() == reflect.Pointer {
 		local = local.Elem()
 	}
 
-	key := getNativeRepresentationKey(local, foreign, name)
-	cached, ok := localRepresentations[key]
+	key := opts.cache.getNativeRepresentationKey(local, foreign, name) + opts.representationVariant()
+	cached, ok := opts.cache.locals[key]
+	if !ok && opts.cache.restore(key, local, foreign) {
+		cached, ok = opts.cache.locals[key], true
+		opts.cache.held().link(cached)
+	}
 	if ok {
+		opts.cache.hits.Add(1)
 		*this = cached
 		return nil
 	}
+	opts.cache.misses.Add(1)
+	if opts.logger != nil {
+		opts.logger.Debug("introspection cache miss", "local", local.String(), "foreign", foreign.String())
+	}
 
-	fields, err := parseStructFields(local, foreign, this.ForeignRootType, parentPath...)
+	fields, unmatched, err := parseStructFields(local, foreign, this.ForeignRootType, depth, opts, parentPath...)
 	if err != nil {
 		return err
 	}
 
 	this.Fields = fields
-	localRepresentations[key] = *this
+	this.Unmatched = unmatched
+	opts.cache.locals[key] = *this
+	opts.cache.held().link(*this)
 
 	return nil
 }
@@ -104,16 +194,24 @@ func (this *StructRepr) describe(
 // Parameters:
 //   - local: The source object whose structure will be analyzed for mapping.
 //   - foreign: The target object whose structure will receive mapped data.
+//   - opts: The options of the Codec requesting the introspection.
 //
 // Returns:
 //   - error: An error if the introspection process fails, nil on success.
 //
-// The function validates that both local and foreign are struct types, initializes the caching
-// system, and then describes the relationship between the structures. It also ensures that
-// at least one valid mapping field exists between the structures.
-func (this *StructRepr) introspect(local, foreign interface{}) error {
+// The function validates that both local and foreign are struct types, and then describes the
+// relationship between the structures, recording the time spent when they weren't cached. Cached representations
+// are looked up without blocking, only types missing the cache are described holding its lock. It also
+// ensures that at least one valid mapping field exists between the structures.
+func (this *StructRepr) introspect(local, foreign interface{}, opts *options) error {
 	l := reflect.TypeOf(local)
 	f := reflect.TypeOf(foreign)
+	if l == nil {
+		return ErrLocalTypeNotStruct
+	}
+	if f == nil {
+		return ErrForeignTypeNotStruct
+	}
 
 	if l.Kind() == reflect.Pointer {
 		l = l.Elem()
@@ -123,29 +221,43 @@ func (this *StructRepr) introspect(local, foreign interface{}) error {
 		f = f.Elem()
 	}
 
-	cacheInit()
-
 	if err := this.validateInput(l, f); err != nil {
 		return err
 	}
 
-	if err := this.describe(l, f, ""); err != nil {
+	misses := opts.cache.misses.Load()
+	start := time.Now()
+	opts.cache.refresh()
+	key := opts.cache.getNativeRepresentationKey(l, f, "") + opts.representationVariant()
+	if cached, ok := opts.cache.lookup(key); ok {
+		opts.cache.hits.Add(1)
+		*this = cached
+	} else if err := opts.cache.update(func() error {
+		return this.describe(l, f, "", 0, opts, opts.root...)
+	}); err != nil {
 		return err
 	}
+	if opts.cache.misses.Load() != misses {
+		elapsed := time.Since(start)
+		opts.cache.elapsed.Add(int64(elapsed))
+		if opts.observer != nil {
+			opts.observer(l, f, elapsed)
+		}
+	}
 
 	if len(this.Fields) == 0 {
-		return errors.New(ErrLocalTypeMissingValidTag)
+		return ErrLocalTypeMissingValidTag
 	}
 
-	return nil
+	return opts.validateSelection(l, *this)
 }
 
 func (this StructRepr) validateInput(local, foreign reflect.Type) error {
 	if local.Kind() != reflect.Struct {
-		return errors.New(ErrLocalTypeNotStruct)
+		return ErrLocalTypeNotStruct
 	}
 	if foreign.Kind() != reflect.Struct {
-		return errors.New(ErrForeignTypeNotStruct)
+		return ErrForeignTypeNotStruct
 	}
 	return nil
 }
@@ -157,6 +269,8 @@ func (this StructRepr) validateInput(local, foreign reflect.Type) error {
 //   - field: The SourceField to analyze for nested structures.
 //   - stfield: The reflect.StructField from the original structure type definition.
 //   - foreign: The target foreign type that fields will be mapped to.
+//   - depth: How many structs the struct holding the field is nested in.
+//   - opts: The options of the Codec requesting the representation.
 //   - parentPath: The path elements indicating the hierarchical location of this field.
 //
 // Returns:
@@ -170,6 +284,8 @@ func findFieldChilds(
 	field SourceField,
 	stfield reflect.StructField,
 	foreign reflect.Type,
+	depth int,
+	opts *options,
 	parentPath []string,
 ) (string, error) {
 	var pregnant bool // identify if the field is a struct to create its representation
@@ -188,20 +304,54 @@ func findFieldChilds(
 		}
 	}
 
+	if pregnant && isLeafType(childRef) {
+		pregnant = false // leaf structs are copied as a whole
+	}
+	if pregnant && assignableAsWhole(stfield, opts.cache.foreigns[field.TargetRef], foreign, opts.tagKey) {
+		pregnant = false // nested structs of the same type on both sides are assigned as a whole
+	}
+
 	if pregnant {
-		key = getNativeRepresentationKey(childRef, foreign, field.Name)
-		_, ok := localRepresentations[key]
+		// the representation depends on the path the nested struct is mapped under, eg when mapped for each direction
+		name := field.Name + "@" + strings.Join(parentPath, ".")
+		key = opts.cache.getNativeRepresentationKey(childRef, foreign, name) + opts.representationVariant()
+		_, ok := opts.cache.locals[key]
 		if ok {
+			opts.cache.hits.Add(1)
 			return key, nil
 		}
 		repr := &StructRepr{}
-		err = repr.describe(childRef, foreign, field.Name, parentPath...)
-		localRepresentations[key] = *repr
+		if err = repr.describe(childRef, foreign, name, depth+1, opts, parentPath...); err != nil {
+			return "", err // representations failing to be introspected aren't cached
+		}
+		opts.cache.locals[key] = *repr
 	}
 
 	return key, err
 }
 
+// assignableAsWhole reports whether a field holding structs can be assigned as a whole to the foreign field it
+// maps, instead of field by field: the foreign path leads to values of the very same type, and the struct type
+// declares no mappings of its own, with tags or registered ones.
+func assignableAsWhole(stfield reflect.StructField, target TargetField, foreign reflect.Type, tagKey string) bool {
+	if target.Type != stfield.Type {
+		return false
+	}
+	nested := unwrapCollections(stfield.Type)
+	if nested.Kind() == reflect.Map {
+		nested = unwrapCollections(nested.Elem())
+	}
+	if nested.Kind() != reflect.Struct {
+		return false
+	}
+	for i := range nested.NumField() {
+		if _, tagged := registeredTag(nested, foreign, nested.Field(i), tagKey).Tag.Lookup(tagKey); tagged {
+			return false
+		}
+	}
+	return true
+}
+
 func newField(id int, stfield reflect.StructField, tag FieldTag, target string) SourceField {
 	kind := stfield.Type.Kind()
 	field := SourceField{
@@ -231,10 +381,13 @@ func newField(id int, stfield reflect.StructField, tag FieldTag, target string)
 //   - local: The reflect.Type of the source structure to be analyzed.
 //   - foreign: The reflect.Type of the target structure that fields will be mapped to.
 //   - foreignRootType: The name of the root type of the foreign structure.
+//   - depth: How many structs the local structure is nested in.
+//   - opts: The options of the Codec requesting the representation.
 //   - parentPath: Optional path elements that indicate the hierarchical location in nested structures.
 //
 // Returns:
 //   - []SourceField: A slice of SourceField structs representing the mappable fields from the local struct.
+//   - []SourceField: A slice of SourceField structs dismissed because their type matching option didn't match.
 //   - error: An error if the mapping generation fails, nil on success.
 //
 // The function processes each field to determine its mapping characteristics, skips fields marked
@@ -243,39 +396,164 @@ func newField(id int, stfield reflect.StructField, tag FieldTag, target string)
 func parseStructFields(
 	local, foreign reflect.Type,
 	foreignRootType string,
+	depth int,
+	opts *options,
 	parentPath ...string,
-) ([]SourceField, error) {
+) ([]SourceField, []SourceField, error) {
 	fields := make([]SourceField, 0)
+	unmatched := make([]SourceField, 0)
+	var errs []error // every invalid field, when collected with WithAllErrors
 	for id := range local.NumField() {
-		stfield := local.Field(id)
-		tag, target, err := getTagAndTarget(foreignRootType, stfield, foreign, parentPath)
+		parsed, dismissed, err := parseStructField(local, foreign, id, foreignRootType, depth, opts, parentPath)
+		if err != nil {
+			if !opts.allErrors {
+				return nil, nil, err
+			}
+			errs = append(errs, flattenErrors(err)...)
+			continue
+		}
+		fields = append(fields, parsed...)
+		unmatched = append(unmatched, dismissed...)
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return fields, unmatched, nil
+}
+
+// parseStructField analyzes a field of a local struct type, returning the fields it's mapped with: one for each
+// tag it expands into, or none when skipped. See parseStructFields.
+//
+// Parameters:
+//   - local: The reflect.Type of the source structure holding the field.
+//   - foreign: The reflect.Type of the target structure that fields will be mapped to.
+//   - id: The index of the field in the local structure.
+//   - foreignRootType: The name of the root type of the foreign structure.
+//   - depth: How many structs the local structure is nested in.
+//   - opts: The options of the Codec requesting the representation.
+//   - parentPath: The path elements indicating the hierarchical location of the local structure.
+//
+// Returns:
+//   - []SourceField: The mappable fields the field is mapped with.
+//   - []SourceField: The field when dismissed because its type matching option didn't match.
+//   - error: A *FieldError identifying the field if its tag is invalid, nil on success.
+func parseStructField(
+	local, foreign reflect.Type,
+	id int,
+	foreignRootType string,
+	depth int,
+	opts *options,
+	parentPath []string,
+) ([]SourceField, []SourceField, error) {
+	fields := make([]SourceField, 0)
+	unmatched := make([]SourceField, 0)
+	stfield := registeredTag(local, foreign, local.Field(id), opts.tagKey)
+	parsed := parseTag(stfield, opts.tagKey)
+	tags, err := expandTag(parsed)
+	if err != nil {
+		return nil, nil, newFieldError(local, stfield.Name, parsed, err)
+	}
+	accepted := false // whether a tag of the field was accepted, the following alternative paths are fallbacks
+	for i, tag := range tags {
+		tag, target, err := getTagAndTarget(foreignRootType, stfield, tag, foreign, parentPath, opts)
+		if opts.autoMap && isAutoMappable(stfield, opts.tagKey) {
+			tag, target = getAutoTagAndTarget(stfield, foreign, parentPath, opts)
+		}
+		if tag.Unmatched && !slices.ContainsFunc(unmatched, func(f SourceField) bool { return f.Id == id }) {
+			unmatched = append(unmatched, newField(id, stfield, tag, ""))
+		}
 		if tag.Skip {
 			continue
 		}
+		if !stfield.IsExported() && !stfield.Anonymous {
+			return nil, nil, newFieldError(local, stfield.Name, tag, fmt.Errorf(
+				"%w %v.%v", ErrUnexportedField, local, stfield.Name,
+			))
+		}
 		if err != nil {
-			return nil, err
+			var notFound *FieldNotFoundError
+			if len(tag.Alternatives) > 0 && errors.As(err, &notFound) && (accepted || i < len(tags)-1) {
+				continue // alternative paths missing from the foreign type are dismissed
+			}
+			if (tag.Opts.Optional || opts.ignoreMissing) && errors.As(err, &notFound) {
+				tag.Missing = true
+				if !slices.ContainsFunc(unmatched, func(f SourceField) bool { return f.Id == id }) {
+					unmatched = append(unmatched, newField(id, stfield, tag, ""))
+				}
+				continue // optional paths missing from the foreign type are dismissed
+			}
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
+		if opts.exceedsDepth(len(tag.Path)) {
+			return nil, nil, newFieldError(local, stfield.Name, tag, fmt.Errorf(
+				"%w %v maps %v path segments", ErrMaxDepth, stfield.Name, len(tag.Path),
+			))
+		}
+		if len(tag.Alternatives) > 0 {
+			tag.Fallback = accepted
 		}
 
 		field := newField(id, stfield, tag, target)
-		childRef, err := findFieldChilds(field, stfield, foreign, tag.Path)
-		if err != nil {
-			return nil, err
+		if tag.Auto && !autoMapCompatible(field, stfield, opts.cache.foreigns[target]) {
+			continue
 		}
-
-		field.ChildRef = childRef
-		if field.ChildRef == "" {
+		if field.ConditionRef, err = resolveCondition(tag, foreign, parentPath, opts); err != nil {
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
+		if field.JoinRefs, err = resolveJoin(tag, stfield, foreign, parentPath, opts); err != nil {
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
+		if opts.cache.foreigns[target].Wildcard > len(parentPath) && !field.IsArray {
+			return nil, nil, newFieldError(local, stfield.Name, tag, fmt.Errorf(
+				"%w %v is %v", ErrWildcardLocalType, field.Name, stfield.Type,
+			))
+		}
+		field.Converted = tag.Opts.Convert != "" || hasConverter(field, opts.cache.foreigns[target])
+		if tag.Opts.Compute == "" && !field.Converted {
+			// computed and converted fields are set as a whole, nested structs aren't mapped field by field
+			if field.ChildRef, err = findFieldChilds(field, stfield, foreign, depth, opts, tag.Path); err != nil {
+				return nil, nil, nestFieldError(local, stfield.Name, tag, err)
+			}
+		}
+		if err := validateMergeKey(field, opts.cache.locals); err != nil {
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
+		if err := validateConverter(field, opts.cache.foreigns[target]); err != nil {
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
+		if err := validateBase64(field, opts.cache.foreigns[target]); err != nil {
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
+		if err := validateStringify(field, opts.cache.foreigns[target]); err != nil {
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
+		if err := validateTimeLayout(field, opts.cache.foreigns[target]); err != nil {
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
+		if field.ChildRef == "" && field.JoinRefs == nil && tag.Opts.Compute == "" && !field.Converted {
 			// having no children means we will write over this field
 			// make sure Local and Foreign fields types matches
 			err := validateFieldsTypeMatch(field, stfield, tag.TargetType)
-			if err != nil {
-				return nil, err
+			if err != nil && !(opts.numeric && numericCompatible(field, opts.cache.foreigns[target])) {
+				return nil, nil, newFieldError(local, stfield.Name, tag, err)
 			}
 		}
+		if err := validateMapKey(stfield, opts.cache.foreigns[target]); err != nil {
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
+		if err := validateArrayLength(stfield, opts.cache.foreigns[target]); err != nil {
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
+		if err := validateConstValue(field); err != nil {
+			return nil, nil, newFieldError(local, stfield.Name, tag, err)
+		}
 
 		fields = append(fields, field)
+		accepted = true
 	}
 
-	return fields, nil
+	return fields, unmatched, nil
 }
 
 // validateFieldsTypeMatch checks if the type of a source field matches the expected target type.
@@ -299,16 +577,178 @@ func validateFieldsTypeMatch(
 ) error {
 	typeName := stfield.Type.Name()
 	if field.IsArray || field.IsMap || field.IsPointer {
-		typeName = stfield.Type.Elem().Name()
+		typeName = typeDescription(stfield.Type.Elem())
 	}
 	if !pointsOrIsStruct(stfield.Type) && targetType != typeName {
-		return fmt.Errorf(ErrForeignTypeMismatch+" %v is not %v", targetType, typeName)
+		return fmt.Errorf("%w %v is not %v", ErrForeignTypeMismatch, targetType, typeName)
+	}
+	return nil
+}
+
+// numericCompatible checks whether a field and the foreign field it maps hold a single number each, so their
+// values can be converted into each other when the codec was created with WithNumericConversion.
+func numericCompatible(field SourceField, target TargetField) bool {
+	if field.IsArray || field.IsMap || field.IsPointer || target.IsArray || target.IsMap {
+		return false
+	}
+	return target.Type != nil && isNumeric(field.Type) && isNumeric(target.Type)
+}
+
+// autoMapCompatible checks whether an auto-mapped field can be transferred to the foreign field of the same name:
+// both must hold either single values, slices or maps, of structs or of the same type.
+func autoMapCompatible(field SourceField, stfield reflect.StructField, target TargetField) bool {
+	if field.IsArray != target.IsArray || field.IsMap != target.IsMap {
+		return false
+	}
+	if field.Kind == reflect.Struct {
+		return target.Kind == reflect.Struct
+	}
+	return validateFieldsTypeMatch(field, stfield, target.TypeName) == nil
+}
+
+// validateMergeKey checks that the `mergekey<>` option of a field, if any, is set on a slice of structs and names
+// a mapped field of the slice elements that can be compared, that is, one not holding a nested struct.
+func validateMergeKey(field SourceField, locals map[string]StructRepr) error {
+	key := field.Tag.Opts.MergeKey
+	if key == "" {
+		return nil
+	}
+	child, hasChild := locals[field.ChildRef]
+	if !field.IsArray || !hasChild {
+		return fmt.Errorf("%w %v is not a slice of structs", ErrInvalidMergeKey, field.Name)
+	}
+	keyField, found := child.fieldByName(key)
+	if !found || keyField.ChildRef != "" {
+		return fmt.Errorf("%w %v in %v", ErrInvalidMergeKey, key, field.Name)
+	}
+	return nil
+}
+
+// validateConstValue checks that the `const<>` option of a field, if any, is set on a field holding a string,
+// boolean or number, and that its value can be converted into the field type.
+func validateConstValue(field SourceField) error {
+	if field.Tag.Opts.Const == "" {
+		return nil
+	}
+	if field.ChildRef != "" || field.IsArray || field.IsMap {
+		return fmt.Errorf("%w %v is not a string, boolean or number", ErrInvalidConstValue, field.Name)
+	}
+	_, err := parseConstValue(field.Tag.Opts.Const, field.Type)
+	return err
+}
+
+// validateConverter checks that the converter named by the `convert<name>` option of a field, if any, is registered
+// and converts between the type of the field and the type of the foreign field it maps.
+func validateConverter(field SourceField, target TargetField) error {
+	name := field.Tag.Opts.Convert
+	if name == "" {
+		return nil
+	}
+	conv, ok := lookupConverter(name)
+	if !ok {
+		return fmt.Errorf("%w %v", ErrUnknownConverter, name)
+	}
+	if conv.local != field.Type || conv.foreign != target.Type {
+		return fmt.Errorf("%w %v converts %v to %v, %v maps %v to %v", ErrConverterTypeMismatch,
+			name, conv.local, conv.foreign, field.Name, field.Type, target.Type)
+	}
+	return nil
+}
+
+// hasConverter reports whether a converter was registered with RegisterConverter for the type of a field and the
+// type of the foreign field it maps, or they are handled by a built-in converter: a string and a type implementing
+// the encoding text interfaces, a scalar and a type implementing the database/sql interfaces, or a string and a
+// byte slice.
+// Fields transformed by other tag options aren't converted.
+func hasConverter(field SourceField, target TargetField) bool {
+	if field.TargetRef == "" || field.Tag.Opts.Const != "" || field.Tag.Opts.Compute != "" {
+		return false
+	}
+	if field.Tag.Opts.Stringify || field.Tag.Opts.TimeLayout != "" {
+		return true
+	}
+	_, ok := lookupPairConverter(field.Type, target.Type)
+	return ok || textConvertible(field.Type, target.Type) || valuerConvertible(field.Type, target.Type) ||
+		bytesConvertible(field.Type, target.Type)
+}
+
+// validateBase64 checks that a field setting the `base64` option maps a string to a byte slice, or the other way
+// around, as the option sets how the bytes are represented in the string.
+func validateBase64(field SourceField, target TargetField) error {
+	if field.Tag.Opts.Base64 && !bytesConvertible(field.Type, target.Type) {
+		return fmt.Errorf("%w %v", ErrInvalidBase64, field.Name)
+	}
+	return nil
+}
+
+// validateTimeLayout checks that a field setting the `time<layout>` option maps a time.Time to a string, or the
+// other way around.
+func validateTimeLayout(field SourceField, target TargetField) error {
+	if field.Tag.Opts.TimeLayout != "" && !timeConvertible(field.Type, target.Type) {
+		return fmt.Errorf("%w %v", ErrInvalidTimeLayout, field.Name)
+	}
+	return nil
+}
+
+// validateStringify checks that a field setting the `stringify` option maps a string to a boolean or a number, or
+// the other way around.
+func validateStringify(field SourceField, target TargetField) error {
+	if field.Tag.Opts.Stringify && !stringifiable(field.Type, target.Type) {
+		return fmt.Errorf("%w %v", ErrInvalidStringify, field.Name)
+	}
+	return nil
+}
+
+// validateMapKey checks that the keys of a local map can be converted into the keys of the foreign map it maps,
+// and back, as otherwise the entries could not be copied in either direction.
+//
+// Parameters:
+//   - stfield: The reflect.StructField from the original structure type definition.
+//   - target: The foreign field the local field maps to.
+//
+// Returns:
+//   - error: An error if both fields are maps with keys of different classes, nil otherwise.
+func validateMapKey(stfield reflect.StructField, target TargetField) error {
+	if stfield.Type.Kind() != reflect.Map || !target.IsMap || target.Type == nil {
+		return nil
+	}
+	foreign := derefType(target.Type)
+	if foreign.Kind() != reflect.Map {
+		return nil
+	}
+	local := stfield.Type.Key()
+	if !mapKeyConvertible(foreign.Key(), local) || !mapKeyConvertible(local, foreign.Key()) {
+		return fmt.Errorf("%w map key %v is not %v", ErrForeignTypeMismatch, foreign.Key(), local)
+	}
+	return nil
+}
+
+// validateArrayLength checks that a local fixed-size array mapped as a whole to a foreign fixed-size array holds
+// the same number of elements, as otherwise either marshaling or unmarshaling could not fit every element
+// into the destination array.
+//
+// Parameters:
+//   - stfield: The reflect.StructField from the original structure type definition.
+//   - target: The foreign field the local field maps to.
+//
+// Returns:
+//   - error: An error if both fields are arrays of different lengths, nil otherwise.
+func validateArrayLength(stfield reflect.StructField, target TargetField) error {
+	if stfield.Type.Kind() != reflect.Array || target.ArrayLen == 0 {
+		return nil
+	}
+	if stfield.Type.Len() != target.ArrayLen {
+		return fmt.Errorf(
+			"%w %v holds %v elements but foreign %v holds %v", ErrArrayLengthMismatch,
+			stfield.Name, stfield.Type.Len(), strings.Join(target.Path, "."), target.ArrayLen,
+		)
 	}
 	return nil
 }
 
 // pointsOrIsStruct determines whether a given reflect.Type is a struct type or points to a struct type.
-// This function is used to identify fields that contain or reference structured data.
+// This function is used to identify fields that contain or reference structured data, leaving out the leaf types
+// registered with RegisterLeafType.
 //
 // Parameters:
 //   - field: The reflect.Type to check.
@@ -323,10 +763,10 @@ func pointsOrIsStruct(field reflect.Type) bool {
 	descendableKinds := []reflect.Kind{reflect.Pointer, reflect.Array, reflect.Slice}
 	kind := field.Kind()
 	if kind == reflect.Struct {
-		return true
+		return !isLeafType(field)
 	}
 	if slices.Contains(descendableKinds, kind) && field.Elem().Kind() == reflect.Struct {
-		return true
+		return !isLeafType(field.Elem())
 	}
 	return false
 }
@@ -347,5 +787,5 @@ func pointsOrIsStruct(field reflect.Type) bool {
 // creating a cached representation to optimize repeated mappings.
 func Introspect(local, foreign interface{}) error {
 	repr := &StructRepr{}
-	return repr.introspect(local, foreign)
+	return repr.introspect(local, foreign, defaultCodec.opts)
 }
@@ -0,0 +1,167 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// BeforeMarshaler is implemented by local structs that need to prepare themselves before being encoded, eg to
+// compute derived fields or normalize their data. BeforeMarshal receives the foreign object being encoded into,
+// as provided to Marshal, before any field is transferred.
+type BeforeMarshaler interface {
+	BeforeMarshal(foreign any) error
+}
+
+// AfterUnmarshaler is implemented by local structs that need to complete themselves after being decoded, eg to
+// compute derived fields or normalize their data. AfterUnmarshal receives the foreign object decoded from, as
+// provided to Unmarshal, once every field was transferred.
+type AfterUnmarshaler interface {
+	AfterUnmarshal(foreign any) error
+}
+
+var beforeMarshalerType = reflect.TypeFor[BeforeMarshaler]()
+
+// Hooks holds callbacks run around the translation between a local type L and a foreign type F, see RegisterHooks.
+// Every callback is optional, and an error returned by any of them aborts the translation.
+type Hooks[L, F any] struct {
+	BeforeEncode func(local *L, foreign *F) error // called by Marshal before any field is transferred
+	AfterEncode  func(local *L, foreign *F) error // called by Marshal once every field was transferred
+	BeforeDecode func(local *L, foreign *F) error // called by Unmarshal before any field is transferred
+	AfterDecode  func(local *L, foreign *F) error // called by Unmarshal once every field was transferred
+}
+
+// typeHooks holds the callbacks registered for a pair of types, receiving pointers to values of those types.
+type typeHooks struct {
+	beforeEncode func(local, foreign reflect.Value) error
+	afterEncode  func(local, foreign reflect.Value) error
+	beforeDecode func(local, foreign reflect.Value) error
+	afterDecode  func(local, foreign reflect.Value) error
+}
+
+// RegisterHooks registers callbacks run every time a local struct of type L is translated to or from a foreign
+// struct of type F, so cross-cutting behavior like auditing or defaulting can be attached to specific conversions
+// without modifying the structs. Registering a pair of types again replaces the previous hooks.
+//
+// Hooks run for the objects given to Marshal and Unmarshal, not for the structs nested in them. Registered
+// callbacks run before the BeforeMarshal method of the local struct and after its AfterUnmarshal method. When the
+// source of the translation is given by value, the callbacks receive a copy of it.
+//
+// Parameters:
+//   - hooks: The callbacks to run, nil ones are ignored
+func RegisterHooks[L, F any](hooks Hooks[L, F]) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.hooks[[2]reflect.Type{reflect.TypeFor[L](), reflect.TypeFor[F]()}] = typeHooks{
+		beforeEncode: wrapHook(hooks.BeforeEncode),
+		afterEncode:  wrapHook(hooks.AfterEncode),
+		beforeDecode: wrapHook(hooks.BeforeDecode),
+		afterDecode:  wrapHook(hooks.AfterDecode),
+	}
+}
+
+// wrapHook wraps a typed callback into one receiving reflected pointers, nil callbacks are kept nil.
+func wrapHook[L, F any](hook func(local *L, foreign *F) error) func(local, foreign reflect.Value) error {
+	if hook == nil {
+		return nil
+	}
+	return func(local, foreign reflect.Value) error {
+		return hook(local.Interface().(*L), foreign.Interface().(*F))
+	}
+}
+
+// lookupHooks returns the hooks registered for a pair of local and foreign struct types, if any.
+func lookupHooks(local, foreign reflect.Type) (typeHooks, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	hooks, ok := registry.hooks[[2]reflect.Type{local, foreign}]
+	return hooks, ok
+}
+
+// addressable returns a pointer to the struct held by a value, which is copied when not given by pointer.
+func addressable(value reflect.Value) reflect.Value {
+	if value.Kind() == reflect.Pointer {
+		return value
+	}
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	return ptr
+}
+
+// runHook calls one of the registered hooks, if set, describing the error it returns.
+func runHook(hook func(local, foreign reflect.Value) error, name string, local, foreign reflect.Value) error {
+	if hook == nil {
+		return nil
+	}
+	if err := hook(local, foreign); err != nil {
+		return fmt.Errorf("%w %v of %v and %v: %w", ErrHookFunc, name, local.Type().Elem(), foreign.Type().Elem(), err)
+	}
+	return nil
+}
+
+// beforeMarshal runs the BeforeEncode hook registered for the types being encoded and the BeforeMarshal method of
+// the local struct, if any. Local values that are not addressable are copied first, so hooks can update the copy
+// that is going to be encoded without modifying the caller's value.
+//
+// Returns:
+//   - error: The error returned by the hooks, if any
+func (this *StructEncoder) beforeMarshal() error {
+	localType := derefType(this.local.Type())
+	hooks, registered := lookupHooks(localType, this.foreign.Type().Elem())
+	if !registered && !reflect.PointerTo(localType).Implements(beforeMarshalerType) {
+		return nil
+	}
+	this.local = addressable(this.local)
+
+	if err := runHook(hooks.beforeEncode, "BeforeEncode", this.local, this.foreign); err != nil {
+		return err
+	}
+	if hook, ok := this.local.Interface().(BeforeMarshaler); ok {
+		if err := hook.BeforeMarshal(this.foreign.Interface()); err != nil {
+			return fmt.Errorf("%w BeforeMarshal of %v: %w", ErrHookFunc, localType, err)
+		}
+	}
+	return nil
+}
+
+// afterMarshal runs the AfterEncode hook registered for the types being encoded, if any.
+//
+// Returns:
+//   - error: The error returned by the hook, if any
+func (this *StructEncoder) afterMarshal() error {
+	hooks, registered := lookupHooks(derefType(this.local.Type()), this.foreign.Type().Elem())
+	if !registered {
+		return nil
+	}
+	return runHook(hooks.afterEncode, "AfterEncode", addressable(this.local), this.foreign)
+}
+
+// beforeUnmarshal runs the BeforeDecode hook registered for the types being decoded, if any.
+//
+// Returns:
+//   - error: The error returned by the hook, if any
+func (this *StructDecoder) beforeUnmarshal() error {
+	hooks, registered := lookupHooks(this.local.Type().Elem(), derefType(this.foreign.Type()))
+	if !registered {
+		return nil
+	}
+	return runHook(hooks.beforeDecode, "BeforeDecode", this.local, addressable(this.foreign))
+}
+
+// afterUnmarshal runs the AfterUnmarshal method of the local struct decoded into and the AfterDecode hook
+// registered for the types being decoded, if any.
+//
+// Returns:
+//   - error: The error returned by the hooks, if any
+func (this *StructDecoder) afterUnmarshal() error {
+	if hook, ok := this.local.Interface().(AfterUnmarshaler); ok {
+		if err := hook.AfterUnmarshal(this.foreign.Interface()); err != nil {
+			return fmt.Errorf("%w AfterUnmarshal of %v: %w", ErrHookFunc, this.local.Type().Elem(), err)
+		}
+	}
+
+	hooks, registered := lookupHooks(this.local.Type().Elem(), derefType(this.foreign.Type()))
+	if !registered {
+		return nil
+	}
+	return runHook(hooks.afterDecode, "AfterDecode", this.local, addressable(this.foreign))
+}
@@ -12,6 +12,13 @@
 //	    Name string `se:metadata.name`
 //	}
 //
+// Fields named just like their foreign counterpart can use `=` instead of repeating the name, nested fields
+// resolve it under the path of their parent.
+//
+//	type MyStruct struct {
+//	    Replicas int `se:"="` // same as `se:"Replicas"`
+//	}
+//
 // And now you just need to call `Marshal` or `Unmarshal` to translate between your structs.
 //
 //	// load your struct with values from an external object
@@ -26,6 +33,37 @@
 //	}
 //	se.Marshal(src, dst)
 //
+// When you don't have a destination at hand, `UnmarshalNew` allocates it for you, and so do `MapTo` and `MapInto`,
+// which take the types to map to and from as type parameters.
+//
+//	local, err := se.UnmarshalNew[MyStruct](getSomeThirdPartyData())
+//	foreign, err := se.MapTo[module.SomeStruct](local)
+//	local, err = se.MapInto[module.SomeStruct, MyStruct](foreign)
+//
+// To encode a whole slice use `MarshalSlice`, or `UnmarshalSlice` to decode it, which introspect the types only once
+// and map every element. Large batches can be split among goroutines with the `WithParallelism` option.
+//
+//	objects, err := se.MarshalSlice[MyStruct, module.SomeStruct](items)
+//	items, err = se.UnmarshalSlice[MyStruct](objects, se.WithParallelism(8))
+//
+// Slice fields holding thousands of nested structs, eg the items of a list object, can have their elements split among
+// goroutines too, with the `WithParallelSlices(workers, threshold)` option of codecs, mapping concurrently the slices
+// holding at least threshold elements.
+//
+//	codec := se.NewCodec(se.WithParallelSlices(8, 1000))
+//	err = codec.Unmarshal(podList, &pods)
+//
+// Maps of structs, like indexed caches of API objects, are mapped the same way by `MarshalMap` and `UnmarshalMap`,
+// which keep the keys of the source map.
+//
+//	cache, err := se.UnmarshalMap[MyStruct](objectsByName)
+//
+// `Pipe` maps the values received from a channel with a pool of workers and sends the results to another one, with
+// the function given to map each value setting the direction, eg `se.Marshal` or the `Decode` method of a compiled
+// mapper. Results are sent in the order they are mapped, which only matches the input order with a single worker.
+//
+//	err := se.Pipe[MyStruct, module.SomeStruct](ctx, locals, objects, se.Marshal, 8)
+//
 // # Advanced
 //
 // # Type Matching
@@ -41,6 +79,42 @@
 //	    Flag bool `se:metadata.name,types<SomeStruct>`
 //	}
 //
+// Type names can be qualified by package name (`types<api.Deployment>`) or by full import path
+// (`types<github.com/org/api.Deployment>`). When more than one entry matches the foreign type the most specific one
+// wins: import path over package name over bare name, and entries with the same specificity are resolved by
+// declaration order.
+//
+// Entries prefixed with `~` hold a regular expression matched against the type names, so a single entry can cover a
+// family of versioned types, eg `types<~^Deployment(V1)?$>`. Patterns can't hold the `|`, `:`, `,` and `>`
+// characters, and have the lowest precedence, so entries naming a type win over them.
+//
+// Use `Explain(local, foreign)` to get a description of how every field maps into a foreign type, including which
+// `types<>` entry was selected and why. `Describe(local, foreign)` returns the representation of the mapping
+// instead, which can be encoded as JSON to snapshot the mapping of critical pairs of types in golden tests, failing
+// them when a refactor silently changes how paths are resolved.
+//
+//	repr, err := se.Describe(MyStruct{}, api.Deployment{})
+//	golden, err := json.MarshalIndent(repr, "", "  ")
+//
+// Representations can also be rendered as a Graphviz graph with `DOT()`, linking each local field to the foreign
+// paths it maps and to the fields of its nested struct, to review complex multi-type mappings visually.
+//
+//	os.WriteFile("mapping.dot", []byte(repr.DOT()), 0o644) // dot -Tsvg mapping.dot > mapping.svg
+//
+// To inspect a mapping programmatically, `IntrospectRepr(local, foreign)` returns the representation along with the
+// ones it references, whose `Field(name)`, `PathFor(name)` and `ForeignType()` methods describe how each local field
+// maps, nested fields being named by their path, eg `Spec.Replicas`.
+//
+//	repr, err := se.IntrospectRepr(MyStruct{}, api.Deployment{})
+//	path, ok := repr.PathFor("Spec.Replicas") // []string{"Spec", "Replicas"}
+//
+// `MarshalAll(local, foreign1, foreign2, ...)` populates several foreign objects of different types in one call, each
+// one receiving only the fields whose `types<>` option matches its type. The other way around, `Unmarshal` assembles
+// a local struct from several foreign objects when given a `[]any` holding them, each one filling the fields whose
+// `types<>` option matches its type.
+//
+//	err := se.Unmarshal([]any{spec, status}, &local)
+//
 // # Per Type Path
 //
 // You can specify a different path for each type by appending the path to the type using `:` as separator in the
@@ -77,6 +151,711 @@
 //	    Child2 DismissParent `->`
 //	}
 //
+// The whole mapping can be mounted under a sub-path of the foreign struct with the `WithRoot` option, so the same
+// struct can be mapped into a sub-tree of a bigger foreign object without changing its tags.
+//
+//	codec := se.NewCodec(se.WithRoot("Spec.Template"))
+//
+// # Relative Paths
+//
+// Besides `->`, the paths of the fields of nested structs can start with operators giving finer control over how they
+// compose with the inherited parent path: each leading `^`, or `../`, strips a segment of the parent path, while a
+// leading `/` resolves the path from the root of the mapping, the one set with `WithRoot` if any. The fields of the
+// elements of slices and maps can't leave the element they're mapped under, such paths fail introspection with
+// `ErrInvalidRelativePath`.
+//
+//	type Scaling struct {
+//	    Replicas int    `se:"Replicas"`       // Spec.Replicas
+//	    Ready    int    `se:"^Status.Ready"`  // Status.Ready
+//	    Name     string `se:"/Metadata.Name"` // Metadata.Name
+//	}
+//
+//	type MyStruct struct {
+//	    Scaling Scaling `se:"Spec"`
+//	}
+//
+// # Embedded Structs
+//
+// Untagged embedded structs, or pointers to them, are squashed just like `encoding/json` promotes their fields: their
+// tags are resolved as if declared by the struct embedding them, as with the `->` path, so shared fields can be
+// declared once and embedded in every local struct. Tag an embedded struct to map it as a named child instead, or with
+// `-` to leave it out.
+//
+//	type Meta struct {
+//		Name   string			`se:"Metadata.Name"`
+//		Labels map[string]string `se:"Metadata.Labels"`
+//	}
+//
+//	type MyStruct struct {
+//		Meta
+//		Replicas int `se:"Spec.Replicas"`
+//	}
+//
+// # Promoted Fields
+//
+// Paths reach the fields promoted through the structs embedded in foreign types, as Kubernetes types embed `TypeMeta`
+// and `ObjectMeta`, so the name of the embedded type may be omitted from the path, following the rules of Go selectors:
+// ambiguous names aren't promoted. Embedded pointers found along the path are allocated when marshaling, just like any
+// other pointer, and the resolved path names the embedded structs, eg in reports and errors.
+//
+//	type MyStruct struct {
+//		Kind string `se:"Kind"` // same as TypeMeta.Kind
+//		Name string `se:"Name"` // same as ObjectMeta.Name
+//	}
+//
+// # Interface Fields
+//
+// Paths can't traverse foreign interface fields, eg `runtime.Object` or `any`, without knowing the type they hold:
+// register pointers to the struct types they may hold with `RegisterConcreteType` and name them with the `as<>` option,
+// one name for each interface along the path, separated by `|`. Marshal allocates the named type when the interface is
+// nil or holds another type, while Unmarshal skips the field with `SkipConcrete` unless the interface holds it. Fields
+// of nested structs inherit the assertion, which the resolved path shows after the interface field, eg
+// `Object.(Deployment).Spec.Replicas` in reports and errors.
+//
+//	func init() {
+//	    se.RegisterConcreteType[*appsv1.Deployment]()
+//	}
+//
+//	type MyStruct struct {
+//	    Replicas int32 `se:"Object.Spec.Replicas,as<Deployment>"`
+//	    Spec     Spec  `se:"Object.Spec,as<Deployment>"`
+//	}
+//
+// # Slices
+//
+// Slices found in the middle of a path are traversed through their first element, and the `[0]` suffix can be used
+// to make it explicit, eg `se:"Config.Items[0].Name"`. Use `[last]` or `[-1]` to traverse the last element instead,
+// eg `se:"Status.Conditions[last].Type"`; marshaling into an empty slice appends the element first.
+// Nested slices are traversed level by level, taking one index per level, eg `se:"Config.Matrix[0][last].Name"`;
+// levels without an index traverse their first element. Local nested slices such as `[][]string` can be mapped to
+// foreign nested slices holding the same element type.
+//
+// When a local slice of structs is mapped to a foreign slice, a path ending in the slice field without index, every
+// element is marshaled into the corresponding element of the foreign slice, growing it as needed. Unmarshaling works
+// the other way around, the local slice is replaced by one holding every element of the foreign slice in the same
+// order.
+//
+//	type Item struct {
+//	    Name string `se:"Metadata.Name"`
+//	}
+//
+//	type MyStruct struct {
+//	    Items []Item `se:"Spec.Items"`
+//	}
+//
+// The `[*]` suffix maps a local slice to a field inside every element of a foreign slice, eg a local `[]string`
+// tagged with `se:"Spec.Items[*].Metadata.Name"` holds the name of every item. Each local element is marshaled into
+// the corresponding foreign element, and unmarshaling collects the field of every foreign element in order. Wildcard
+// paths can only be used on local slice fields.
+//
+// When unmarshaling into a slice that already holds elements, the `mergekey<>` option matches the foreign and local
+// elements by a key field of the local elements and decodes each foreign element over its match, keeping the local
+// fields the mapping doesn't touch. Matched elements keep their position, new ones are appended and local elements
+// missing from the foreign slice are dropped, which makes `Unmarshal` usable in reconciliation loops.
+//
+//	type MyStruct struct {
+//	    Items []Item `se:"Spec.Items,mergekey<Name>"`
+//	}
+//
+// By default unmarshaling replaces local slices. Partial updates coming from foreign objects can keep the locally
+// held data instead with the `preserve` tag option, or for every slice with the `WithPreservedSlices()` codec option:
+// the foreign elements are decoded over the existing local elements, keeping the fields the mapping doesn't touch,
+// and local elements beyond the foreign slice length survive.
+//
+//	type MyStruct struct {
+//	    Items []Item `se:"Spec.Items,preserve"`
+//	}
+//
+// Fixed-size arrays can be used anywhere a slice can. Arrays mapped to arrays must have the same length, which is
+// checked when introspecting, while transferring a slice into an array fails when the slice holds more elements than
+// the array.
+//
+// # Maps
+//
+// A local map mapped to a foreign map copies every entry, allocating a new destination map so neither side shares
+// the map of the other one. Map values can be structs, mapped with paths relative to the foreign map value, and keys
+// are converted when the key types differ but are of the same class, eg a named string type or an integer of another
+// size. Keys of different classes, such as integers and strings, are rejected with ErrForeignTypeMismatch.
+//
+//	type Condition struct {
+//	    Status string `se:"Status"`
+//	}
+//
+//	type MyStruct struct {
+//	    Labels     map[string]string    `se:"Metadata.Labels"`
+//	    Conditions map[string]Condition `se:"Status.Conditions"`
+//	}
+//
+// When marshaling struct values, foreign fields not mapped by the local struct are kept for keys present on both
+// sides.
+//
+// By default the destination map is replaced, dropping the entries it held. Label or annotation style maps, where
+// other parties own some of the entries, can be merged instead with the `merge<>` tag option: `merge<overwrite>`
+// writes every entry over the destination map, while `merge<keep>` only adds the entries whose key isn't present yet.
+// `merge<replace>` restores the default. The mode used by map fields without the option is configured on the codec.
+//
+//	type MyStruct struct {
+//	    Labels map[string]string `se:"Metadata.Labels,merge<overwrite>"`
+//	}
+//
+//	codec := se.NewCodec(se.WithMapMerge(se.MapMergeKeep))
+//
+// # Constants
+//
+// The `const<value>` option always writes the given value into the foreign path when marshaling, regardless of the
+// local field value, while the field is ignored when unmarshaling. It suits discriminators like API versions or kinds,
+// the value is converted into the field type, which must be a string, a boolean or a number.
+//
+//	type MyStruct struct {
+//	    Kind string `se:"Kind,const<Deployment>"`
+//	}
+//
+// # Fallback Paths
+//
+// Several candidate paths can be listed separated by `||`, handy when vendors move a field between API versions but
+// keep the old location. `Unmarshal` reads the first one holding a non zero value, while `Marshal` writes to the
+// first one that exists in the foreign type. Paths missing from the foreign type are dismissed, as long as one exists.
+//
+//	type MyStruct struct {
+//	    Name string `se:"Spec.DisplayName||Spec.Name"`
+//	}
+//
+// # Optional Paths
+//
+// Paths missing from the foreign type fail introspection with `ErrForeignTypeMissingField`. Fields whose path may not
+// exist, eg when the same tags target several loosely related versions of a foreign type, set the `optional` option to
+// be dismissed instead, just like fields whose `types<>` option doesn't match. Dismissed fields are listed by mapping
+// reports and strict mode with the `SkipMissingPath` reason. Codecs created with `WithIgnoreMissingFields()` treat
+// every path as optional.
+//
+//	type MyStruct struct {
+//		Name   string `se:"Metadata.Name"`
+//		Paused bool   `se:"Spec.Paused,optional"`
+//	}
+//
+// # Joined Fields
+//
+// The `join<...>` option composes a local string out of several foreign fields and quoted literals, with `+` as the
+// main path. `Unmarshal` concatenates the values, while `Marshal` splits the local string on the literals and
+// converts each piece to its foreign type, so every pair of paths must be separated by a literal.
+//
+//	type MyStruct struct {
+//	    Ref string `se:"+,join<Metadata.Namespace,\"/\",Metadata.Name>"`
+//	}
+//
+// When every path shares the same separator, `split<>` takes the separator followed by the paths instead.
+//
+//	type MyStruct struct {
+//	    Ref string `se:"+,split<\"/\",Metadata.Namespace,Metadata.Name>"`
+//	}
+//
+// # Computed Fields
+//
+// When a tag can't describe a mapping, register a function with RegisterFunc and reference it with the
+// `compute<name>` option. `Unmarshal` sets the field to the value the function derives from the foreign value found
+// at the field path, or from the foreign struct holding the field when the path is `+`. Computed fields are only
+// written back by `Marshal` when an inverse is registered with RegisterInverseFunc, which receives a pointer to the
+// foreign value.
+//
+//	se.RegisterFunc("displayName", func(foreign any) (any, error) {
+//	    meta := foreign.(api.Metadata)
+//	    return meta.Namespace + "/" + meta.Name, nil
+//	})
+//
+//	type MyStruct struct {
+//	    DisplayName string `se:"Metadata,compute<displayName>"`
+//	}
+//
+// # Converted Fields
+//
+// Values needing a transformation on both directions, such as internal enums exposed as strings by an API, can use
+// a converter registered with RegisterNamedConverter and referenced with the `convert<name>` option. `Marshal`
+// writes the result of the first function into the foreign field, and `Unmarshal` sets the local field to the
+// result of the second one. The field and the foreign field must be of the types the converter was registered with.
+//
+//	se.RegisterNamedConverter("phase",
+//	    func(phase Phase) (string, error) { return phase.String(), nil },
+//	    func(name string) (Phase, error) { return ParsePhase(name) },
+//	)
+//
+//	type MyStruct struct {
+//	    Phase Phase `se:"Status.Phase,convert<phase>"`
+//	}
+//
+// Converters registered with RegisterConverter apply to every field of the first type mapping a foreign field of
+// the second one, without any tag option, lifting the requirement for both fields to be of the same type.
+//
+//	se.RegisterConverter(
+//	    func(t time.Time) (string, error) { return t.Format(time.RFC3339), nil },
+//	    func(s string) (time.Time, error) { return time.Parse(time.RFC3339, s) },
+//	)
+//
+// Teams migrating from mapstructure can reuse their decode hooks: FromDecodeHook adapts a `DecodeHookFunc` into a
+// conversion function, and RegisterDecodeHook registers a hook handling both directions as a converter.
+//
+//	decode, err := se.FromDecodeHook[string, time.Duration](mapstructure.StringToTimeDurationHookFunc())
+//	if err != nil {
+//	    return err
+//	}
+//	se.RegisterConverter(func(d time.Duration) (string, error) { return d.String(), nil }, decode)
+//
+// # Leaf Types
+//
+// Struct fields are mapped field by field, so struct types with unexported fields, like `uuid.UUID` wrappers or
+// `resource.Quantity`, would be left empty. Register them with `RegisterLeafType` to copy their values as a whole,
+// like scalars; fields of a leaf type, or pointers, slices and maps of it, must map foreign fields of the same type.
+// `time.Time` is registered by default.
+//
+//	func init() {
+//	    se.RegisterLeafType[resource.Quantity]()
+//	}
+//
+// # Identical Types
+//
+// Nested structs holding the very same type as the foreign field they map, eg a local struct embedding an API type as
+// is, are assigned as a whole instead of field by field, as long as the type declares no mappings of its own. Such
+// assignments are shallow copies sharing pointers, slices and maps with the source; register a copier with
+// `RegisterCopier` to copy the values of a type with a function instead, eg generated deep copy methods:
+//
+//	type Deployment struct {
+//		Name string         `se:"Metadata.Name"`
+//		Spec corev1.PodSpec `se:"Spec.Template.Spec"` // assigned as a whole
+//	}
+//
+//	func init() {
+//		se.RegisterCopier((*corev1.PodSpec).DeepCopyInto)
+//	}
+//
+// # Text Types
+//
+// Types implementing `encoding.TextMarshaler`, with their pointer implementing `encoding.TextUnmarshaler`, map
+// foreign strings through their text representation, and string fields map foreign fields of such types. It covers
+// custom IDs, enums and types like `net.IP` or `netip.Addr` without registering any converter.
+//
+//	type MyStruct struct {
+//	    Address net.IP `se:"Status.PodIP"` // a string in the foreign struct
+//	}
+//
+// # Bytes and Strings
+//
+// String fields can map foreign byte slices, and byte slices foreign strings, as API objects often carry `[]byte`
+// payloads, eg secrets, that internal models hold as strings. Set the `base64` option when the string holds the
+// standard base64 encoding of the bytes.
+//
+//	type MyStruct struct {
+//	    Password string `se:"Data.Password"`
+//	    Cert     string `se:"Data.Cert,base64"`
+//	}
+//
+// # Stringified Values
+//
+// Many APIs represent numbers as strings. Fields holding a boolean or a number can map a foreign string setting the
+// `stringify` option, formatting the value on `Marshal` and parsing it back on `Unmarshal`. The option works the
+// other way around too, for string fields mapping a foreign boolean or number.
+//
+//	type MyStruct struct {
+//	    Replicas int `se:"Spec.Replicas,stringify"`
+//	}
+//
+// # Time Layouts
+//
+// `time.Time` fields can map a foreign string, or string fields a foreign `time.Time`, setting the `time<layout>`
+// option with the name of a layout of the `time` package, eg `RFC3339` or `DateOnly`, or a literal layout without
+// commas.
+//
+//	type MyStruct struct {
+//	    CreatedAt time.Time `se:"Metadata.CreatedAt,time<RFC3339>"`
+//	    Day       time.Time `se:"Spec.Day,time<2006/01/02>"`
+//	}
+//
+// # Nullable Types
+//
+// The `database/sql` nullable types, eg `sql.NullString` or `sql.NullTime`, map fields holding the type they wrap,
+// or a pointer to it, on both sides of the mapping. Invalid values convert into the zero value, or a nil pointer,
+// while values written into a nullable field are set as valid.
+//
+//	type MyStruct struct {
+//	    Name      string     `se:"Name"`      // a sql.NullString in the foreign struct
+//	    DeletedAt *time.Time `se:"DeletedAt"` // a sql.NullTime in the foreign struct
+//	}
+//
+// Other types implementing `driver.Valuer`, with their pointer implementing `sql.Scanner`, map foreign strings,
+// booleans, numbers, byte slices and times, and the other way around. Values are written with the database value
+// returned by `Value`, converted into the type of the other side, and read back with `Scan`, receiving `int64` for
+// integers and `float64` for floats as a database driver would.
+//
+// # Protocol Buffers
+//
+// The protoconv subpackage registers converters for the protobuf well-known types found in generated API structs,
+// so internal models can keep using plain Go types. Importing it is enough, and keeps the protobuf module out of the
+// dependencies of the users who don't. `*timestamppb.Timestamp` foreign fields map `time.Time` and `*time.Time`
+// fields, while wrapper foreign fields, eg `*wrapperspb.StringValue`, map fields holding the wrapped type or a
+// pointer to it, which keeps track of unset values.
+//
+//	import _ "github.com/ilexPar/struct-marshal/protoconv"
+//
+//	type MyStruct struct {
+//	    CreatedAt time.Time `se:"CreateTime"`
+//	    Replicas  *int32    `se:"Replicas"` // a *wrapperspb.Int32Value in the foreign struct
+//	}
+//
+// # Numeric Conversion
+//
+// Fields must be of the same type as the foreign field they map, unless the codec is created with
+// WithNumericConversion, which converts between fields holding a single number of any type. Values are widened as
+// needed, while narrowing them fails with an error when they don't fit the destination type, or would lose their
+// fractional part when converted into an integer.
+//
+//	type MyStruct struct {
+//	    Replicas int `se:"Spec.Replicas"` // an int32 in the foreign struct
+//	}
+//
+//	codec := se.NewCodec(se.WithNumericConversion())
+//
+// # Mapping Direction
+//
+// Fields tagged `readonly` are populated by `Unmarshal` but never written back by `Marshal`, while `writeonly`
+// fields are only written by `Marshal`, which keeps status and spec fields apart in a single struct.
+//
+//	type MyStruct struct {
+//	    Replicas      int `se:"Spec.Replicas"`
+//	    ReadyReplicas int `se:"Status.ReadyReplicas,readonly"`
+//	}
+//
+// When the readable and writable locations differ, set a path for each direction with the `in<path>` and `out<path>`
+// options. The main path is used for the direction without its own path, unless it is `+`.
+//
+//	type MyStruct struct {
+//	    Phase string `se:"+,in<Status.Phase>,out<Spec.DesiredPhase>"`
+//	}
+//
+// # Conditional Mapping
+//
+// The `when<path=value>` option only maps a field while another foreign field holds the given value, which is
+// evaluated against the live foreign object: the source when unmarshaling and the destination when marshaling.
+// The compared path is relative to the parent field path, just like the field path, and must lead to a string,
+// boolean or number.
+//
+//	type MyStruct struct {
+//	    Type           string `se:"Spec.Type"`
+//	    LoadBalancerIP string `se:"Spec.LoadBalancerIP,when<Spec.Type=LoadBalancer>"`
+//	}
+//
+// # Per Kind Path
+//
+// When the foreign layout depends on the value of a discriminator field rather than on its Go type, the `kind<>`
+// option selects the path at runtime, comparing the foreign `Kind` field, or the one set with `kindfield<path>`.
+// Entries without their own path use the main path. When marshaling the discriminator is read from the destination,
+// so make sure it is set beforehand, e.g. by a field mapped to it.
+//
+//	type MyStruct struct {
+//	    Kind     string      `se:"Kind"`
+//	    Template PodTemplate `se:"+,kind<Deployment:Spec.Template|CronJob:Spec.JobTemplate.Spec.Template>"`
+//	}
+//
+// # Per Version Path
+//
+// Payloads of different API versions often share the same Go type but not the same layout. The `version<>` option
+// works just like `kind<>`, selecting the path by the value of the foreign `APIVersion` field, or the one set with
+// `versionfield<path>`, so a single local struct can translate every version.
+//
+//	type MyStruct struct {
+//	    APIVersion  string `se:"APIVersion"`
+//	    MinReplicas int    `se:"+,version<autoscaling/v1:Spec.MinReplicas|autoscaling/v2:Spec.Scale.Min>"`
+//	}
+//
+// # Mapping Report
+//
+// `UnmarshalWithReport` and `MarshalWithReport` map just like `Unmarshal` and `Marshal` and also return a `*Report`
+// listing every tagged field that was skipped along with the reason, which helps debugging mappings between large objects.
+//
+// # Skip Handler
+//
+// Codecs created with `WithSkipHandler` notify a function of every field a mapping report would list, as soon as it's
+// skipped, so silently skipped fields become observable during development without switching to the report functions.
+// The handler is called synchronously, and concurrently for the elements of slices split with `WithParallelSlices`.
+//
+//	codec := se.NewCodec(se.WithSkipHandler(func(skip se.FieldSkip) {
+//		slog.Warn("field skipped", "field", skip.Field, "reason", skip.Reason)
+//	}))
+//
+// # Errors
+//
+// Failures are reported with the exported `Err*` error variables, most of them wrapped along with the details of the
+// failure, so they're checked with `errors.Is` rather than by comparing error strings. Errors carrying structured
+// details have their own types, like `*FieldNotFoundError` or `*StrictError`, to be inspected with `errors.As`:
+//
+//	err := se.Unmarshal(src, &dst)
+//	if errors.Is(err, se.ErrForeignTypeMissingField) {
+//		var notFound *se.FieldNotFoundError
+//		errors.As(err, &notFound) // notFound.Path holds the missing path
+//	}
+//
+// Errors found with a field, introspecting its tag or mapping its value, are wrapped in a `*FieldError` identifying
+// the local type, the path of the field from it, the tag of the field and the foreign path it maps:
+//
+//	conversion error: unknown level 7 (field Spec.Containers[2].Level of app.Deployment, tag "Level,convert<level>", path Spec.Containers.Level)
+//
+// Introspection stops at the first invalid field by default. Codecs created with `WithAllErrors()` report every
+// invalid tag or path of the local types at once instead, joined with `errors.Join`, so they can be fixed in a
+// single pass:
+//
+//	err := se.NewCodec(se.WithAllErrors()).Unmarshal(src, &dst)
+//	log.Println(err) // one line per invalid field, each error a *se.FieldError
+//
+// # Panic Recovery
+//
+// Mapping never panics out of `Marshal` and `Unmarshal`. Tags on unexported fields, local or foreign, fail
+// introspection with `ErrUnexportedField`, and panics raised while mapping values, eg by converters, computed fields
+// functions or hooks, are recovered and returned as errors wrapping `ErrMappingPanic`, within a `*FieldError`
+// identifying the local field being mapped.
+//
+// # Strict Mode
+//
+// By default fields that can't be transferred are silently skipped: nil pointers or empty slices found in the
+// source path, zero values, and fields whose `types<>` option doesn't match the foreign type.
+// Use `UnmarshalStrict` or `MarshalStrict` when you treat silent data loss as a bug, they map everything they can and
+// then return a `*StrictError` listing every skipped field along with the reason. Codecs created with `WithStrict()` do
+// the same.
+//
+// # Required Fields
+//
+// Mark mandatory fields with the `required` option to validate them at translation time: instead of skipping them
+// `Unmarshal` fails when the foreign value is zero or can't be reached, and `Marshal` fails when the local value is
+// zero. The option applies to fields holding values, slices or maps, and nil local pointers to nested structs.
+//
+//	type MyStruct struct {
+//	    Name string `se:"Metadata.NameField,required"`
+//	}
+//
+// # Field Presence
+//
+// `UnmarshalPresence` decodes just like `Unmarshal` and also returns the set of local fields that were actually
+// populated from the foreign object, similar to a protobuf field mask. It tells fields absent from the source apart
+// from fields holding a zero value.
+//
+// # Comparing
+//
+// `MappedEqual(local, foreign)` reports whether the foreign object already reflects the local struct values, that is,
+// whether `Marshal` would leave every mapped foreign field unchanged. Reconcile loops can use it to skip no-op updates.
+//
+// # Lifecycle Hooks
+//
+// Local structs can implement `BeforeMarshal(foreign any) error`, called before `Marshal` transfers any field, and
+// `AfterUnmarshal(foreign any) error`, called once `Unmarshal` transferred every field, to compute derived fields or
+// normalize their data as part of the translation. Both receive the foreign object given to the call, and their
+// errors abort it. Only the hooks of the top level struct are called.
+//
+//	func (this *MyStruct) AfterUnmarshal(foreign any) error {
+//	    this.Name = strings.ToLower(this.Name)
+//	    return nil
+//	}
+//
+// Cross-cutting behavior like auditing or defaulting can be attached to the translation between a pair of types
+// without modifying the structs, registering `Hooks` with callbacks run before and after encoding and decoding.
+//
+//	se.RegisterHooks(se.Hooks[MyStruct, module.SomeStruct]{
+//	    BeforeDecode: func(local *MyStruct, foreign *module.SomeStruct) error {
+//	        local.Replicas = 1 // default, kept unless the foreign struct sets it
+//	        return nil
+//	    },
+//	})
+//
+// # Dependency Injection
+//
+// `NewCodec()` returns a `*Codec` implementing the `Mapper` interface, which exposes the same `Marshal` and `Unmarshal`
+// methods as the package. Depend on `Mapper` in your services so the translation layer can be mocked in unit tests.
+//
+// # Options
+//
+// Codecs are configured with options, like `WithStrict()`, `WithCopyZero()` or `WithMapMerge()`, described along with
+// the behavior they tune. `MarshalWithOptions` and `UnmarshalWithOptions` map a single pair of objects with the
+// provided options, while `Marshal` and `Unmarshal` use the defaults. Create a `Codec` once when mapping many objects
+// with the same options.
+//
+//	err := se.UnmarshalWithOptions(src, dst, se.WithStrict(), se.WithCopyZero(reflect.Bool))
+//
+// # Field Selection
+//
+// A subset of the tagged fields can be mapped with the `WithOnly` and `WithExclude` options, eg to update only the
+// mutable fields of an object, without defining new structs. Fields are named by their local path, like
+// `Nested.Direction`, and naming a struct field covers every field nested in it. Naming a field the local type doesn't
+// map fails with `ErrUnknownLocalField`, so typos don't go unnoticed.
+//
+//	err := se.MarshalWithOptions(src, dst, se.WithOnly("Replicas", "Labels"))
+//
+// # Depth Limit
+//
+// Codecs created with `WithMaxDepth(n)` refuse mappings reaching deeper than `n`, as a guard against deeply nested or
+// recursive types, eg foreign types generated from untrusted schemas. Introspecting a pair of types fails with
+// `ErrMaxDepth` when a foreign path has more than `n` segments, or when local structs are nested more than `n` levels
+// deep.
+//
+//	codec := se.NewCodec(se.WithMaxDepth(16))
+//
+// # Tag Keys
+//
+// Mappings are read from the `se` tag key by default. Codecs created with `WithTagKey` read them from another key, so
+// a local struct can carry several independent mapping schemes, eg one for each version of an API.
+//
+//	type MyStruct struct {
+//	    Name string `se_v1:"Spec.Name" se_v2:"Metadata.Name"`
+//	}
+//
+//	codec := se.NewCodec(se.WithTagKey("se_v2"))
+//
+// # Protobuf Field Names
+//
+// The Go field names protoc-gen-go generates often diverge from the proto schema names users know. Codecs created
+// with `WithProtoNames` also resolve path segments by the proto field name or protojson name of generated structs,
+// read from their `protobuf` tags. Go field names keep matching and take precedence.
+//
+//	type MyStruct struct {
+//	    MinReplicas int32 `se:"spec.min_replicas"` // or "spec.minReplicas", resolving Spec.MinReplicas
+//	}
+//
+//	codec := se.NewCodec(se.WithProtoNames())
+//
+// # Programmatic Mappings
+//
+// Third-party structs whose source can't be tagged can be mapped with a Mapping, describing each field with the
+// paths and options its tag would hold. `Register` validates the mapping by introspecting both types, and from then
+// on the fields are mapped as if they were tagged accordingly.
+//
+//	err := se.NewMapping[vendor.Config, APIObject]().
+//	    Field("Name").To("Metadata.NameField").
+//	    Field("Replicas").To("Spec.Replicas").Options("required").
+//	    Field("Internal").Skip().
+//	    Register()
+//
+// Alternatively, RegisterTagSource registers a shadow struct declaring fields of the same names as a generated or
+// vendored struct, whose tags are read in place of the ones of the real struct.
+//
+//	type ConfigTags struct {
+//	    Name     string `se:"Metadata.NameField"`
+//	    Replicas int    `se:"Spec.Replicas,required"`
+//	}
+//
+//	func init() {
+//	    if err := se.RegisterTagSource[vendor.Config, ConfigTags](); err != nil {
+//	        panic(err)
+//	    }
+//	}
+//
+// # Mapping Specifications
+//
+// Mappings can also be loaded at runtime from a MappingSpec document, so operators can tweak translations without
+// recompiling. LoadMapping reads the document as YAML, or as JSON when it starts with `{`, rejecting unknown
+// attributes, while NewMappingFromSpec builds the mapping from a spec decoded by other means.
+//
+//	fields:
+//	  - name: Name
+//	    to: Metadata.NameField||Metadata.Name
+//	  - name: Replicas
+//	    to: Spec.Replicas
+//	    options: [required]
+//
+//	mapping, err := se.LoadMapping[vendor.Config, APIObject](file)
+//	if err != nil {
+//	    return err
+//	}
+//	err = mapping.Register()
+//
+// # Path Resolution
+//
+// `ResolvePath(foreignType, path)` resolves an `se`-style path against a foreign type just like tags are resolved,
+// returning the `TargetField` it leads to, or a `*FieldNotFoundError`, without introspecting any local type. It's meant
+// for tooling validating paths built at runtime, eg from configuration.
+//
+//	target, err := se.ResolvePath(reflect.TypeFor[api.Deployment](), "Spec.Template.Spec.Containers[0].Image")
+//
+// For one-off dynamic access, `GetPath(obj, path)` reads the value found at a path of a struct, reporting whether a
+// nil pointer or an empty slice was found along the way, while `SetPath(&obj, path, value)` writes it, allocating
+// what's missing along the path just like `Marshal` does. Paths leading to no field fail with a `*PathError` naming
+// the segment, along with its index when it isn't one of `[0]`, `[last]`, `[-1]` or `[*]`, see `ErrUnsupportedIndex`.
+//
+//	image, ok, err := se.GetPath(deployment, "Spec.Template.Spec.Containers[0].Image")
+//	err = se.SetPath(&deployment, "Spec.Template.Spec.Containers[0].Image", "nginx:latest")
+//
+// # Static Checks
+//
+// The `sevet` command checks `se` tags at build time, reporting paths leading to no foreign field and fields mapping
+// foreign fields of another type, which would otherwise fail on the first call to the mapping. As foreign types are
+// only known when mapping, tags are resolved against the foreign types named by their `types<>` option.
+//
+//	go install github.com/ilexPar/struct-marshal/cmd/sevet
+//	go vet -vettool=$(which sevet) ./...
+//
+// The checker is also available as a go/analysis analyzer in the `analyzer` package, to be run along with others.
+//
+// Other tools can reuse the exact tag grammar instead of re-implementing it: `ParseTag(field, key)` parses the tag of
+// a reflected field and `ParseTagValue(fieldName, value)` a tag value read from source code, while `Validate()`
+// reports the errors the mapping would fail with regardless of the foreign type.
+//
+//	tag := se.ParseTagValue("Replicas", "+,types<Deployment:Spec.Replicas|Service:Port>")
+//	err := tag.Validate()
+//
+// # Generated Mappers
+//
+// The `segen` command generates plain Go functions mapping pairs of local and foreign structs from the `se` tags of the
+// local ones, eg `EncodeMyStructToSomeStruct` and `DecodeSomeStructToMyStruct`, along with an init function registering
+// them with `RegisterGenerated`. From then on `Marshal` and `Unmarshal` call the generated functions for those types
+// instead of walking their representation, unless the codec sets options changing which fields are mapped, or how.
+// Lifecycle hooks still run around them.
+//
+//	//go:generate segen -o mappers_se.go MyStruct=module.SomeStruct
+//
+// Only tags whose mapping is known at build time are supported: paths to fields of nested structs, the `=` and `->`
+// paths, nested local structs, alternative paths, and the `types<>`, `readonly` and `writeonly` options, between
+// fields of the same type. The command fails for pairs of types relying on anything else, which keep being mapped by
+// reflection.
+//
+// # Adapters
+//
+// Hot pairs of types can be mapped by hand-written functions instead: `RegisterAdapter(localType, foreignType, encode,
+// decode)` makes `Marshal` and `Unmarshal` call them for those types, receiving pointers to both values, under the same
+// conditions as generated mappers. Either function may be nil, leaving that direction to the reflection walk.
+//
+//	err := se.RegisterAdapter(reflect.TypeFor[MyStruct](), reflect.TypeFor[module.SomeStruct](),
+//		func(from, into any) error {
+//			into.(*module.SomeStruct).Spec.Image = from.(*MyStruct).Image
+//			return nil
+//		},
+//		nil,
+//	)
+//
+// # Compiled Mappers
+//
+// `NewMapper(local, foreign, opts...)` introspects a pair of types once and returns a `*CompiledMapper`, whose
+// `Encode` and `Decode` methods map values of those types just like `Marshal` and `Unmarshal`, carrying their own
+// representation instead of going through the introspection cache on every call.
+//
+//	mapper, err := se.NewMapper(MyStruct{}, module.SomeStruct{})
+//	err = mapper.Decode(foreign, &local)
+//
+// # Auto Mapping
+//
+// Codecs created with `WithAutoMap()` map untagged exported fields to the foreign field of the same name, deep through
+// nested structs, as long as the foreign field exists and its type is compatible. Tag a field with `se:"-"` to leave it
+// out, and use `codec.AutoMapped(local, foreign)` to review which fields were matched by name.
+//
+//	codec := se.NewCodec(se.WithAutoMap())
+//
+// # Logging
+//
+// Internal decisions like introspection cache misses, skipped fields and allocations can be logged at debug level by
+// providing a logger to the codec. Any type with a `Debug(msg string, args ...any)` method will do, like `*slog.Logger`.
+//
+//	codec := se.NewCodec(se.WithLogger(slog.Default()))
+//
+// # Zero Values
+//
+// By default zero values are considered empty and skipped, both when encoding and decoding. When some kinds' zero
+// values are legitimate values to copy, configure the codec accordingly, e.g. to transfer `false` and `0` while
+// still skipping empty strings:
+//
+//	codec := se.NewCodec(se.WithCopyZero(reflect.Bool, reflect.Int))
+//
 // # Introspection Caching
 //
 // Analyzed structs get cached to prevent unnecessary processing.
@@ -84,50 +863,251 @@
 // You can preload introspection cache by calling `Introspect(local, foreign)`.
 // Where `local` is the struct annotated with `se` tag, and `foreign` is the struct target for those tags
 //
+// `IntrospectAll(pairs...)` introspects many pairs of types at once, so services can fail fast at boot instead of on the
+// first request mapping a broken pair. The errors of every failing pair are returned joined, and codecs created with
+// `WithParallelism(n)` introspect the pairs in parallel:
+//
+//	err := se.IntrospectAll(
+//		se.PairOf[Deployment, appsv1.Deployment](),
+//		se.Pair{Local: Service{}, Foreign: corev1.Service{}},
+//	)
+//	err = se.NewCodec(se.WithParallelism(4)).IntrospectAll(pairs...)
+//
+// Services with huge API surfaces can also warm the cache at startup from a file generated ahead, eg in CI: `ExportCache(w)`
+// writes the cached representations, and `ImportCache(r)` reads them back. Imported representations are only used for
+// types whose layout matches the exported one, every other type keeps being introspected:
+//
+//	// in CI, after introspecting every pair of types
+//	se.ExportCache(file)
+//	// at startup
+//	se.ImportCache(file)
+//
 // In case of need cache can be cleared by calling `ClearTypeCache()`.
+// `CacheMemoryUsage()` reports the number of cached entries and an estimate of the memory they hold, which helps
+// deciding when to clear the cache in long-running processes.
+//
+// `CacheStats()` reports the cache hits and misses along with the time spent introspecting the types that missed it,
+// `PublishCacheStats(name)` exports them as an `expvar` variable, and codecs created with
+// `WithIntrospectionObserver(fn)` call `fn` with the types and the time taken every time they introspect types
+// missing from the cache, eg to feed a metrics system:
+//
+//	se.PublishCacheStats("se_cache")
+//	codec := se.NewCodec(se.WithIntrospectionObserver(func(local, foreign reflect.Type, elapsed time.Duration) {
+//		introspectionSeconds.Observe(elapsed.Seconds())
+//	}))
+//
+// The cache is shared by the package-level functions and every `Codec`. Independent components of a process can keep
+// their mappings apart by creating their codec with `WithPrivateCache()`, the package-level `ClearTypeCache()` leaves
+// private caches untouched:
+//
+//	codec := se.NewCodec(se.WithPrivateCache())
+//	codec.Marshal(local, &foreign)
+//	codec.CacheMemoryUsage() // entries held by the codec cache only
+//	codec.ClearTypeCache()   // empties the codec cache only
 package pkg
 
+import "errors"
+
 const (
 	// Operators
 	//
 	// field tag to parse
 	FIELD_TAG_KEY = "se"
+	// tags protoc-gen-go sets on the fields of generated structs, holding their proto schema names
+	PROTOBUF_TAG_KEY       = "protobuf"
+	PROTOBUF_ONEOF_TAG_KEY = "protobuf_oneof"
 	// type separator when encoding to multiple types from a single source, eg se:"example,types<Struct1|Struct2>"
 	TYPES_SPLIT = "|"
 	// type path separator when setting per type path, eg se:"+,types<Struct1:path.one|Struct2:path.name>"
 	TYPES_PATH_SPLIT = ":"
+	// prefix of the type matching entries holding a regular expression matched against the foreign type names,
+	// eg se:"Spec.Replicas,types<~^Deployment(V1)?$>"
+	TYPE_PATTERN_PREFIX = "~"
 	// path to be used when dismissing path nesting
 	DISMISS_NESTED = "->"
+	// prefix of the paths stripping a segment of the inherited parent path, repeated for each segment, eg se:"^^Name"
+	PARENT_PATH       = "^"
+	PARENT_PATH_ALIAS = "../"
+	// prefix of the paths resolved from the root of the mapping instead of the parent path, eg se:"/Metadata.Name"
+	ABSOLUTE_PATH = "/"
+	// delimiters of the path segments asserting the concrete type held by an interface field, set with the `as<>`
+	// option, eg se:"Object.Spec.Replicas,as<Deployment>" is resolved into `Object.(Deployment).Spec.Replicas`
+	ASSERTION_PREFIX = "("
+	ASSERTION_SUFFIX = ")"
 	// path name to be used when setting per type path, eg se:"+,types<Struct1:path.one|Struct2:path.name>"
 	MULTI_TYPE_NAME = "+"
+	// separator of the alternative paths of a field, eg se:"Spec.Name||Spec.OldName"
+	FALLBACK_SPLIT = "||"
+	// path mapping to the foreign field named after the local field, eg se:"="
+	SAME_NAME = "="
+	// foreign field holding the discriminator compared by the per-kind paths, eg se:"+,kind<Deployment:Spec.Template>"
+	DEFAULT_KIND_FIELD = "Kind"
+	// foreign field holding the API version compared by the per-version paths, eg se:"+,version<v1:Spec.Replicas>"
+	DEFAULT_VERSION_FIELD = "APIVersion"
+	// tag excluding a field from the mapping, also when auto-mapping untagged fields, eg se:"-"
+	SKIP_FIELD = "-"
+	// tag option keeping the local slice elements the mapping doesn't touch when unmarshaling
+	PRESERVE_OPT = "preserve"
+	// tag option failing the mapping when the field value can't be transferred, eg se:"Metadata.Name,required"
+	REQUIRED_OPT = "required"
+	// tag option dismissing the field when its path doesn't exist in the foreign type, eg se:"Spec.Paused,optional"
+	OPTIONAL_OPT = "optional"
+	// tag option making a field only be decoded from the foreign object, never encoded into it
+	READ_ONLY_OPT = "readonly"
+	// tag option making a field only be encoded into the foreign object, never decoded from it
+	WRITE_ONLY_OPT = "writeonly"
+	// tag option making the string a byte slice is mapped from or to hold its base64 encoding, eg se:"Data,base64"
+	BASE64_OPT = "base64"
+	// tag option representing a boolean or number as a string on the other side, eg se:"Spec.Replicas,stringify"
+	STRINGIFY_OPT = "stringify"
+	// index selecting the first element of a slice in a path, eg `Items[0]`
+	INDEX_FIRST = "0"
+	// index selecting every element of a slice in a path, eg `Items[*].Name`
+	INDEX_ALL = "*"
+	// indexes selecting the last element of a slice in a path, eg `Items[last].Name` or `Items[-1].Name`
+	INDEX_LAST          = "last"
+	INDEX_LAST_NEGATIVE = "-1"
 
-	TYPE_OPTS_REGEX = `^types<([^>]+)>$`
+	TYPE_OPTS_REGEX          = `^types<([^>]+)>$`
+	MERGE_OPTS_REGEX         = `^merge<([^>]+)>$`
+	MERGE_KEY_OPTS_REGEX     = `^mergekey<([^>]+)>$`
+	CONST_OPTS_REGEX         = `^const<([^>]*)>$`
+	IN_OPTS_REGEX            = `^in<([^>]+)>$`
+	OUT_OPTS_REGEX           = `^out<([^>]+)>$`
+	WHEN_OPTS_REGEX          = `^when<([^=>]+)=([^>]*)>$`
+	KIND_OPTS_REGEX          = `^kind<([^>]+)>$`
+	KIND_FIELD_OPTS_REGEX    = `^kindfield<([^>]+)>$`
+	VERSION_OPTS_REGEX       = `^version<([^>]+)>$`
+	VERSION_FIELD_OPTS_REGEX = `^versionfield<([^>]+)>$`
+	JOIN_OPTS_REGEX          = `^join<(.+)>$`
+	SPLIT_OPTS_REGEX         = `^split<(.+)>$`
+	COMPUTE_OPTS_REGEX       = `^compute<([^>]+)>$`
+	CONVERT_OPTS_REGEX       = `^convert<([^>]+)>$`
+	TIME_OPTS_REGEX          = `^time<([^>]+)>$`
+	AS_OPTS_REGEX            = `^as<([^>]+)>$`
 )
 
-const (
-	ErrUnmarshalDestType        = "into parameter must be a pointer to a non-nil struct"
-	ErrUnmarshalSrcType         = "from parameter must be a struct or a pointer to a non nil struct"
-	ErrLocalTypeNotStruct       = "local type must be a struct or non-nil pointer to a struct"
-	ErrForeignTypeNotStruct     = "foreign type must be a struct or non-nil pointer to a struct"
-	ErrLocalTypeMissingValidTag = "could not find any serializable field"
-	ErrForeignTypeMissingField  = "field not found in path:"
-	ErrForeignTypeMismatch      = "field type mismatch:"
-	ErrInvalidPerTypePath       = "main path should be '+' when using per-type path matching"
+// Errors returned by the package, most of them wrapped along with details about the failure: check them with
+// errors.Is instead of comparing error strings.
+var (
+	ErrUnmarshalDestType        = errors.New("into parameter must be a pointer to a non-nil struct")
+	ErrUnmarshalSrcType         = errors.New("from parameter must be a struct or a pointer to a non nil struct")
+	ErrLocalTypeNotStruct       = errors.New("local type must be a struct or non-nil pointer to a struct")
+	ErrForeignTypeNotStruct     = errors.New("foreign type must be a struct or non-nil pointer to a struct")
+	ErrLocalTypeMissingValidTag = errors.New("could not find any serializable field")
+	ErrForeignTypeMissingField  = errors.New("field not found in path:")
+	ErrForeignTypeMismatch      = errors.New("field type mismatch:")
+	ErrInvalidPerTypePath       = errors.New("main path should be '+' when using per-type path matching")
+	ErrStrictSkippedFields      = errors.New("some fields could not be transferred:")
+	ErrWildcardLocalType        = errors.New("wildcard paths can only be mapped to local slices:")
+	ErrInvalidMapMergeMode      = errors.New("invalid map merge mode:")
+	ErrArrayLengthMismatch      = errors.New("array length mismatch:")
+	ErrArrayOverflow            = errors.New("slice does not fit destination array:")
+	ErrInvalidMergeKey          = errors.New("merge key must name a mapped non struct field of the slice elements:")
+	ErrRequiredField            = errors.New("required field could not be transferred:")
+	ErrInvalidConstValue        = errors.New("invalid constant value:")
+	ErrConflictingDirection     = errors.New("readonly fields can't be writeonly nor constant")
+	ErrInvalidDirectionalPath   = errors.New("per direction paths can't be combined with per-type paths")
+	ErrInvalidCondition         = errors.New("conditions must compare a string, boolean or number foreign field:")
+	ErrInvalidKindPath          = errors.New("per-kind paths can't be combined with conditions, per-type or per direction paths")
+	ErrMissingKindPath          = errors.New("main path should be set when per-kind entries don't set their own path:")
+	ErrInvalidVersionPath       = errors.New("per-version paths can't be combined with conditions, per-type, per-kind or per direction paths")
+	ErrMissingVersionPath       = errors.New("main path should be set when per-version entries don't set their own path:")
+	ErrInvalidFallbackPath      = errors.New("alternative paths can't be combined with per-type, per-kind, per-version or per direction paths")
+	ErrInvalidJoin              = errors.New("invalid join:")
+	ErrJoinMismatch             = errors.New("value doesn't match the joined fields:")
+	ErrUnknownFunc              = errors.New("function not registered:")
+	ErrComputeFunc              = errors.New("compute function error:")
+	ErrConflictingCompute       = errors.New("computed fields can't be joined nor constant")
+	ErrUnknownConverter         = errors.New("converter not registered:")
+	ErrConvertFunc              = errors.New("conversion error:")
+	ErrConverterTypeMismatch    = errors.New("converter type mismatch:")
+	ErrConflictingConvert       = errors.New("converted fields can't be computed, joined nor constant")
+	ErrNumericOverflow          = errors.New("numeric value out of range:")
+	ErrInvalidBase64            = errors.New("base64 option requires mapping a string and a byte slice:")
+	ErrInvalidStringify         = errors.New("stringify option requires mapping a string and a boolean or number:")
+	ErrInvalidTimeLayout        = errors.New("time option requires mapping a time.Time and a string:")
+	ErrInvalidTypePattern       = errors.New("invalid type pattern:")
+	ErrHookFunc                 = errors.New("hook error:")
+	ErrUnknownLocalField        = errors.New("field not found in local type:")
+	ErrInvalidMappingSpec       = errors.New("invalid mapping specification:")
+	ErrInvalidDecodeHook        = errors.New("decode hook must be a mapstructure DecodeHookFunc:")
+	ErrDecodeHookResult         = errors.New("decode hook result type mismatch:")
+	ErrMapperTypeMismatch       = errors.New("values don't match the types of the mapper:")
+	ErrWildcardPath             = errors.New("wildcard indexes select no single value to get or set:")
+	ErrUnsupportedIndex         = errors.New("unsupported index, only [0], [last], [-1] and [*] select elements:")
+	ErrPathValueType            = errors.New("value type can't be assigned to the path:")
+	ErrCacheVersion             = errors.New("exported cache written by an incompatible version:")
+	ErrMaxDepth                 = errors.New("maximum depth exceeded:")
+	ErrMappingPanic             = errors.New("mapping panicked:")
+	ErrUnexportedField          = errors.New("unexported fields can't be mapped:")
+	ErrInvalidRelativePath      = errors.New("relative path can't leave the parent path nor the collection elements:")
+	ErrConcreteType             = errors.New("interface fields are only traversed asserting a registered type:")
 )
 
 // Unmarshal decodes a source object into a destination object using the struct mapping (sm) tags.
 // The `from` parameter is the source object to decode from, which must be a struct or a pointer to a non-nil struct.
 // The `into` parameter is the destination object to decode into, which must be a pointer to a non-nil struct.
 // Fields are mapped according to the sm tag rules defined in the package documentation.
+// When `from` is a []any holding several source objects, eg the spec and the status of a resource, every one of
+// them is decoded into the destination in order, each one filling the fields whose `types<>` option matches its type.
 // Returns an error if the types are invalid or if the decoding process fails.
 func Unmarshal(from interface{}, into interface{}) error {
-	cacheInit()
-	decoder := &StructDecoder{}
-	if err := decoder.init(from, into); err != nil {
+	return defaultCodec.Unmarshal(from, into)
+}
+
+// UnmarshalWithOptions decodes a source object into a destination object just like Unmarshal does, with its
+// behavior tuned by the provided options, eg UnmarshalWithOptions(from, into, WithStrict(), WithCopyZero(reflect.Bool)).
+// It's a shorthand for NewCodec(opts...).Unmarshal(from, into), prefer creating a Codec once when mapping many
+// objects with the same options.
+func UnmarshalWithOptions(from interface{}, into interface{}, opts ...Option) error {
+	return NewCodec(opts...).Unmarshal(from, into)
+}
+
+// UnmarshalStrict decodes a source object into a destination object just like Unmarshal does, but instead
+// of silently skipping fields it keeps track of every tagged field that could not be transferred, either
+// because a nil pointer or an empty slice was found in the foreign path, the foreign value was zero,
+// or the type matching option didn't match.
+// Fields that could be transferred are still decoded; when any field was skipped a *StrictError
+// listing all of them is returned.
+func UnmarshalStrict(from interface{}, into interface{}) error {
+	skips, _, err := defaultCodec.unmarshal(from, into, true, false)
+	if err != nil {
 		return err
 	}
 
-	return decoder.run()
+	if len(skips) > 0 {
+		return &StrictError{Skips: skips}
+	}
+
+	return nil
+}
+
+// UnmarshalWithReport decodes a source object into a destination object just like Unmarshal does, and returns
+// a Report listing every tagged field that could not be transferred and why, making it easy to debug mappings
+// between large objects. Unlike UnmarshalStrict skipped fields are not treated as an error.
+func UnmarshalWithReport(from interface{}, into interface{}) (*Report, error) {
+	skips, _, err := defaultCodec.unmarshal(from, into, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{Skipped: skips}, nil
+}
+
+// UnmarshalPresence decodes a source object into a destination object just like Unmarshal does, and returns
+// the set of local fields that were actually populated from the foreign object, similar to a protobuf field mask.
+// It allows telling fields absent from the source apart from fields holding a zero value: a field whose foreign
+// value was skipped is not in the set, even if the local field holds a value.
+// Slices and maps are included as a whole along with the fields of their elements, eg "Items" and
+// "Items[0].Name".
+func UnmarshalPresence(from interface{}, into interface{}) (FieldSet, error) {
+	_, presence, err := defaultCodec.unmarshal(from, into, false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return presence, nil
 }
 
 // Marshal encodes a source object into a destination object using the struct mapping (sm) tags.
@@ -136,10 +1116,86 @@ func Unmarshal(from interface{}, into interface{}) error {
 // Fields are mapped according to the sm tag rules defined in the package documentation.
 // Returns an error if the types are invalid or if the encoding process fails.
 func Marshal(from interface{}, into interface{}) error {
-	cacheInit()
-	encoder := &StructEncoder{}
-	if err := encoder.init(from, into); err != nil {
+	return defaultCodec.Marshal(from, into)
+}
+
+// MarshalWithOptions encodes a source object into a destination object just like Marshal does, with its behavior
+// tuned by the provided options, eg MarshalWithOptions(from, into, WithStrict(), WithMapMerge(MapMergeKeep)).
+// It's a shorthand for NewCodec(opts...).Marshal(from, into), prefer creating a Codec once when mapping many
+// objects with the same options.
+func MarshalWithOptions(from interface{}, into interface{}, opts ...Option) error {
+	return NewCodec(opts...).Marshal(from, into)
+}
+
+// MarshalAll encodes a source object into several destination objects in one call, as if calling Marshal once for
+// each of them, so a local struct annotated with `types<>` for several foreign types can populate all of them: each
+// destination only receives the fields whose type matching option matches its type.
+// Destinations are encoded in order, the first error stops the process and is returned along with the position
+// and type of the destination that failed.
+func MarshalAll(from interface{}, into ...interface{}) error {
+	return defaultCodec.MarshalAll(from, into...)
+}
+
+// MarshalStrict encodes a source object into a destination object just like Marshal does, but instead
+// of silently skipping fields it keeps track of every tagged field that could not be transferred, either
+// because a nil pointer or an empty slice was found in the local struct, the local value was zero,
+// or the type matching option didn't match.
+// Fields that could be transferred are still encoded; when any field was skipped a *StrictError
+// listing all of them is returned.
+func MarshalStrict(from interface{}, into interface{}) error {
+	skips, err := defaultCodec.marshal(from, into, true)
+	if err != nil {
 		return err
 	}
-	return encoder.run()
+
+	if len(skips) > 0 {
+		return &StrictError{Skips: skips}
+	}
+
+	return nil
+}
+
+// MarshalWithReport encodes a source object into a destination object just like Marshal does, and returns
+// a Report listing every tagged field that could not be transferred and why, either because a nil pointer
+// or an empty slice was found in the local struct, the local value was zero, or the type matching option
+// didn't match.
+func MarshalWithReport(from interface{}, into interface{}) (*Report, error) {
+	skips, err := defaultCodec.marshal(from, into, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{Skipped: skips}, nil
+}
+
+// UnmarshalNew allocates a new local struct of type L, decodes the `foreign` object into it and returns it.
+// It behaves exactly like Unmarshal, but saves the caller from declaring the destination beforehand,
+// making one-liner translations possible.
+// Returns the zero value of L along with the error if the decoding process fails.
+func UnmarshalNew[L any](foreign interface{}) (L, error) {
+	var local L
+	if err := Unmarshal(foreign, &local); err != nil {
+		var empty L
+		return empty, err
+	}
+	return local, nil
+}
+
+// MapTo allocates a new foreign struct of type F, encodes the `from` local object into it and returns it.
+// It behaves exactly like Marshal, but saves the caller from declaring the destination beforehand.
+// Returns the zero value of F along with the error if the encoding process fails.
+func MapTo[F any](from any) (F, error) {
+	var foreign F
+	if err := Marshal(from, &foreign); err != nil {
+		var empty F
+		return empty, err
+	}
+	return foreign, nil
+}
+
+// MapInto allocates a new local struct of type L, decodes the `from` foreign object of type F into it and returns
+// it. It behaves exactly like UnmarshalNew, while also checking the type of the foreign object at compile time.
+// Returns the zero value of L along with the error if the decoding process fails.
+func MapInto[F, L any](from F) (L, error) {
+	return UnmarshalNew[L](from)
 }
@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CompiledMapper maps values of a single pair of local and foreign struct types, carrying its own representation
+// of them computed once by NewMapper. Unlike Marshal and Unmarshal, mapping values doesn't go through the
+// introspection cache, so it's not affected by ClearTypeCache.
+type CompiledMapper struct {
+	local          reflect.Type
+	foreign        reflect.Type
+	representation StructRepr
+	reprs          representations
+	opts           *options
+}
+
+// NewMapper introspects the types of the provided prototypes once, and returns a CompiledMapper encoding and
+// decoding values of those types with the provided options.
+//
+// Parameters:
+//   - localPrototype: A value, or pointer, of the local struct type annotated with `se` tags
+//   - foreignPrototype: A value, or pointer, of the foreign struct type the tags refer to
+//   - opts: The options tuning the behavior of the mapper, see Option
+//
+// Returns:
+//   - *CompiledMapper: The mapper for the prototype types
+//   - error: Any error found introspecting the types
+func NewMapper(localPrototype, foreignPrototype interface{}, opts ...Option) (*CompiledMapper, error) {
+	if localPrototype == nil {
+		return nil, ErrLocalTypeNotStruct
+	}
+	if foreignPrototype == nil {
+		return nil, ErrForeignTypeNotStruct
+	}
+
+	mapper := &CompiledMapper{
+		local:   derefType(reflect.TypeOf(localPrototype)),
+		foreign: derefType(reflect.TypeOf(foreignPrototype)),
+		opts:    newOptions(opts...),
+	}
+	if err := mapper.representation.introspect(localPrototype, foreignPrototype, mapper.opts); err != nil {
+		return nil, err
+	}
+	mapper.reprs = mapper.opts.cache.representations().snapshot(mapper.representation)
+	return mapper, nil
+}
+
+// Encode encodes a local value into a foreign one, just like Marshal does.
+//
+// Parameters:
+//   - from: A value, or pointer to a non-nil value, of the local type of the mapper
+//   - into: A pointer to a non-nil value of the foreign type of the mapper
+//
+// Returns:
+//   - error: Any error found encoding the value, or ErrMapperTypeMismatch when the types don't match the mapper's
+func (this *CompiledMapper) Encode(from interface{}, into interface{}) error {
+	encoder := acquireEncoder(this.opts, this.opts.strict)
+	defer encoder.release()
+	encoder.local = reflect.ValueOf(from)
+	encoder.foreign = reflect.ValueOf(into)
+	encoder.representation = &this.representation
+	encoder.reprs = this.reprs
+	if err := encoder.validateInput(); err != nil {
+		return err
+	}
+	if err := this.validateTypes(encoder.local, encoder.foreign); err != nil {
+		return err
+	}
+
+	if err := encoder.run(); err != nil {
+		return err
+	}
+	return this.opts.strictError(encoder.skips)
+}
+
+// Decode decodes a foreign value into a local one, just like Unmarshal does.
+//
+// Parameters:
+//   - from: A value, or pointer to a non-nil value, of the foreign type of the mapper
+//   - into: A pointer to a non-nil value of the local type of the mapper
+//
+// Returns:
+//   - error: Any error found decoding the value, or ErrMapperTypeMismatch when the types don't match the mapper's
+func (this *CompiledMapper) Decode(from interface{}, into interface{}) error {
+	decoder := acquireDecoder(this.opts, this.opts.strict)
+	defer decoder.release()
+	decoder.local = reflect.ValueOf(into)
+	decoder.foreign = reflect.ValueOf(from)
+	decoder.representation = &this.representation
+	decoder.reprs = this.reprs
+	if err := decoder.validateInput(); err != nil {
+		return err
+	}
+	if err := this.validateTypes(decoder.local, decoder.foreign); err != nil {
+		return err
+	}
+
+	if err := decoder.run(); err != nil {
+		return err
+	}
+	return this.opts.strictError(decoder.skips)
+}
+
+// validateTypes checks that the local and foreign values, already validated by the encoder or decoder, are of
+// the types the mapper was created for.
+func (this *CompiledMapper) validateTypes(local, foreign reflect.Value) error {
+	if !local.IsValid() || derefType(local.Type()) != this.local {
+		return fmt.Errorf("%w expected local %v", ErrMapperTypeMismatch, this.local)
+	}
+	if !foreign.IsValid() || derefType(foreign.Type()) != this.foreign {
+		return fmt.Errorf("%w expected foreign %v", ErrMapperTypeMismatch, this.foreign)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping defines how a local type L maps a foreign type F without tagging L, eg for third-party structs whose
+// source can't be annotated. Each field is described with the same paths and options its `se` tag would hold, and
+// the mapping takes effect once registered:
+//
+//	err := se.NewMapping[vendor.Config, APIObject]().
+//	    Field("Name").To("Metadata.NameField").
+//	    Field("Replicas").To("Spec.Replicas").Options("required").
+//	    Register()
+type Mapping[L, F any] struct {
+	fields []*FieldMapping[L, F]
+}
+
+// FieldMapping describes how a field of a Mapping is mapped, see Mapping.Field.
+type FieldMapping[L, F any] struct {
+	mapping *Mapping[L, F]
+	name    string
+	paths   []string // main path followed by the alternative ones
+	types   []string // type matching entries, as held by the `types<>` option
+	opts    []string // any other tag option
+	skip    bool
+}
+
+// MappingSpec is the document form of a Mapping, so mappings can be loaded from configuration files and tweaked
+// without recompiling, see LoadMapping and NewMappingFromSpec. It carries both json and yaml tags:
+//
+//	fields:
+//	  - name: Name
+//	    to: Metadata.NameField||Metadata.Name
+//	  - name: Replicas
+//	    to: Spec.Replicas
+//	    options: [required]
+//	  - name: Internal
+//	    skip: true
+type MappingSpec struct {
+	Fields []FieldSpec `json:"fields" yaml:"fields"`
+}
+
+// FieldSpec describes how a field of a MappingSpec is mapped, see FieldMapping.
+type FieldSpec struct {
+	Name    string   `json:"name" yaml:"name"`                           // name of the local field
+	To      string   `json:"to,omitempty" yaml:"to,omitempty"`           // path, alternatives separated by "||"
+	Types   []string `json:"types,omitempty" yaml:"types,omitempty"`     // type matching entries
+	Options []string `json:"options,omitempty" yaml:"options,omitempty"` // any other tag option
+	Skip    bool     `json:"skip,omitempty" yaml:"skip,omitempty"`       // exclude the field from the mapping
+}
+
+// NewMapping returns an empty Mapping of the local type L into the foreign type F.
+func NewMapping[L, F any]() *Mapping[L, F] {
+	return &Mapping[L, F]{}
+}
+
+// NewMappingFromSpec returns the Mapping of the local type L into the foreign type F described by a MappingSpec,
+// eg decoded by the caller from another format, ready to be registered.
+func NewMappingFromSpec[L, F any](spec MappingSpec) *Mapping[L, F] {
+	mapping := NewMapping[L, F]()
+	for _, fieldSpec := range spec.Fields {
+		field := mapping.Field(fieldSpec.Name).Types(fieldSpec.Types...).Options(fieldSpec.Options...)
+		if fieldSpec.To != "" {
+			field.To(strings.Split(fieldSpec.To, FALLBACK_SPLIT)...)
+		}
+		if fieldSpec.Skip {
+			field.Skip()
+		}
+	}
+	return mapping
+}
+
+// LoadMapping reads a MappingSpec encoded as YAML or JSON and returns the Mapping of the local type L into the
+// foreign type F it describes, ready to be registered. Documents starting with `{` are decoded as JSON, any other
+// one as YAML. Unknown attributes are rejected, so typos don't go unnoticed.
+//
+// Parameters:
+//   - r: The reader the YAML or JSON document is read from
+//
+// Returns:
+//   - *Mapping[L, F]: The mapping described by the document
+//   - error: An error if the document can't be decoded
+func LoadMapping[L, F any](r io.Reader) (*Mapping[L, F], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w %w", ErrInvalidMappingSpec, err)
+	}
+
+	spec := MappingSpec{}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&spec)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w %w", ErrInvalidMappingSpec, err)
+	}
+	return NewMappingFromSpec[L, F](spec), nil
+}
+
+// Field returns the description of the local field of the given name, mapped to the foreign field of the same
+// name unless set otherwise. Fields left out of the mapping keep the tag they are declared with, if any.
+//
+// Parameters:
+//   - name: The name of a field declared by L
+//
+// Returns:
+//   - *FieldMapping[L, F]: The description of the field, to be completed with its paths and options
+func (this *Mapping[L, F]) Field(name string) *FieldMapping[L, F] {
+	for _, field := range this.fields {
+		if field.name == name {
+			return field
+		}
+	}
+	field := &FieldMapping[L, F]{mapping: this, name: name}
+	this.fields = append(this.fields, field)
+	return field
+}
+
+// Register validates the mapping by introspecting L and F with a private cache, and registers it so the fields it
+// describes are mapped as if they were tagged accordingly. Registering a pair of types again replaces the previous
+// mapping, and a mapping failing validation is not registered.
+//
+// Returns:
+//   - error: An error if a field is not declared by L, or any error found introspecting the types
+func (this *Mapping[L, F]) Register() error {
+	local, foreign := reflect.TypeFor[L](), reflect.TypeFor[F]()
+	tags := map[string]string{}
+	for _, field := range this.fields {
+		if stfield, ok := local.FieldByName(field.name); !ok || len(stfield.Index) > 1 {
+			return fmt.Errorf("%w %v in %v", ErrUnknownLocalField, field.name, local)
+		}
+		tags[field.name] = field.tag()
+	}
+
+	pair := [2]reflect.Type{local, foreign}
+	registry.Lock()
+	previous, replaced := registry.mappings[pair]
+	registry.mappings[pair] = tags
+	invalidateRepresentations()
+	registry.Unlock()
+
+	repr := StructRepr{}
+	err := repr.introspect(new(L), new(F), newOptions(WithPrivateCache()))
+	if err != nil {
+		registry.Lock()
+		if replaced {
+			registry.mappings[pair] = previous
+		} else {
+			delete(registry.mappings, pair)
+		}
+		invalidateRepresentations()
+		registry.Unlock()
+	}
+	return err
+}
+
+// To sets the path of the foreign field, relative to the path of the parent field. Several paths are tried in
+// order, just like alternative paths separated by "||" in tags.
+func (this *FieldMapping[L, F]) To(paths ...string) *FieldMapping[L, F] {
+	this.paths = paths
+	return this
+}
+
+// Types sets the type matching entries of the field, just like the `types<>` option, eg Types("Deployment",
+// "CronJob:Spec.JobTemplate.Spec.Replicas").
+func (this *FieldMapping[L, F]) Types(matches ...string) *FieldMapping[L, F] {
+	this.types = matches
+	return this
+}
+
+// Options sets any other tag option of the field, eg Options("required", "merge<overwrite>").
+func (this *FieldMapping[L, F]) Options(opts ...string) *FieldMapping[L, F] {
+	this.opts = opts
+	return this
+}
+
+// Skip excludes the field from the mapping, just like the "-" tag.
+func (this *FieldMapping[L, F]) Skip() *FieldMapping[L, F] {
+	this.skip = true
+	return this
+}
+
+// Field returns the description of another field of the mapping, see Mapping.Field.
+func (this *FieldMapping[L, F]) Field(name string) *FieldMapping[L, F] {
+	return this.mapping.Field(name)
+}
+
+// Register registers the mapping the field belongs to, see Mapping.Register.
+func (this *FieldMapping[L, F]) Register() error {
+	return this.mapping.Register()
+}
+
+// tag returns the `se` tag equivalent to the description of the field. The path defaults to the field name, or to
+// "+" when type matching entries set their own paths.
+func (this *FieldMapping[L, F]) tag() string {
+	if this.skip {
+		return SKIP_FIELD
+	}
+	path := strings.Join(this.paths, FALLBACK_SPLIT)
+	if path == "" {
+		path = SAME_NAME
+		for _, match := range this.types {
+			if strings.Contains(match, TYPES_PATH_SPLIT) {
+				path = MULTI_TYPE_NAME
+			}
+		}
+	}
+	parts := []string{path}
+	if len(this.types) > 0 {
+		parts = append(parts, fmt.Sprintf("types<%v>", strings.Join(this.types, TYPES_SPLIT)))
+	}
+	return strings.Join(append(parts, this.opts...), ",")
+}
+
+// RegisterTagSource registers a shadow struct S carrying the tags of the struct T, so generated or vendored local
+// structs can be mapped with declarative tags while left untouched. Every field of S must be declared by T under
+// the same name, and its tags are read in place of the ones of the field of T, which keeps its own tags when S
+// doesn't declare it. Registering a type again replaces its previous tag source.
+//
+// Returns:
+//   - error: An error if T or S is not a struct, or S declares a field T doesn't
+func RegisterTagSource[T, S any]() error {
+	tagged, shadow := reflect.TypeFor[T](), reflect.TypeFor[S]()
+	if tagged.Kind() != reflect.Struct || shadow.Kind() != reflect.Struct {
+		return ErrLocalTypeNotStruct
+	}
+	for id := range shadow.NumField() {
+		name := shadow.Field(id).Name
+		if field, ok := tagged.FieldByName(name); !ok || len(field.Index) > 1 {
+			return fmt.Errorf("%w %v in %v", ErrUnknownLocalField, name, tagged)
+		}
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.tagSources[tagged] = shadow
+	invalidateRepresentations()
+	return nil
+}
+
+// registeredTag returns the field with the tag registered for it, if any: the one set by the mapping of its local
+// type into the foreign type, under the given tag key so it's read just like a declared tag, or else the tags of
+// the field of the same name declared by the tag source of the local type. See Mapping and RegisterTagSource.
+func registeredTag(local, foreign reflect.Type, field reflect.StructField, key string) reflect.StructField {
+	registry.RLock()
+	defer registry.RUnlock()
+	if tag, ok := registry.mappings[[2]reflect.Type{local, foreign}][field.Name]; ok {
+		field.Tag = reflect.StructTag(fmt.Sprintf("%v:%q", key, tag))
+		return field
+	}
+	if shadow, ok := registry.tagSources[local]; ok {
+		if source, found := shadow.FieldByName(field.Name); found && len(source.Index) == 1 {
+			field.Tag = source.Tag
+		}
+	}
+	return field
+}
@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"database/sql"
+	"time"
+)
+
+// The database/sql nullable types are converted from and into the type they hold, or a pointer to it, on both
+// sides of the mapping, so ORM entities translate into API objects without glue code. Invalid values convert into
+// the zero value, or a nil pointer, while zero values are skipped as usual.
+func init() {
+	registerNullable(
+		func(v string) sql.NullString { return sql.NullString{String: v, Valid: true} },
+		func(n sql.NullString) (string, bool) { return n.String, n.Valid },
+	)
+	registerNullable(
+		func(v int64) sql.NullInt64 { return sql.NullInt64{Int64: v, Valid: true} },
+		func(n sql.NullInt64) (int64, bool) { return n.Int64, n.Valid },
+	)
+	registerNullable(
+		func(v int32) sql.NullInt32 { return sql.NullInt32{Int32: v, Valid: true} },
+		func(n sql.NullInt32) (int32, bool) { return n.Int32, n.Valid },
+	)
+	registerNullable(
+		func(v int16) sql.NullInt16 { return sql.NullInt16{Int16: v, Valid: true} },
+		func(n sql.NullInt16) (int16, bool) { return n.Int16, n.Valid },
+	)
+	registerNullable(
+		func(v byte) sql.NullByte { return sql.NullByte{Byte: v, Valid: true} },
+		func(n sql.NullByte) (byte, bool) { return n.Byte, n.Valid },
+	)
+	registerNullable(
+		func(v float64) sql.NullFloat64 { return sql.NullFloat64{Float64: v, Valid: true} },
+		func(n sql.NullFloat64) (float64, bool) { return n.Float64, n.Valid },
+	)
+	registerNullable(
+		func(v bool) sql.NullBool { return sql.NullBool{Bool: v, Valid: true} },
+		func(n sql.NullBool) (bool, bool) { return n.Bool, n.Valid },
+	)
+	registerNullable(
+		func(v time.Time) sql.NullTime { return sql.NullTime{Time: v, Valid: true} },
+		func(n sql.NullTime) (time.Time, bool) { return n.Time, n.Valid },
+	)
+}
+
+// registerNullable registers the converters between a nullable type and the type it holds, or a pointer to it,
+// with the nullable type on either side of the mapping.
+//
+// Parameters:
+//   - wrap: The function wrapping a value into a valid nullable value
+//   - unwrap: The function returning the value held by a nullable value, and whether it is valid
+func registerNullable[V, N any](wrap func(V) N, unwrap func(N) (V, bool)) {
+	toNullable := func(value V) (N, error) { return wrap(value), nil }
+	fromNullable := func(nullable N) (V, error) {
+		value, _ := unwrap(nullable) // invalid values hold the zero value
+		return value, nil
+	}
+	pointerToNullable := func(value *V) (N, error) {
+		if value == nil {
+			var null N
+			return null, nil
+		}
+		return wrap(*value), nil
+	}
+	pointerFromNullable := func(nullable N) (*V, error) {
+		if value, valid := unwrap(nullable); valid {
+			return &value, nil
+		}
+		return nil, nil
+	}
+
+	RegisterConverter(toNullable, fromNullable)
+	RegisterConverter(fromNullable, toNullable)
+	RegisterConverter(pointerToNullable, pointerFromNullable)
+	RegisterConverter(pointerFromNullable, pointerToNullable)
+}
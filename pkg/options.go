@@ -0,0 +1,435 @@
+package pkg
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Logger is the minimal logging interface used to report internal decisions (cache misses, skipped fields,
+// allocations) at debug level. *slog.Logger satisfies it, so the host application's logging stack can be
+// plugged in directly.
+type Logger interface {
+	Debug(msg string, args ...any)
+}
+
+// options holds the configuration shared by a Codec with the encoders, decoders and introspection it performs.
+type options struct {
+	logger         Logger                // nil unless set, checked before building the arguments of every message
+	copyZero       map[reflect.Kind]bool // kinds whose zero value is copied instead of skipped
+	mapMerge       MapMergeMode          // how entries are written into destination maps holding entries
+	preserve       bool                  // keep local slice elements the mapping doesn't touch when decoding
+	autoMap        bool                  // map untagged exported fields to the foreign fields of the same name
+	numeric        bool                  // convert between numeric fields of different types
+	strict         bool                  // fail with a StrictError when any field is skipped
+	parallelism    int                   // goroutines the slice helpers split the elements among
+	sliceWorkers   int                   // goroutines the elements of large slice fields are split among
+	sliceThreshold int                   // elements a slice field holds at least to be split among goroutines
+	root           []string              // foreign path every tag path is resolved under
+	only           map[string]bool       // local fields to map, every one when empty
+	exclude        map[string]bool       // local fields left out
+	tagKey         string                // key of the struct tags holding the mappings
+	protoNames     bool                  // resolve path segments by the protobuf schema names of generated structs
+	cache          *typeCache            // where introspected representations are kept, shared by default
+	observer       IntrospectionObserver // notified of every introspection of types missing from the cache
+	skipHandler    SkipHandler           // notified of every field that could not be transferred
+	maxDepth       int                   // nesting levels and path segments mappings may reach, unlimited when zero
+	allErrors      bool                  // report every invalid field found introspecting, not only the first one
+	ignoreMissing  bool                  // dismiss every field whose path doesn't exist, as if tagged `optional`
+}
+
+// MapMergeMode defines how the entries of a map are written into a destination map that already holds entries.
+type MapMergeMode string
+
+const (
+	MapReplace        MapMergeMode = "replace"   // the destination map is replaced, dropping its entries (default)
+	MapMergeOverwrite MapMergeMode = "overwrite" // entries are merged, overwriting the destination on key conflicts
+	MapMergeKeep      MapMergeMode = "keep"      // entries are merged, keeping the destination on key conflicts
+)
+
+func (this MapMergeMode) valid() bool {
+	return this == MapReplace || this == MapMergeOverwrite || this == MapMergeKeep
+}
+
+// Option configures the behavior of a Codec.
+type Option func(*options)
+
+// WithLogger sets the logger used to report internal decisions at debug level.
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
+
+// WithCopyZero makes the zero values of the provided kinds be considered legitimate values to copy,
+// instead of being skipped as empty. E.g. WithCopyZero(reflect.Bool, reflect.Int) transfers `false` and `0`
+// while empty strings keep being skipped.
+func WithCopyZero(kinds ...reflect.Kind) Option {
+	return func(o *options) {
+		for _, kind := range kinds {
+			o.copyZero[kind] = true
+		}
+	}
+}
+
+// WithMapMerge sets how map entries are written into destination maps that already hold entries, for every map
+// field not setting its own mode with the `merge<>` tag option. Invalid modes are ignored.
+func WithMapMerge(mode MapMergeMode) Option {
+	return func(o *options) {
+		if mode.valid() {
+			o.mapMerge = mode
+		}
+	}
+}
+
+// WithPreservedSlices makes decoding into local slices keep the elements already held that the mapping doesn't
+// touch, decoding the foreign elements over the existing ones instead of allocating a new slice, for every slice
+// field. Fields can opt in individually with the `preserve` tag option.
+func WithPreservedSlices() Option {
+	return func(o *options) {
+		o.preserve = true
+	}
+}
+
+// WithAutoMap makes untagged exported fields map to the foreign field of the same name, as if tagged with `se:"="`,
+// as long as the foreign struct has such a field and its type is compatible, going deep through nested structs.
+// Fields without a compatible foreign counterpart are left out, while fields tagged with `se:"-"` are never mapped.
+func WithAutoMap() Option {
+	return func(o *options) {
+		o.autoMap = true
+	}
+}
+
+// WithNumericConversion makes fields holding a single number map foreign numeric fields of another type, eg an
+// `int` local field and an `int64` foreign field. Values are widened as needed, while narrowing them fails with an
+// error when they don't fit the destination type, or lose their fractional part when converted into an integer.
+func WithNumericConversion() Option {
+	return func(o *options) {
+		o.numeric = true
+	}
+}
+
+// WithStrict makes the codec fail with a *StrictError listing every tagged field that could not be transferred,
+// instead of silently skipping them, just like UnmarshalStrict and MarshalStrict do. Fields that could be
+// transferred are still mapped.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithParallelism makes MarshalSlice and UnmarshalSlice split the elements among the given number of goroutines,
+// which pays off for large batches of big structs, and Codec.IntrospectAll split the pairs of types the same way.
+// Elements are mapped sequentially by default.
+func WithParallelism(workers int) Option {
+	return func(o *options) {
+		o.parallelism = workers
+	}
+}
+
+// WithParallelSlices makes encoders and decoders split the elements of slice fields holding at least threshold
+// elements among up to the given number of goroutines, as mapping thousands of nested structs is CPU-bound and every
+// element is mapped on its own. Slices nested in the elements of a split slice are mapped sequentially, so no more
+// than the given number of goroutines map a slice at once. Lifecycle hooks of the elements, and the logger, may be
+// called concurrently. Elements are mapped sequentially by default.
+func WithParallelSlices(workers, threshold int) Option {
+	return func(o *options) {
+		o.sliceWorkers = workers
+		o.sliceThreshold = threshold
+	}
+}
+
+// WithMaxDepth caps how deep mappings may reach, as a guard against deeply nested or recursive types: introspecting
+// a pair of types fails with ErrMaxDepth when a foreign path has more segments than the given depth, or when local
+// structs are nested deeper than it. Values are mapped walking the introspected representation, so they're never
+// walked deeper either. Depth is unlimited by default.
+func WithMaxDepth(depth int) Option {
+	return func(o *options) {
+		o.maxDepth = depth
+	}
+}
+
+// WithAllErrors makes introspection report every invalid tag or path of the local types at once, instead of
+// stopping at the first one, so they can be fixed in a single pass. The failures are joined with errors.Join, each
+// of them a *FieldError identifying the field.
+func WithAllErrors() Option {
+	return func(o *options) {
+		o.allErrors = true
+	}
+}
+
+// WithIgnoreMissingFields makes introspection dismiss the fields whose path doesn't exist in the foreign type, as if
+// every tag set the `optional` option, instead of failing with ErrForeignTypeMissingField. Useful when the same local
+// struct maps several loosely related versions of a foreign type.
+func WithIgnoreMissingFields() Option {
+	return func(o *options) {
+		o.ignoreMissing = true
+	}
+}
+
+// WithRoot mounts the mapping under a sub-path of the foreign struct, eg WithRoot("Spec.Template"), resolving every
+// tag path as if prefixed by it. The same annotated local struct can then be mapped into a sub-tree of a bigger
+// foreign object without changing its tags. An empty path maps from the foreign struct itself, the default.
+func WithRoot(path string) Option {
+	return func(o *options) {
+		o.root = nil
+		if path != "" {
+			o.root = strings.Split(path, ".")
+		}
+	}
+}
+
+// WithOnly restricts the mapping to the provided local fields, so a subset of the tagged fields can be encoded or
+// decoded, eg for flows updating only mutable fields, without defining new structs. Nested fields are named by their
+// local path, eg "Nested.Direction", selecting the parents leading to them, while naming a struct field selects
+// every field nested in it. Indexes and keys of slices and maps are left out of the paths. Mapping values whose local
+// type doesn't map every named field fails with ErrUnknownLocalField.
+func WithOnly(fields ...string) Option {
+	return func(o *options) {
+		for _, field := range fields {
+			o.only[field] = true
+		}
+	}
+}
+
+// WithExclude leaves the provided local fields, and every field nested in them, out of the mapping. Fields are
+// named like in WithOnly, unknown ones failing just the same, and excluding a field takes precedence over selecting
+// it.
+func WithExclude(fields ...string) Option {
+	return func(o *options) {
+		for _, field := range fields {
+			o.exclude[field] = true
+		}
+	}
+}
+
+// WithTagKey sets the key of the struct tags the codec reads the mappings from, `se` by default, so a local struct
+// can carry several independent mapping schemes, eg `se_v1:"Spec.Name" se_v2:"Metadata.Name"`, each one selected
+// by a codec. An empty key is ignored.
+func WithTagKey(key string) Option {
+	return func(o *options) {
+		if key != "" {
+			o.tagKey = key
+		}
+	}
+}
+
+// WithProtoNames makes path segments also match the fields of protoc-gen-go generated structs by their proto schema
+// name or protojson name, read from the `protobuf` struct tags, eg `se:"spec.min_replicas"` or `se:"spec.minReplicas"`
+// for the Go field `Spec.MinReplicas`. Go field names keep matching, and take precedence.
+func WithProtoNames() Option {
+	return func(o *options) {
+		o.protoNames = true
+	}
+}
+
+// WithPrivateCache makes the codec keep the representations it introspects in a cache of its own instead of the
+// one shared by the package-level functions and every other codec, so independent components of a process don't
+// interfere with each other's mappings. The package-level ClearTypeCache leaves private caches untouched, they
+// are emptied with Codec.ClearTypeCache.
+func WithPrivateCache() Option {
+	return func(o *options) {
+		o.cache = newTypeCache()
+	}
+}
+
+// IntrospectionObserver is notified of the introspection of a pair of local and foreign types, see
+// WithIntrospectionObserver.
+type IntrospectionObserver func(local, foreign reflect.Type, elapsed time.Duration)
+
+// WithIntrospectionObserver sets a function notified every time the codec introspects a pair of types missing from
+// its cache, along with the time it took, eg to feed a metrics system. It's called synchronously, so it must be
+// cheap. See CacheStats for the totals.
+func WithIntrospectionObserver(observer IntrospectionObserver) Option {
+	return func(o *options) {
+		o.observer = observer
+	}
+}
+
+// SkipHandler is notified of a field that could not be transferred, see WithSkipHandler.
+type SkipHandler func(skip FieldSkip)
+
+// WithSkipHandler sets a function notified of every tagged field that could not be transferred, along with the
+// reason, as soon as it's skipped: fields dismissed by their type matching option or optional path, nil pointers and
+// empty slices found in the source path, and zero values. It's notified of the fields a mapping report would list,
+// so silently skipped fields become observable in development. It's called synchronously, and concurrently for the
+// elements of slices split with WithParallelSlices.
+func WithSkipHandler(handler SkipHandler) Option {
+	return func(o *options) {
+		o.skipHandler = handler
+	}
+}
+
+// representationVariant returns a suffix for the keys of the cached local representations, keeping apart the
+// representations of the same types introspected with options that change which fields are mapped.
+func (this *options) representationVariant() string {
+	variant := ""
+	if this.autoMap {
+		variant += "~auto"
+	}
+	if this.numeric {
+		variant += "~numeric"
+	}
+	if this.tagKey != FIELD_TAG_KEY {
+		variant += "~tag:" + this.tagKey
+	}
+	if this.protoNames {
+		variant += "~proto"
+	}
+	if len(this.root) > 0 {
+		variant += "~root:" + strings.Join(this.root, ".")
+	}
+	if this.ignoreMissing {
+		variant += "~optional"
+	}
+	if this.maxDepth > 0 {
+		variant += "~depth:" + strconv.Itoa(this.maxDepth)
+	}
+	return variant
+}
+
+// adaptable reports whether values can be mapped by the adapter registered for their types, which implements the
+// default mapping only: options changing which fields are mapped, or how, or observing the skipped ones, rule
+// adapters out.
+func (this *options) adaptable() bool {
+	return this.representationVariant() == "" && !this.strict && !this.preserve && this.mapMerge == MapReplace &&
+		len(this.copyZero) == 0 && len(this.only) == 0 && len(this.exclude) == 0 && this.skipHandler == nil
+}
+
+// sliceParallelism returns the number of goroutines the elements of a slice field of the given length are split
+// among, one when they're mapped sequentially.
+func (this *options) sliceParallelism(length int) int {
+	if this.sliceWorkers <= 1 || length < max(this.sliceThreshold, 2) {
+		return 1
+	}
+	return min(this.sliceWorkers, length)
+}
+
+// exceedsDepth reports whether a nesting level, or a number of path segments, exceeds the depth set with
+// WithMaxDepth.
+func (this *options) exceedsDepth(depth int) bool {
+	return this.maxDepth > 0 && depth > this.maxDepth
+}
+
+// notifySkips notifies the handler set with WithSkipHandler of skipped fields, if any.
+func (this *options) notifySkips(skips ...FieldSkip) {
+	if this.skipHandler == nil {
+		return
+	}
+	for _, skip := range skips {
+		this.skipHandler(skip)
+	}
+}
+
+// strictError returns a *StrictError listing the skipped fields when strict mode was requested with WithStrict.
+func (this *options) strictError(skips []FieldSkip) error {
+	if this.strict && len(skips) > 0 {
+		return &StrictError{Skips: skips}
+	}
+	return nil
+}
+
+var fieldIndexRegEx = regexp.MustCompile(`\[[^\]]*\]`)
+
+// selected reports whether a local field, named by its path as reported in skips, has to be mapped according to
+// the fields selected with WithOnly and WithExclude.
+func (this *options) selected(field string) bool {
+	if len(this.only) == 0 && len(this.exclude) == 0 {
+		return true
+	}
+	path := fieldIndexRegEx.ReplaceAllString(field, "")
+	if withParents(path, this.exclude) {
+		return false
+	}
+	if len(this.only) == 0 || withParents(path, this.only) {
+		return true
+	}
+	for selected := range this.only {
+		if strings.HasPrefix(selected, path+".") {
+			return true // the field leads to a selected one
+		}
+	}
+	return false
+}
+
+// validateSelection checks that every field named with WithOnly and WithExclude is a field of the representation
+// of the local type, so misspelled names aren't silently ignored.
+//
+// Parameters:
+//   - local: The local type, named by the error
+//   - repr: The representation of the local type
+//
+// Returns:
+//   - error: An error wrapping ErrUnknownLocalField naming the first unknown field, if any
+func (this *options) validateSelection(local reflect.Type, repr StructRepr) error {
+	if len(this.only) == 0 && len(this.exclude) == 0 {
+		return nil
+	}
+	reprs := this.cache.representations()
+	for _, set := range []map[string]bool{this.only, this.exclude} {
+		for _, field := range slices.Sorted(maps.Keys(set)) {
+			if !reprs.hasField(repr, fieldIndexRegEx.ReplaceAllString(field, "")) {
+				return fmt.Errorf("%w %v in %v", ErrUnknownLocalField, field, local)
+			}
+		}
+	}
+	return nil
+}
+
+// withParents reports whether a field path, or the path of any of its parents, is in the set.
+func withParents(path string, set map[string]bool) bool {
+	for {
+		if set[path] {
+			return true
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			return false
+		}
+		path = path[:i]
+	}
+}
+
+// preserveSlice resolves whether the existing elements of a local slice field are kept when decoding.
+func (this *options) preserveSlice(tag FieldTag) bool {
+	return this.preserve || tag.Opts.Preserve
+}
+
+// mapMergeMode resolves the merge mode of a map field, the one set on its tag taking precedence.
+func (this *options) mapMergeMode(tag FieldTag) MapMergeMode {
+	if tag.Opts.MapMerge != "" {
+		return tag.Opts.MapMerge
+	}
+	return this.mapMerge
+}
+
+// isEmpty checks whether a value should be considered empty, and therefore skipped, according to the
+// zero-value semantics configured for its kind.
+func (this *options) isEmpty(value reflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+	return value.IsZero() && !this.copyZero[value.Kind()]
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		copyZero: map[reflect.Kind]bool{},
+		only:     map[string]bool{},
+		exclude:  map[string]bool{},
+		mapMerge: MapReplace,
+		tagKey:   FIELD_TAG_KEY,
+		cache:    sharedCache,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
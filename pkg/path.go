@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// GetPath reads the value found at an `se`-style path of a struct, eg `Config.SomeList[0].Config.Direction`, walking
+// it just like Unmarshal walks foreign structs, for one-off dynamic access that doesn't need a whole mapping.
+//
+// Parameters:
+//   - from: The struct, or pointer to a non-nil struct, to read from
+//   - path: The path of the value, segments separated by dots
+//
+// Returns:
+//   - any: The value found at the path, nil when it can't be reached
+//   - bool: False when a nil pointer or an empty slice was found along the path
+//   - error: ErrForeignTypeNotStruct when from isn't a struct, a *PathError when the path leads to no field, or
+//     ErrWildcardPath when the path selects every element of a slice
+func GetPath(from any, path string) (any, bool, error) {
+	value := reflect.ValueOf(from)
+	if value.Kind() == reflect.Pointer && value.IsNil() {
+		return nil, false, ErrForeignTypeNotStruct
+	}
+	target, err := resolveValuePath(value, path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	found, skip := resolveForeignValue(target.IndexPath, target.Elements, value)
+	if last := target.Elements[len(target.Elements)-1]; skip == "" && len(last) > 0 {
+		found, skip = descendIntoForeignArrayField(found, last) // the path ends selecting an element
+	}
+	if skip != "" {
+		return nil, false, nil
+	}
+	return found.Interface(), true, nil
+}
+
+// SetPath writes a value at an `se`-style path of a struct, eg `Config.SomeList[0].Config.Direction`, walking it
+// just like Marshal walks foreign structs: nil pointers and empty slices found along the path are allocated, and
+// the value is converted as Marshal converts the values of fields, eg between slices and arrays. A nil value sets
+// the zero value.
+//
+// Parameters:
+//   - into: A pointer to a non-nil struct to write into
+//   - path: The path of the value, segments separated by dots
+//   - value: The value to write
+//
+// Returns:
+//   - error: ErrUnmarshalDestType when into isn't a pointer, ErrForeignTypeNotStruct when it doesn't point to a
+//     struct, a *PathError when the path leads to no field, ErrWildcardPath when the path selects every
+//     element of a slice, or ErrPathValueType when the value can't be assigned to the field
+func SetPath(into any, path string, value any) error {
+	dst := reflect.ValueOf(into)
+	if dst.Kind() != reflect.Pointer || dst.IsNil() {
+		return ErrUnmarshalDestType
+	}
+	target, err := resolveValuePath(dst, path)
+	if err != nil {
+		return err
+	}
+
+	last := target.Elements[len(target.Elements)-1]
+	fieldType := target.Type
+	for len(last) > 0 && (fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array) {
+		fieldType = fieldType.Elem() // the path ends selecting an element
+	}
+	data := reflect.ValueOf(value)
+	if value != nil && !assignable(fieldType, data.Type()) {
+		return fmt.Errorf("%w %v into %v of type %v", ErrPathValueType, data.Type(), path, fieldType)
+	}
+
+	encoder := &StructEncoder{opts: newOptions()}
+	field := encoder.resolveForeignField(target.IndexPath, target.Elements, dst)
+	if len(last) > 0 {
+		field = encoder.descendIntoLocalArrayField(field, last)
+	}
+	if value == nil {
+		field.SetZero()
+		return nil
+	}
+	return assignValue(field, data)
+}
+
+// resolveValuePath resolves a path against the type of a struct value, or pointer to it, rejecting the paths that
+// don't lead to a single value.
+func resolveValuePath(value reflect.Value, path string) (TargetField, error) {
+	if !value.IsValid() {
+		return TargetField{}, ErrForeignTypeNotStruct
+	}
+	if strings.Contains(path, "["+INDEX_ALL+"]") {
+		return TargetField{}, fmt.Errorf("%w %v", ErrWildcardPath, path)
+	}
+	target, err := ResolvePath(value.Type(), path)
+	return target, newPathError(path, err)
+}
+
+// assignable reports whether assignValue can set a value of type data into a destination of type dst.
+func assignable(dst, data reflect.Type) bool {
+	switch {
+	case data.AssignableTo(dst):
+		return true
+	case dst.Kind() == reflect.Array && data.Kind() == reflect.Slice,
+		dst.Kind() == reflect.Slice && data.Kind() == reflect.Array:
+		return data.Elem() == dst.Elem()
+	case dst.Kind() == reflect.Pointer:
+		return data == dst.Elem()
+	}
+	return isNumeric(data) && isNumeric(dst)
+}
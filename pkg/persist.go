@@ -0,0 +1,192 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// cacheExportVersion is the version of the format written by ExportCache, bumped whenever the cached structures
+// change so stale exports are rejected instead of misread.
+const cacheExportVersion = 1
+
+// cacheExport is the form ExportCache writes the introspection cache in: the representations of the local types
+// and the foreign fields they reference, keyed by the identity of the types involved.
+type cacheExport struct {
+	Version  int
+	Locals   map[string]structReprAlias
+	Foreigns map[string]TargetField
+}
+
+// ExportCache writes the representations held by the shared introspection cache, so a process can warm its cache
+// with ImportCache instead of introspecting the same types again, eg from a file generated in CI.
+//
+// Parameters:
+//   - w: The writer the cache is written to, as JSON
+//
+// Returns:
+//   - error: Any error found encoding or writing the cache
+func ExportCache(w io.Writer) error {
+	return sharedCache.export(w)
+}
+
+// ImportCache reads representations written by ExportCache into the shared introspection cache. Imported
+// representations are only used for types whose layout matches the exported one, any other type keeps being
+// introspected. Representations depend on the registered functions, converters and mappings too, so the exporting
+// process is expected to register the same ones.
+//
+// Parameters:
+//   - r: The reader the cache is read from
+//
+// Returns:
+//   - error: ErrCacheVersion when the cache was written by an incompatible version, or any decoding error
+func ImportCache(r io.Reader) error {
+	return sharedCache.load(r)
+}
+
+// ExportCache writes the representations held by the cache of the codec, see the package-level ExportCache.
+func (this *Codec) ExportCache(w io.Writer) error {
+	return this.opts.cache.export(w)
+}
+
+// ImportCache reads representations into the cache of the codec, see the package-level ImportCache.
+func (this *Codec) ImportCache(r io.Reader) error {
+	return this.opts.cache.load(r)
+}
+
+// export writes the representations of the cache, along with the imported ones not used yet.
+func (this *typeCache) export(w io.Writer) error {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	exported := cacheExport{
+		Version:  cacheExportVersion,
+		Locals:   map[string]structReprAlias{},
+		Foreigns: map[string]TargetField{},
+	}
+	for key, repr := range this.imported.locals {
+		exported.Locals[key] = structReprAlias(repr)
+	}
+	for key, repr := range this.locals {
+		exported.Locals[key] = structReprAlias(repr)
+	}
+	maps.Copy(exported.Foreigns, this.imported.foreigns)
+	maps.Copy(exported.Foreigns, this.foreigns)
+	return json.NewEncoder(w).Encode(exported)
+}
+
+// load reads exported representations, keeping them apart until the types they describe are requested, as the
+// types can't be restored from their names.
+func (this *typeCache) load(r io.Reader) error {
+	imported := cacheExport{}
+	if err := json.NewDecoder(r).Decode(&imported); err != nil {
+		return err
+	}
+	if imported.Version != cacheExportVersion {
+		return fmt.Errorf("%w %v, expected %v", ErrCacheVersion, imported.Version, cacheExportVersion)
+	}
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	for key, repr := range imported.Locals {
+		this.imported.locals[key] = StructRepr(repr)
+	}
+	maps.Copy(this.imported.foreigns, imported.Foreigns)
+	return nil
+}
+
+// restore moves the imported representation of a pair of types into the cache, along with the representations of
+// its children and the foreign fields they reference, setting the types their fields hold. It's called while
+// introspecting, holding the lock of the cache, see update.
+//
+// Parameters:
+//   - key: The key of the representation
+//   - local: The local struct type the representation describes
+//   - foreign: The foreign struct type the representation maps into
+//
+// Returns:
+//   - bool: Whether the representation, and everything it references, was imported
+func (this *typeCache) restore(key string, local, foreign reflect.Type) bool {
+	if len(this.imported.locals) == 0 {
+		return false
+	}
+	restored := representations{locals: map[string]StructRepr{}, foreigns: map[string]TargetField{}}
+	if !this.imported.hydrate(key, local, foreign, restored) {
+		return false
+	}
+	maps.Copy(this.locals, restored.locals)
+	maps.Copy(this.foreigns, restored.foreigns)
+	return true
+}
+
+// hydrate copies into dst the representation of the given key, and the ones it references, setting the types of
+// their fields by walking the local and foreign types with the indexes of the fields.
+func (this representations) hydrate(key string, local, foreign reflect.Type, dst representations) bool {
+	repr, ok := this.locals[key]
+	if !ok || local.Kind() != reflect.Struct {
+		return false
+	}
+	repr.foreignType = foreign
+	repr.Fields = slices.Clone(repr.Fields)
+	repr.Unmatched = slices.Clone(repr.Unmatched)
+	dst.locals[key] = repr
+
+	for _, fields := range [][]SourceField{repr.Fields, repr.Unmatched} {
+		for i, field := range fields {
+			if field.Id >= local.NumField() {
+				return false
+			}
+			fields[i].Type = local.Field(field.Id).Type
+			for _, ref := range append([]string{field.TargetRef, field.ConditionRef}, field.JoinRefs...) {
+				if ref == "" {
+					continue
+				}
+				target, ok := this.foreigns[ref]
+				if ok {
+					target, ok = hydrateTarget(foreign, target)
+				}
+				if !ok {
+					return false
+				}
+				dst.foreigns[ref] = target
+			}
+			if _, hydrated := dst.locals[field.ChildRef]; field.ChildRef == "" || hydrated {
+				continue
+			}
+			child := fields[i].Type
+			if child.Kind() != reflect.Struct {
+				child = child.Elem() // pointers, slices and maps of structs
+			}
+			if !this.hydrate(field.ChildRef, child, foreign, dst) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// hydrateTarget sets the type of an imported foreign field, walking the foreign type with its index path just like
+// resolveTargetField walks it with the field names.
+func hydrateTarget(foreign reflect.Type, target TargetField) (TargetField, bool) {
+	current := foreign
+	for depth, id := range target.IndexPath {
+		if current.Kind() != reflect.Struct || id < 0 || id >= current.NumField() {
+			return target, false // asserted concrete types are registered by each process, in any order
+		}
+		field := current.Field(id)
+		if depth == len(target.IndexPath)-1 {
+			target.Type = field.Type
+			return target, true
+		}
+		fieldType := field.Type
+		if slices.Contains([]reflect.Kind{reflect.Map, reflect.Array, reflect.Slice}, fieldType.Kind()) {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		current = unwrapCollections(fieldType)
+	}
+	return target, false
+}
@@ -0,0 +1,73 @@
+package pkg
+
+// fieldPlan holds the representations referenced by a field, resolved once when the representation holding the
+// field is cached, so encoders and decoders follow them directly instead of looking them up by key for every field
+// of every value they map.
+type fieldPlan struct {
+	child     *StructRepr   // representation of the nested struct, nil when there is none
+	target    TargetField   // foreign field referenced by TargetRef
+	condition TargetField   // foreign field referenced by ConditionRef
+	joins     []TargetField // foreign field referenced by each one of JoinRefs
+}
+
+// link resolves the references of the fields of a representation, and of the representations of its children,
+// into plans. Fields are shared by every copy of a representation, so linking the cached one links them all.
+// Fields already linked are left as they are.
+func (this representations) link(repr StructRepr) {
+	for _, fields := range [][]SourceField{repr.Fields, repr.Unmatched} {
+		for i, field := range fields {
+			if field.plan != nil {
+				continue
+			}
+			plan := &fieldPlan{
+				target:    this.foreigns[field.TargetRef],
+				condition: this.foreigns[field.ConditionRef],
+				joins:     make([]TargetField, len(field.JoinRefs)),
+			}
+			for j, ref := range field.JoinRefs {
+				plan.joins[j] = this.foreigns[ref]
+			}
+			if child, ok := this.locals[field.ChildRef]; ok {
+				this.link(child)
+				plan.child = &child
+			}
+			fields[i].plan = plan
+		}
+	}
+}
+
+// child returns the representation of the nested struct of a field, if any.
+func (this representations) child(field SourceField) (StructRepr, bool) {
+	if field.plan == nil {
+		child, ok := this.locals[field.ChildRef]
+		return child, ok
+	}
+	if field.plan.child == nil {
+		return StructRepr{}, false
+	}
+	return *field.plan.child, true
+}
+
+// target returns the foreign field a field maps.
+func (this representations) target(field SourceField) TargetField {
+	if field.plan == nil {
+		return this.foreigns[field.TargetRef]
+	}
+	return field.plan.target
+}
+
+// condition returns the foreign field compared by the `when<>` option of a field.
+func (this representations) condition(field SourceField) TargetField {
+	if field.plan == nil {
+		return this.foreigns[field.ConditionRef]
+	}
+	return field.plan.condition
+}
+
+// joined returns the foreign field of the part i of the `join<>` option of a field.
+func (this representations) joined(field SourceField, i int) TargetField {
+	if field.plan == nil {
+		return this.foreigns[field.JoinRefs[i]]
+	}
+	return field.plan.joins[i]
+}
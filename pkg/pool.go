@@ -0,0 +1,52 @@
+package pkg
+
+import "sync"
+
+// encoderPool and decoderPool keep the encoders and decoders released by the calls mapping a single value, so
+// services mapping millions of values reuse them instead of allocating new ones.
+var (
+	encoderPool = sync.Pool{New: func() any { return &StructEncoder{} }}
+	decoderPool = sync.Pool{New: func() any { return &StructDecoder{} }}
+)
+
+// acquireEncoder takes an encoder from the pool, set up with the given options.
+//
+// Parameters:
+//   - opts: The options of the codec the encoder maps values for
+//   - recordSkips: Whether the encoder keeps track of the fields that could not be transferred
+//
+// Returns:
+//   - *StructEncoder: An encoder to initialize, to be released once done
+func acquireEncoder(opts *options, recordSkips bool) *StructEncoder {
+	encoder := encoderPool.Get().(*StructEncoder)
+	encoder.opts = opts
+	encoder.recordSkips = recordSkips
+	return encoder
+}
+
+// release resets the encoder and puts it back into the pool. The encoder must not be used afterwards.
+func (this *StructEncoder) release() {
+	*this = StructEncoder{}
+	encoderPool.Put(this)
+}
+
+// acquireDecoder takes a decoder from the pool, set up with the given options.
+//
+// Parameters:
+//   - opts: The options of the codec the decoder maps values for
+//   - recordSkips: Whether the decoder keeps track of the fields that could not be transferred
+//
+// Returns:
+//   - *StructDecoder: A decoder to initialize, to be released once done
+func acquireDecoder(opts *options, recordSkips bool) *StructDecoder {
+	decoder := decoderPool.Get().(*StructDecoder)
+	decoder.opts = opts
+	decoder.recordSkips = recordSkips
+	return decoder
+}
+
+// release resets the decoder and puts it back into the pool. The decoder must not be used afterwards.
+func (this *StructDecoder) release() {
+	*this = StructDecoder{}
+	decoderPool.Put(this)
+}
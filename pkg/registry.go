@@ -0,0 +1,587 @@
+package pkg
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ComputeFunc derives the value of a local field from the foreign value found at the field path, the foreign
+// struct holding the field when the path is `+`. It's referenced from tags with the `compute<name>` option, see
+// RegisterFunc.
+type ComputeFunc func(foreign any) (any, error)
+
+// InverseFunc writes the value of a local field setting the `compute<name>` option back into the foreign value
+// found at the field path, received as a pointer, see RegisterInverseFunc.
+type InverseFunc func(local any, foreign any) error
+
+// converter converts values between a local and a foreign type, see RegisterNamedConverter.
+type converter struct {
+	local   reflect.Type
+	foreign reflect.Type
+	encode  func(any) (any, error)
+	decode  func(any) (any, error)
+}
+
+var registry = struct {
+	sync.RWMutex
+	funcs      map[string]ComputeFunc
+	inverses   map[string]InverseFunc
+	converters map[string]converter
+	pairs      map[[2]reflect.Type]converter
+	leaves     map[reflect.Type]bool
+	hooks      map[[2]reflect.Type]typeHooks
+	mappings   map[[2]reflect.Type]map[string]string // tag of each field described by a Mapping
+	tagSources map[reflect.Type]reflect.Type         // shadow struct carrying the tags of a struct
+	adapters   map[[2]reflect.Type]adapter           // functions mapping a pair of types without reflection
+	copiers    map[reflect.Type]func(from, into any) // functions copying values of a struct type
+	concretes  []reflect.Type                        // types interface fields are asserted to hold, see as<>
+	generation atomic.Uint64                         // registrations changing how types are introspected so far
+}{
+	funcs:      map[string]ComputeFunc{},
+	inverses:   map[string]InverseFunc{},
+	converters: map[string]converter{},
+	pairs:      map[[2]reflect.Type]converter{},
+	leaves:     map[reflect.Type]bool{reflect.TypeFor[time.Time](): true},
+	hooks:      map[[2]reflect.Type]typeHooks{},
+	mappings:   map[[2]reflect.Type]map[string]string{},
+	tagSources: map[reflect.Type]reflect.Type{},
+	adapters:   map[[2]reflect.Type]adapter{},
+	copiers:    map[reflect.Type]func(from, into any){},
+	concretes:  []reflect.Type{},
+}
+
+// RegisterFunc registers a function under a name, so fields tagged with `compute<name>` are set by Unmarshal to
+// the value it derives from the foreign struct. Registering a name again replaces the previous function.
+//
+// Parameters:
+//   - name: The name the function is referenced by in tags
+//   - fn: The function deriving the local value
+func RegisterFunc(name string, fn ComputeFunc) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.funcs[name] = fn
+	invalidateRepresentations()
+}
+
+// RegisterInverseFunc registers the reverse of the function registered under the same name with RegisterFunc, so
+// Marshal writes fields tagged with `compute<name>` back into the foreign struct. Fields computed by a function
+// without an inverse are only populated by Unmarshal.
+//
+// Parameters:
+//   - name: The name the function is referenced by in tags
+//   - fn: The function writing the local value into the foreign one
+func RegisterInverseFunc(name string, fn InverseFunc) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.inverses[name] = fn
+}
+
+// RegisterNamedConverter registers a pair of functions under a name, so fields tagged with `convert<name>` are
+// converted instead of copied: Marshal writes the result of encode into the foreign field, while Unmarshal sets
+// the local field to the result of decode. Fields setting the option must be of type L, and map a foreign field of
+// type F. Registering a name again replaces the previous converter.
+//
+// Parameters:
+//   - name: The name the converter is referenced by in tags
+//   - encode: The function converting local values into foreign values
+//   - decode: The function converting foreign values into local values
+func RegisterNamedConverter[L, F any](name string, encode func(L) (F, error), decode func(F) (L, error)) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.converters[name] = newConverter(encode, decode)
+	invalidateRepresentations()
+}
+
+// RegisterConverter registers a pair of functions converting values between a local type L and a foreign type F,
+// so every field of type L mapping a foreign field of type F is converted instead of copied, even though their
+// types differ: Marshal writes the result of encode into the foreign field, while Unmarshal sets the local field
+// to the result of decode. Registering a pair of types again replaces the previous converter.
+//
+// Parameters:
+//   - encode: The function converting local values into foreign values
+//   - decode: The function converting foreign values into local values
+func RegisterConverter[L, F any](encode func(L) (F, error), decode func(F) (L, error)) {
+	conv := newConverter(encode, decode)
+	registry.Lock()
+	defer registry.Unlock()
+	registry.pairs[[2]reflect.Type{conv.local, conv.foreign}] = conv
+	invalidateRepresentations()
+}
+
+// RegisterLeafType registers a struct type whose values are copied as a whole, like scalars, instead of being
+// mapped field by field, eg uuid.UUID or resource.Quantity. Fields of the type, or pointers, slices and maps of it,
+// must map foreign fields of the same type. time.Time is registered by default.
+func RegisterLeafType[T any]() {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.leaves[reflect.TypeFor[T]()] = true
+	invalidateRepresentations()
+}
+
+// RegisterCopier registers the function copying values of a struct type T whenever they're assigned as a whole, eg
+// for leaf types or nested structs of the same type on both sides, instead of a shallow copy sharing the pointers,
+// slices and maps they hold with the source. Generated deep copy methods fit, eg
+// RegisterCopier((*corev1.PodSpec).DeepCopyInto). Registering a type again replaces the previous function.
+//
+// Parameters:
+//   - copy: The function copying a value into another one
+func RegisterCopier[T any](copy func(from, into *T)) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.copiers[reflect.TypeFor[T]()] = func(from, into any) { copy(from.(*T), into.(*T)) }
+}
+
+// RegisterConcreteType registers a pointer to a struct type T that foreign interface fields, eg `runtime.Object` or
+// `any`, can hold, so paths tagged with the `as<name>` option traverse them: Marshal allocates a T when the field
+// holds no value, or one of another type, while Unmarshal skips the field unless the interface holds a T. Types are
+// referenced by the name of the struct, qualified by its package name or not, eg `Deployment` or `v1.Deployment`.
+func RegisterConcreteType[T any]() {
+	registry.Lock()
+	defer registry.Unlock()
+	if typ := reflect.TypeFor[T](); !slices.Contains(registry.concretes, typ) {
+		registry.concretes = append(registry.concretes, typ)
+		invalidateRepresentations()
+	}
+}
+
+// invalidateRepresentations marks the representations cached so far as stale after a registration changing how
+// types are introspected, so every cache drops them the next time it's used, see typeCache.refresh. It must be
+// called holding the registry lock.
+func invalidateRepresentations() {
+	registry.generation.Add(1)
+}
+
+// lookupConcreteType finds the type registered with RegisterConcreteType under a name that an interface type can
+// hold. Registered types are referenced in index paths by a negative id, see concreteType.
+//
+// Parameters:
+//   - name: The name of the struct, qualified by its package name or not
+//   - iface: The type of the interface field
+//
+// Returns:
+//   - int: The id of the type, the index path entry asserting the interface field holds it
+//   - reflect.Type: The registered type
+//   - error: An error wrapping ErrConcreteType when no registered type, or several ones, match the name
+func lookupConcreteType(name string, iface reflect.Type) (int, reflect.Type, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	id, found := 0, []reflect.Type{}
+	for i, typ := range registry.concretes {
+		if typ.Kind() != reflect.Pointer || typ.Elem().Kind() != reflect.Struct || !typ.Implements(iface) {
+			continue
+		}
+		if typ.Elem().Name() == name || typ.Elem().String() == name {
+			id, found = -(i + 1), append(found, typ)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return 0, nil, fmt.Errorf("%w no pointer to a struct named %v implementing %v", ErrConcreteType, name, iface)
+	case 1:
+		return id, found[0], nil
+	default:
+		return 0, nil, fmt.Errorf("%w %v is ambiguous, found %v", ErrConcreteType, name, found)
+	}
+}
+
+// concreteType returns the type registered with RegisterConcreteType under an id returned by lookupConcreteType.
+func concreteType(id int) reflect.Type {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.concretes[-id-1]
+}
+
+// lookupCopier returns the function registered with RegisterCopier for a type, if any.
+func lookupCopier(typ reflect.Type) (func(from, into any), bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	copier, ok := registry.copiers[typ]
+	return copier, ok
+}
+
+// isLeafType reports whether a type was registered with RegisterLeafType.
+func isLeafType(typ reflect.Type) bool {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.leaves[typ]
+}
+
+// newConverter wraps a pair of typed conversion functions into a converter.
+func newConverter[L, F any](encode func(L) (F, error), decode func(F) (L, error)) converter {
+	return converter{
+		local:   reflect.TypeFor[L](),
+		foreign: reflect.TypeFor[F](),
+		encode: func(value any) (any, error) {
+			local, _ := value.(L) // nil interfaces are passed as the zero value
+			return encode(local)
+		},
+		decode: func(value any) (any, error) {
+			foreign, _ := value.(F)
+			return decode(foreign)
+		},
+	}
+}
+
+// lookupFunc returns the function registered under a name, if any.
+func lookupFunc(name string) (ComputeFunc, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	fn, ok := registry.funcs[name]
+	return fn, ok
+}
+
+// lookupInverseFunc returns the inverse function registered under a name, if any.
+func lookupInverseFunc(name string) (InverseFunc, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	fn, ok := registry.inverses[name]
+	return fn, ok
+}
+
+// lookupConverter returns the converter registered under a name, if any.
+func lookupConverter(name string) (converter, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	conv, ok := registry.converters[name]
+	return conv, ok
+}
+
+// lookupPairConverter returns the converter registered for a pair of local and foreign types, if any.
+func lookupPairConverter(local, foreign reflect.Type) (converter, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	conv, ok := registry.pairs[[2]reflect.Type{local, foreign}]
+	return conv, ok
+}
+
+// converter returns the converter transforming the value of a field mapping a foreign field of the given type: the
+// one named by its `convert<name>` option, the ones set with the `stringify` and `time<layout>` options, the one
+// registered for the types of the field and the foreign field it maps, or the built-in ones converting strings into types implementing the encoding text
+// interfaces, scalars into types implementing the database/sql interfaces, and strings into byte slices.
+func (this SourceField) converter(foreign reflect.Type) (converter, bool) {
+	if this.Tag.Opts.Convert != "" {
+		return lookupConverter(this.Tag.Opts.Convert)
+	}
+	if this.Tag.Opts.Stringify {
+		return newStringifyConverter(this.Type, foreign), true
+	}
+	if this.Tag.Opts.TimeLayout != "" {
+		return newTimeConverter(this.Type, foreign, this.Tag.Opts.TimeLayout), true
+	}
+	if conv, ok := lookupPairConverter(this.Type, foreign); ok {
+		return conv, ok
+	}
+	if textConvertible(this.Type, foreign) {
+		return newTextConverter(this.Type, foreign), true
+	}
+	if valuerConvertible(this.Type, foreign) {
+		return newValuerConverter(this.Type, foreign), true
+	}
+	if bytesConvertible(this.Type, foreign) {
+		return newBytesConverter(this.Type, foreign, this.Tag.Opts.Base64), true
+	}
+	return converter{}, false
+}
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// isText reports whether the values of a type can be marshaled into text, and a pointer to them unmarshaled back.
+func isText(typ reflect.Type) bool {
+	return typ.Implements(textMarshalerType) && reflect.PointerTo(typ).Implements(textUnmarshalerType)
+}
+
+// textConvertible reports whether one of the types is a string and the other one implements the encoding text
+// interfaces.
+func textConvertible(local, foreign reflect.Type) bool {
+	if local == nil || foreign == nil {
+		return false
+	}
+	return isText(local) && foreign.Kind() == reflect.String || local.Kind() == reflect.String && isText(foreign)
+}
+
+// newTextConverter creates the built-in converter between a string and a type implementing the encoding text
+// interfaces, eg net.IP, marshaling its values into text and unmarshaling them back.
+func newTextConverter(local, foreign reflect.Type) converter {
+	marshal := func(to reflect.Type) func(any) (any, error) {
+		return func(value any) (any, error) {
+			text, err := value.(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			return reflect.ValueOf(string(text)).Convert(to).Interface(), nil
+		}
+	}
+	unmarshal := func(to reflect.Type) func(any) (any, error) {
+		return func(value any) (any, error) {
+			result := reflect.New(to)
+			err := result.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(reflect.ValueOf(value).String()))
+			if err != nil {
+				return nil, err
+			}
+			return result.Elem().Interface(), nil
+		}
+	}
+	if isText(local) && foreign.Kind() == reflect.String {
+		return converter{local: local, foreign: foreign, encode: marshal(foreign), decode: unmarshal(local)}
+	}
+	return converter{local: local, foreign: foreign, encode: unmarshal(foreign), decode: marshal(local)}
+}
+
+var (
+	valuerType  = reflect.TypeFor[driver.Valuer]()
+	scannerType = reflect.TypeFor[sql.Scanner]()
+)
+
+// isValuer reports whether the values of a type can be turned into database values, and a pointer to them scan
+// those values back.
+func isValuer(typ reflect.Type) bool {
+	return typ.Implements(valuerType) && reflect.PointerTo(typ).Implements(scannerType)
+}
+
+// isDriverScalar reports whether a type holds a value a database driver handles: a string, a boolean, a number, a
+// byte slice or a time.
+func isDriverScalar(typ reflect.Type) bool {
+	isBytes := typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+	return typ.Kind() == reflect.String || typ.Kind() == reflect.Bool || isNumeric(typ) || isBytes || typ == timeType
+}
+
+// valuerConvertible reports whether one of the types implements the database/sql interfaces and the other one
+// holds a value a database driver handles.
+func valuerConvertible(local, foreign reflect.Type) bool {
+	if local == nil || foreign == nil {
+		return false
+	}
+	return isValuer(local) && isDriverScalar(foreign) || isDriverScalar(local) && isValuer(foreign)
+}
+
+// newValuerConverter creates the built-in converter between a type implementing the database/sql interfaces, eg a
+// persistence entity field, and a scalar. Values are turned into database values with driver.Valuer and the scalar
+// is converted into the type of the other side, while scalars are scanned back with sql.Scanner.
+func newValuerConverter(local, foreign reflect.Type) converter {
+	value := func(to reflect.Type) func(any) (any, error) {
+		return func(value any) (any, error) {
+			data, err := value.(driver.Valuer).Value()
+			if err != nil {
+				return nil, err
+			}
+			converted, err := fromDriverValue(data, to)
+			if err != nil {
+				return nil, err
+			}
+			return converted.Interface(), nil
+		}
+	}
+	scan := func(to reflect.Type) func(any) (any, error) {
+		return func(value any) (any, error) {
+			data, err := toDriverValue(reflect.ValueOf(value))
+			if err != nil {
+				return nil, err
+			}
+			result := reflect.New(to)
+			if err := result.Interface().(sql.Scanner).Scan(data); err != nil {
+				return nil, err
+			}
+			return result.Elem().Interface(), nil
+		}
+	}
+	if isValuer(local) && isDriverScalar(foreign) {
+		return converter{local: local, foreign: foreign, encode: value(foreign), decode: scan(local)}
+	}
+	return converter{local: local, foreign: foreign, encode: scan(foreign), decode: value(local)}
+}
+
+// toDriverValue converts a scalar into the type a database driver would hand to sql.Scanner: int64 for integers,
+// float64 for floats, and the underlying type for named strings, booleans and byte slices.
+func toDriverValue(value reflect.Value) (any, error) {
+	switch {
+	case value.CanInt() || value.CanUint():
+		converted, err := convertNumber(value, reflect.TypeFor[int64]())
+		return converted.Interface(), err
+	case value.CanFloat():
+		return value.Float(), nil
+	case value.Kind() == reflect.String:
+		return value.String(), nil
+	case value.Kind() == reflect.Bool:
+		return value.Bool(), nil
+	case value.Kind() == reflect.Slice:
+		return value.Bytes(), nil
+	}
+	return value.Interface(), nil
+}
+
+// fromDriverValue converts a value returned by driver.Valuer into a scalar type, nil converting into its zero
+// value. Numbers are converted between numeric types, and strings into byte slices and back.
+func fromDriverValue(data any, to reflect.Type) (reflect.Value, error) {
+	result := reflect.New(to).Elem()
+	if data == nil {
+		return result, nil
+	}
+	value := reflect.ValueOf(data)
+	switch {
+	case isNumeric(value.Type()) && isNumeric(to):
+		return convertNumber(value, to)
+	case value.Type().ConvertibleTo(to) && (value.Kind() == to.Kind() || bytesConvertible(value.Type(), to)):
+		result.Set(value.Convert(to))
+		return result, nil
+	}
+	return result, fmt.Errorf("database value %v can't be converted into %v", value.Type(), to)
+}
+
+// bytesConvertible reports whether one of the types is a string and the other one a byte slice.
+func bytesConvertible(local, foreign reflect.Type) bool {
+	if local == nil || foreign == nil {
+		return false
+	}
+	isBytes := func(typ reflect.Type) bool { return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 }
+	return local.Kind() == reflect.String && isBytes(foreign) || isBytes(local) && foreign.Kind() == reflect.String
+}
+
+// newBytesConverter creates the built-in converter between a string and a byte slice, holding the bytes as they
+// are or, when encoded, their standard base64 encoding.
+func newBytesConverter(local, foreign reflect.Type, encoded bool) converter {
+	convert := func(to reflect.Type) func(any) (any, error) {
+		return func(value any) (any, error) {
+			from := reflect.ValueOf(value)
+			result := reflect.New(to).Elem()
+			if from.Kind() == reflect.String {
+				data := []byte(from.String())
+				if encoded {
+					decoded, err := base64.StdEncoding.DecodeString(from.String())
+					if err != nil {
+						return nil, err
+					}
+					data = decoded
+				}
+				result.SetBytes(data)
+				return result.Interface(), nil
+			}
+			text := string(from.Bytes())
+			if encoded {
+				text = base64.StdEncoding.EncodeToString(from.Bytes())
+			}
+			result.SetString(text)
+			return result.Interface(), nil
+		}
+	}
+	return converter{local: local, foreign: foreign, encode: convert(foreign), decode: convert(local)}
+}
+
+// convertValue converts a value with one of the functions of a converter, the encode one when encoding.
+//
+// Parameters:
+//   - value: The value to convert, of the local type when encoding and of the foreign type otherwise
+//   - encoding: Whether the value is converted into the foreign type
+//
+// Returns:
+//   - reflect.Value: The converted value
+//   - error: An error if the conversion fails
+func (this converter) convertValue(value reflect.Value, encoding bool) (reflect.Value, error) {
+	convert, typ := this.decode, this.local
+	if encoding {
+		convert, typ = this.encode, this.foreign
+	}
+	result, err := convert(value.Interface())
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("%w %v to %v: %w", ErrConvertFunc, value.Type(), typ, err)
+	}
+	converted := reflect.New(typ).Elem()
+	if result != nil {
+		converted.Set(reflect.ValueOf(result))
+	}
+	return converted, nil
+}
+
+// computeValue calls the function referenced by the `compute<name>` option of a field with the foreign value,
+// checking the result can be assigned to the local field.
+//
+// Parameters:
+//   - field: The local field setting the `compute<name>` option
+//   - foreign: The foreign value found at the field path
+//
+// Returns:
+//   - reflect.Value: The computed value, invalid when the function returned nil
+//   - error: An error if the function isn't registered, fails, or returns a value of another type
+func computeValue(field SourceField, foreign reflect.Value) (reflect.Value, error) {
+	name := field.Tag.Opts.Compute
+	fn, ok := lookupFunc(name)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%w %v", ErrUnknownFunc, name)
+	}
+	result, err := fn(foreign.Interface())
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("%w %v for %v: %w", ErrComputeFunc, name, field.Name, err)
+	}
+	if result == nil {
+		return reflect.Value{}, nil
+	}
+
+	value := reflect.ValueOf(result)
+	if !value.Type().AssignableTo(field.Type) {
+		return reflect.Value{}, fmt.Errorf("%w %v returned %v for %v of type %v", ErrComputeFunc,
+			name, value.Type(), field.Name, field.Type)
+	}
+	return value, nil
+}
+
+// stringifiable reports whether one of the types is a string and the other one a boolean or a number.
+func stringifiable(local, foreign reflect.Type) bool {
+	if local == nil || foreign == nil {
+		return false
+	}
+	isScalar := func(typ reflect.Type) bool { return typ.Kind() == reflect.Bool || isNumeric(typ) }
+	return local.Kind() == reflect.String && isScalar(foreign) || isScalar(local) && foreign.Kind() == reflect.String
+}
+
+// newStringifyConverter creates the built-in converter of the `stringify` option, formatting booleans and numbers
+// into strings and parsing them back.
+func newStringifyConverter(local, foreign reflect.Type) converter {
+	convert := func(to reflect.Type) func(any) (any, error) {
+		return func(value any) (any, error) {
+			from := reflect.ValueOf(value)
+			if to.Kind() == reflect.String {
+				return reflect.ValueOf(formatScalar(from)).Convert(to).Interface(), nil
+			}
+			parsed, err := parseConstValue(from.String(), to)
+			if err != nil {
+				return nil, err
+			}
+			return parsed.Interface(), nil
+		}
+	}
+	return converter{local: local, foreign: foreign, encode: convert(foreign), decode: convert(local)}
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// timeConvertible reports whether one of the types is time.Time and the other one a string.
+func timeConvertible(local, foreign reflect.Type) bool {
+	if local == nil || foreign == nil {
+		return false
+	}
+	return local == timeType && foreign.Kind() == reflect.String || local.Kind() == reflect.String && foreign == timeType
+}
+
+// newTimeConverter creates the built-in converter of the `time<layout>` option, formatting times into strings
+// with the layout and parsing them back.
+func newTimeConverter(local, foreign reflect.Type, layout string) converter {
+	convert := func(to reflect.Type) func(any) (any, error) {
+		return func(value any) (any, error) {
+			if t, ok := value.(time.Time); ok {
+				return reflect.ValueOf(t.Format(layout)).Convert(to).Interface(), nil
+			}
+			return time.Parse(layout, reflect.ValueOf(value).String())
+		}
+	}
+	return converter{local: local, foreign: foreign, encode: convert(foreign), decode: convert(local)}
+}
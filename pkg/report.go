@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SkipReason describes why a mapped field could not be transferred between structs.
+type SkipReason string
+
+const (
+	SkipNilPointer   SkipReason = "nil pointer in path"
+	SkipEmptySlice   SkipReason = "empty slice in path"
+	SkipZeroValue    SkipReason = "zero value"
+	SkipTypeMismatch SkipReason = "type matching option did not match"
+	SkipMissingPath  SkipReason = "optional path not found in foreign type"
+	SkipConcrete     SkipReason = "interface field holds another concrete type"
+)
+
+// FieldSkip describes a local field whose value could not be transferred.
+// Field is the dotted path of the field in the local struct, Path the foreign path it maps to.
+type FieldSkip struct {
+	Field  string
+	Path   []string
+	Reason SkipReason
+}
+
+func (this FieldSkip) String() string {
+	return fmt.Sprintf("%v (%v): %v", this.Field, strings.Join(this.Path, "."), this.Reason)
+}
+
+// Report describes the outcome of a mapping performed by UnmarshalWithReport or MarshalWithReport.
+// Skipped holds every tagged field that could not be transferred along with the reason, in the order
+// they were found, fields dismissed by type matching come first.
+type Report struct {
+	Skipped []FieldSkip
+}
+
+// StrictError is returned by the strict entry points when one or more tagged fields
+// could not be transferred. It holds every skipped field along with the reason.
+type StrictError struct {
+	Skips []FieldSkip
+}
+
+func (this *StrictError) Error() string {
+	skips := make([]string, len(this.Skips))
+	for i, skip := range this.Skips {
+		skips[i] = skip.String()
+	}
+	return fmt.Sprintf("%v %v", ErrStrictSkippedFields, strings.Join(skips, "; "))
+}
+
+// Unwrap returns ErrStrictSkippedFields, so strict mode failures can be checked with errors.Is too.
+func (this *StrictError) Unwrap() error {
+	return ErrStrictSkippedFields
+}
+
+// FieldSet is a set of local field paths, using the same dotted notation as FieldSkip.Field,
+// eg "Name", "Nested.Direction" or "Items[1].Direction".
+type FieldSet map[string]struct{}
+
+// Has reports whether the set holds the given local field path.
+func (this FieldSet) Has(field string) bool {
+	_, ok := this[field]
+	return ok
+}
+
+// Paths returns the local field paths held by the set, sorted.
+func (this FieldSet) Paths() []string {
+	paths := make([]string, 0, len(this))
+	for path := range this {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// unmatchedFields walks a representation and its children gathering every field that was
+// dismissed during introspection because its type matching option didn't match the foreign type,
+// or its optional path doesn't exist in it.
+//
+// Parameters:
+//   - repr: The representation to walk
+//   - prefix: The local path of the parent field, empty for the root representation
+//
+// Returns:
+//   - []FieldSkip: A FieldSkip for each dismissed field, using SkipTypeMismatch or SkipMissingPath as reason
+func (this representations) unmatchedFields(repr StructRepr, prefix string) []FieldSkip {
+	skips := []FieldSkip{}
+	for _, field := range repr.Unmatched {
+		reason := SkipTypeMismatch
+		if field.Tag.Missing {
+			reason = SkipMissingPath
+		}
+		skips = append(skips, FieldSkip{
+			Field:  prefix + field.Name,
+			Path:   field.Tag.Path,
+			Reason: reason,
+		})
+	}
+	for _, field := range repr.Fields {
+		if child, ok := this.locals[field.ChildRef]; ok {
+			skips = append(skips, this.unmatchedFields(child, prefix+field.Name+".")...)
+		}
+	}
+	return skips
+}
+
+// collectAutoMappedFields walks a representation and its children adding every auto-mapped field to the given set.
+//
+// Parameters:
+//   - repr: The representation to walk
+//   - prefix: The local path of the parent field, empty for the root representation
+//   - fields: The set the local path of every auto-mapped field is added to
+//   - locals: The representations the children of the fields are resolved with
+func collectAutoMappedFields(repr StructRepr, prefix string, fields FieldSet, locals map[string]StructRepr) {
+	for _, field := range repr.Fields {
+		if field.Tag.Auto {
+			fields[prefix+field.Name] = struct{}{}
+		}
+		if child, ok := locals[field.ChildRef]; ok {
+			collectAutoMappedFields(child, prefix+field.Name+".", fields, locals)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package pkg
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// The aliases below have the fields of the representation types but none of their methods, so they are encoded
+// with the default behavior of encoding/json.
+type (
+	structReprAlias  StructRepr
+	sourceFieldAlias SourceField
+	targetFieldAlias TargetField
+)
+
+// structReprJSON is the JSON form of a StructRepr: its fields along with the representations of the nested structs
+// and the foreign fields they reference, keyed by the refs found in the fields.
+type structReprJSON struct {
+	structReprAlias
+	Children map[string]structReprAlias `json:",omitempty"`
+	Targets  map[string]TargetField     `json:",omitempty"`
+}
+
+// sourceFieldJSON is the JSON form of a SourceField, describing its kind and type by name.
+type sourceFieldJSON struct {
+	sourceFieldAlias
+	Kind string
+	Type string `json:",omitempty"`
+}
+
+// targetFieldJSON is the JSON form of a TargetField, describing its kind and type by name.
+type targetFieldJSON struct {
+	targetFieldAlias
+	Kind string
+	Type string `json:",omitempty"`
+}
+
+// MarshalJSON encodes the representation along with the representations of its nested structs and the foreign
+// fields they reference, found in the introspection cache, so the computed mapping of a pair of types can be
+// snapshotted, eg in golden tests.
+func (this StructRepr) MarshalJSON() ([]byte, error) {
+	referenced := this.references().snapshot(this)
+	children := make(map[string]structReprAlias, len(referenced.locals))
+	for key, child := range referenced.locals {
+		children[key] = structReprAlias(child)
+	}
+	return json.Marshal(structReprJSON{
+		structReprAlias: structReprAlias(this),
+		Children:        children,
+		Targets:         referenced.foreigns,
+	})
+}
+
+// UnmarshalJSON decodes a representation encoded by MarshalJSON. The representations of its nested structs and
+// the foreign fields they reference are not restored into the introspection cache, so the representation is meant
+// for inspection and comparison only.
+func (this *StructRepr) UnmarshalJSON(data []byte) error {
+	decoded := structReprJSON{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*this = StructRepr(decoded.structReprAlias)
+	return nil
+}
+
+// MarshalJSON encodes the field, describing its kind and type by name.
+func (this SourceField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sourceFieldJSON{
+		sourceFieldAlias: sourceFieldAlias(this),
+		Kind:             this.Kind.String(),
+		Type:             typeString(this.Type),
+	})
+}
+
+// UnmarshalJSON decodes a field encoded by MarshalJSON. Types can't be restored from their names, so Type is
+// left nil.
+func (this *SourceField) UnmarshalJSON(data []byte) error {
+	decoded := sourceFieldJSON{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*this = SourceField(decoded.sourceFieldAlias)
+	this.Kind = kindByName(decoded.Kind)
+	return nil
+}
+
+// MarshalJSON encodes the field, describing its kind and type by name.
+func (this TargetField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(targetFieldJSON{
+		targetFieldAlias: targetFieldAlias(this),
+		Kind:             this.Kind.String(),
+		Type:             typeString(this.Type),
+	})
+}
+
+// UnmarshalJSON decodes a field encoded by MarshalJSON. Types can't be restored from their names, so Type is
+// left nil.
+func (this *TargetField) UnmarshalJSON(data []byte) error {
+	decoded := targetFieldJSON{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*this = TargetField(decoded.targetFieldAlias)
+	this.Kind = kindByName(decoded.Kind)
+	return nil
+}
+
+// typeString returns the name of a type including its package name, empty for nil types.
+func typeString(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+// kindByName returns the kind of the given name, as returned by reflect.Kind.String, reflect.Invalid when unknown.
+func kindByName(name string) reflect.Kind {
+	for kind := reflect.Invalid; kind <= reflect.UnsafePointer; kind++ {
+		if kind.String() == name {
+			return kind
+		}
+	}
+	return reflect.Invalid
+}
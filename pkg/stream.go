@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"context"
+	"sync"
+)
+
+// UnmarshalStream decodes foreign objects as they arrive through the `in` channel (watch streams, message queues)
+// and sends the resulting local structs through the `out` channel.
+// The representation is computed once and reused for every object, only being recomputed if the type of the
+// received foreign objects changes.
+//
+// Parameters:
+//   - ctx: Cancelling the context stops the stream, returning the context error
+//   - in: The channel delivering foreign objects, the stream ends when it is closed
+//   - out: The channel receiving the decoded local structs, it is not closed by this function
+//
+// Returns:
+//   - error: nil when `in` is closed, the context error if cancelled, or the first decoding error
+func UnmarshalStream[L any](ctx context.Context, in <-chan interface{}, out chan<- L) error {
+	decoder := &StructDecoder{opts: defaultCodec.opts}
+
+	for {
+		var foreign interface{}
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case foreign, ok = <-in:
+			if !ok {
+				return nil
+			}
+		}
+
+		var local L
+		if err := decoder.decodeNext(foreign, &local); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case out <- local:
+		}
+	}
+}
+
+// Pipe maps the values received through the `in` channel with a pool of workers and sends the results through the
+// `out` channel, for controllers and ETL-style consumers processing large object streams. Values are mapped with
+// the provided function, which sets the direction of the translation: Marshal or Unmarshal, the methods of a
+// Codec, or the Encode and Decode methods of a CompiledMapper. Results are sent in the order they are mapped,
+// which only matches the order of the input with a single worker.
+//
+// Parameters:
+//   - ctx: Cancelling the context stops the pipe, returning the context error
+//   - in: The channel delivering the values to map, the pipe ends when it is closed
+//   - out: The channel receiving the mapped values, it is not closed by this function
+//   - mapFn: The function mapping a value into a pointer to the result, eg pkg.Marshal
+//   - workers: The number of goroutines mapping values, one when lower
+//
+// Returns:
+//   - error: nil when `in` is closed and every value was sent, the context error if cancelled, or the first mapping
+//     error, which stops the remaining workers
+func Pipe[S, D any](
+	ctx context.Context,
+	in <-chan S,
+	out chan<- D,
+	mapFn func(from interface{}, into interface{}) error,
+	workers int,
+) error {
+	workersCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var failure error
+	var once sync.Once
+	wg := sync.WaitGroup{}
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var value S
+				var ok bool
+				select {
+				case <-workersCtx.Done():
+					return
+				case value, ok = <-in:
+					if !ok {
+						return
+					}
+				}
+
+				var result D
+				if err := mapFn(value, &result); err != nil {
+					once.Do(func() {
+						failure = err
+						cancel()
+					})
+					return
+				}
+
+				select {
+				case <-workersCtx.Done():
+					return
+				case out <- result:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failure != nil {
+		return failure
+	}
+	return ctx.Err()
+}
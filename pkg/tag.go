@@ -6,24 +6,100 @@ import (
 	"reflect"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 )
 
 type TypeMatch struct {
-	Path    []string
-	Name    string
-	Matches bool
+	Path       []string
+	Name       string
+	Matches    bool
+	Precedence TypeMatchPrecedence // how specific the match against the foreign type was
+	Pattern    *regexp.Regexp      // expression of the entries prefixed with `~`, nil when it doesn't compile
+}
+
+// TypeMatchPrecedence ranks how specifically a type matching entry matched the foreign type.
+// When several entries match, the one with the highest precedence wins.
+type TypeMatchPrecedence int
+
+const (
+	NoTypeMatch          TypeMatchPrecedence = iota
+	MatchByPattern                           // regular expression matching any form of the name, eg types<~^API.*$>
+	MatchByName                              // bare type name, eg types<APIObject>
+	MatchByQualifiedName                     // package qualified type name, eg types<api.APIObject>
+	MatchByImportPath                        // import path qualified type name, eg types<github.com/org/api.APIObject>
+)
+
+func (this TypeMatchPrecedence) String() string {
+	switch this {
+	case MatchByPattern:
+		return "pattern"
+	case MatchByName:
+		return "name"
+	case MatchByQualifiedName:
+		return "qualified name"
+	case MatchByImportPath:
+		return "import path"
+	}
+	return "none"
 }
 
 type TagOpts struct {
-	MatchTypes []TypeMatch
+	MatchTypes   []TypeMatch
+	MapMerge     MapMergeMode    // how map entries are written into destination maps holding entries, if set
+	MergeKey     string          // local element field matching slice elements when decoding into existing slices
+	Preserve     bool            // keep the local slice elements the mapping doesn't touch when decoding
+	Required     bool            // fail the mapping instead of skipping the field when its value can't be transferred
+	Optional     bool            // dismiss the field instead of failing when its path doesn't exist in the foreign type
+	Const        string          // value always written to the foreign field when encoding, the field is ignored when decoding
+	In           []string        // path the field is decoded from, when it differs from the one it is encoded into
+	Out          []string        // path the field is encoded into, when it differs from the one it is decoded from
+	When         *FieldCondition // foreign value the mapping of the field depends on, if any
+	Kinds        []TypeMatch     // path for each value of the foreign discriminator field, set with `kind<>`
+	Join         []JoinPart      // foreign fields and literals joined into the local string, set with `join<>` or `split<>`
+	JoinError    error           // error found parsing the `join<>` or `split<>` options, reported when validating the tag
+	KindField    []string        // path of the foreign discriminator field, relative to the parent field path
+	Versions     []TypeMatch     // path for each value of the foreign API version field, set with `version<>`
+	VersionField []string        // path of the foreign API version field, relative to the parent field path
+	Compute      string          // name of the registered function deriving the field from the foreign value, if any
+	Convert      string          // name of the registered converter transforming the field value, if any
+	Base64       bool            // the string the field maps from or to holds the base64 encoding of the bytes
+	Stringify    bool            // the boolean or number held by the field is represented as a string by the other side
+	TimeLayout   string          // layout of the string a time is represented as by the other side, set with `time<>`
+	As           []string        // concrete type held by each interface field the path traverses, set with `as<>`
+	ReadOnly     bool            // the field is only decoded from the foreign object, never encoded into it
+	WriteOnly    bool            // the field is only encoded into the foreign object, never decoded from it
+}
+
+// FieldCondition is the condition set with the `when<path=value>` tag option: the field is only mapped while the
+// foreign field found in Path, relative to the path of the parent field, holds Value.
+type FieldCondition struct {
+	Path  []string
+	Value string
 }
 
 type FieldTag struct {
-	Path       []string
-	Opts       TagOpts
-	Skip       bool
-	TargetType string
+	Path         []string
+	Opts         TagOpts
+	Skip         bool
+	Unmatched    bool       // the field was skipped because none of the type matching options matched
+	Missing      bool       // the field was skipped because its optional path doesn't exist in the foreign type
+	Auto         bool       // the field is untagged and was mapped to the foreign field of the same name
+	Alternatives [][]string // fallback paths listed after the main path, eg se:"Spec.Name||Spec.OldName"
+	Fallback     bool       // the tag maps one of the alternatives, only decoded when the previous paths are empty
+	Match        TypeMatch  // the type matching option selected for the foreign type, if any
+	TargetType   string
+	Raw          string // the tag as declared, or registered, for the field
+}
+
+// encodes reports whether the field is written into the foreign object when marshaling, fallback paths never are.
+func (this FieldTag) encodes() bool {
+	return !this.Opts.ReadOnly && !this.Fallback
+}
+
+// decodes reports whether the field is read from the foreign object when unmarshaling.
+func (this FieldTag) decodes() bool {
+	return !this.Opts.WriteOnly && this.Opts.Const == ""
 }
 
 // check naming convention when using "type matching" tag option
@@ -32,21 +108,47 @@ type FieldTag struct {
 // - TypeMatch with `Matches` property set false if no match is found but there are type-matching options set in this
 // tag field
 // - the TypeMatch description if a match is found
-func (t *FieldTag) findTypeMatch(typeName string) TypeMatch {
+//
+// When more than one entry matches the foreign type the most specific one wins (see TypeMatchPrecedence),
+// entries with the same precedence are resolved by declaration order.
+func (t *FieldTag) findTypeMatch(foreign reflect.Type) TypeMatch {
 	result := TypeMatch{Matches: true}
-	if len(t.Opts.MatchTypes) > 0 && typeName != "" {
+	if len(t.Opts.MatchTypes) > 0 && foreign.Name() != "" {
 		result.Matches = false
 		for _, match := range t.Opts.MatchTypes {
-			if match.Name == typeName {
+			precedence := typeMatchPrecedence(match, foreign)
+			if precedence > result.Precedence {
 				result = match
 				result.Matches = true
-				break
+				result.Precedence = precedence
 			}
 		}
 	}
 	return result
 }
 
+// typeMatchPrecedence checks a type matching entry against the foreign type,
+// returning how specific the match was or NoTypeMatch if the entry doesn't match at all.
+// Patterns are matched against the bare, package qualified and import path qualified names of the type.
+func typeMatchPrecedence(match TypeMatch, foreign reflect.Type) TypeMatchPrecedence {
+	if match.Pattern != nil {
+		names := []string{foreign.Name(), foreign.String(), foreign.PkgPath() + "." + foreign.Name()}
+		if slices.ContainsFunc(names, match.Pattern.MatchString) {
+			return MatchByPattern
+		}
+		return NoTypeMatch
+	}
+	switch match.Name {
+	case foreign.PkgPath() + "." + foreign.Name():
+		return MatchByImportPath
+	case foreign.String():
+		return MatchByQualifiedName
+	case foreign.Name():
+		return MatchByName
+	}
+	return NoTypeMatch
+}
+
 // validatePaths checks if the paths in a FieldTag and a TypeMatch are valid together.
 // It returns an error if:
 // 1. The TypeMatch has a path AND the FieldTag's first path element is the a multi-type operator
@@ -71,36 +173,46 @@ func (this *FieldTag) validatePaths(match TypeMatch) error {
 	}
 
 	if failedCheck {
-		err = errors.New(ErrInvalidPerTypePath)
+		err = ErrInvalidPerTypePath
 	}
 
 	return err
 }
 
-// validate checks if a FieldTag is valid for the given foreign root type.
+// validate checks if a FieldTag is valid for the given foreign root type, keeping track of the selected type match.
 // It returns an error if the tag is invalid, or nil if the tag is valid.
 //
 // If the tag has Skip set to true, the function returns nil.
 //
 // The function first checks if the foreign root type matches any of the type
 // matches specified in the tag options. If no match is found, the tag is marked
-// as Skip and the function returns nil.
+// as Skip and Unmatched and the function returns nil.
 //
 // If a match is found, the function checks if the per-type path naming is valid
 // using checkPerTypePathNaming. If the match has a path, the function replaces
 // the tag's main path with the match's path.
-func (this *FieldTag) validate(foreignRootType string) error {
+func (this *FieldTag) validate(foreignRoot reflect.Type) error {
 	if this.Skip {
 		return nil
 	}
+	if err := this.validatePatterns(); err != nil {
+		return err
+	}
 
-	match := this.findTypeMatch(foreignRootType)
+	match := this.findTypeMatch(foreignRoot)
+	this.Match = match
 	if !match.Matches {
 		this.Skip = true
+		this.Unmatched = true
 		return nil
 	}
 
-	err := this.validatePaths(match)
+	if this.Opts.Compute != "" {
+		if _, ok := lookupFunc(this.Opts.Compute); !ok {
+			return fmt.Errorf("%w %v", ErrUnknownFunc, this.Opts.Compute)
+		}
+	}
+	err := this.validateMatch(match)
 
 	if len(match.Path) > 0 {
 		// replace tag main path with type-matching path
@@ -110,46 +222,146 @@ func (this *FieldTag) validate(foreignRootType string) error {
 	return err
 }
 
+// Validate checks a tag parsed with ParseTag or ParseTagValue against the rules the mapping enforces regardless of
+// the foreign type, so linters and code generators can report invalid tags without introspecting types: the per-type
+// path of every `types<>` entry is checked as if it matched. Whether the functions and converters named by the tag
+// are registered isn't checked, as they are usually registered at runtime.
+//
+// Returns:
+//   - error: The error the mapping would fail with, nil when the tag is valid or skips the field
+func (this FieldTag) Validate() error {
+	if this.Skip {
+		return nil
+	}
+	if err := this.validatePatterns(); err != nil {
+		return err
+	}
+	if _, err := expandTag(this); err != nil {
+		return err
+	}
+
+	matches := this.Opts.MatchTypes
+	if len(matches) == 0 {
+		matches = []TypeMatch{{Matches: true}}
+	}
+	for _, match := range matches {
+		if err := this.validateMatch(match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePatterns checks that the patterns of the `types<>` entries are valid regular expressions.
+func (this FieldTag) validatePatterns() error {
+	for _, match := range this.Opts.MatchTypes {
+		if strings.HasPrefix(match.Name, TYPE_PATTERN_PREFIX) && match.Pattern == nil {
+			return fmt.Errorf("%w %v", ErrInvalidTypePattern, match.Name)
+		}
+	}
+	return nil
+}
+
+// validateMatch checks that the options of a tag are valid together with the type match selected for the foreign
+// type, see validate.
+func (this FieldTag) validateMatch(match TypeMatch) error {
+	if len(match.Path) > 0 && (len(this.Opts.In) > 0 || len(this.Opts.Out) > 0) {
+		return ErrInvalidDirectionalPath
+	}
+	if this.Opts.JoinError != nil {
+		return this.Opts.JoinError
+	}
+	if len(this.Opts.Join) > 0 && (this.Path[0] != MULTI_TYPE_NAME || len(match.Path) > 0) {
+		return fmt.Errorf("%w main path should be '+' when joining foreign fields", ErrInvalidJoin)
+	}
+	if this.Opts.Compute != "" && (len(this.Opts.Join) > 0 || this.Opts.Const != "") {
+		return ErrConflictingCompute
+	}
+	if this.Opts.Convert != "" && (len(this.Opts.Join) > 0 || this.Opts.Const != "" || this.Opts.Compute != "") {
+		return ErrConflictingConvert
+	}
+
+	err := this.validatePaths(match)
+	if err == nil && this.Opts.MapMerge != "" && !this.Opts.MapMerge.valid() {
+		err = fmt.Errorf("%w %v", ErrInvalidMapMergeMode, this.Opts.MapMerge)
+	}
+	if err == nil && this.Opts.ReadOnly && (this.Opts.WriteOnly || this.Opts.Const != "") {
+		err = ErrConflictingDirection
+	}
+	return err
+}
+
 // getTagAndTarget processes a struct field and returns a FieldTag, the target field ref key, and any error encountered.
 //
 // Parameters:
 // - foreignRoot: The name of the foreign root type to validate against
 // - field: The reflect.StructField being processed
+// - tag: The tag parsed from the field, see parseTag and splitDirections
 // - alien: The reflect.Type of the foreign struct being matched against
 // - parentPath: The path from parent fields, if any
+// - opts: The options of the Codec requesting the representation
 //
 // Returns:
 // - FieldTag: The parsed and validated field tag
 // - string: The target field name in the foreign struct
 // - error: Any error encountered during processing
 //
-// The function first parses the field's tag, then validates it against the foreign root type.
+// The function first validates the field's tag against the foreign root type.
 // If the tag is set to be skipped or validation fails, it returns early.
 // Otherwise, it processes the path (handling nested fields appropriately) and
 // determines the target field name and type in the foreign struct.
 func getTagAndTarget(
 	foreignRoot string,
 	field reflect.StructField,
+	tag FieldTag,
 	alien reflect.Type,
 	parentPath []string,
+	opts *options,
 ) (FieldTag, string, error) {
 	var err error
-	tag := parseTag(field)
-	err = tag.validate(foreignRoot)
+	err = tag.validate(alien)
 	if tag.Skip || err != nil {
 		return tag, "", err
 	}
 
 	var target, targetType string
-	if tag.Path[0] == DISMISS_NESTED {
+	if tag.Path[0] == MULTI_TYPE_NAME && tag.Opts.Compute != "" {
+		// computed from the foreign struct holding the field, which is the foreign root on the top level
 		tag.Path = parentPath
-	} else {
 		if len(parentPath) > 0 {
+			target, targetType, err = parseTargetField(tag.Path, alien, opts)
+		}
+	} else if tag.Path[0] == DISMISS_NESTED || len(tag.Opts.Join) > 0 {
+		tag.Path = parentPath
+	} else {
+		if tag.Path[0] == PARENT_PATH || tag.Path[0] == ABSOLUTE_PATH {
+			if tag.Path, err = resolveRelativePath(tag.Path, parentPath, alien, opts); err != nil {
+				return tag, "", err
+			}
+			if len(tag.Path) == 0 {
+				return tag, "", nil // the root, just like dismissing the path nesting at the top level
+			}
+		} else if len(parentPath) > 0 {
 			tag.Path = append(parentPath, tag.Path...)
 		}
-		if target, targetType, err = parseTargetField(tag.Path, alien); err != nil {
+		if len(tag.Opts.As) > 0 {
+			if tag.Path, err = assertConcreteTypes(tag.Path, alien, tag.Opts.As, opts); err != nil {
+				return tag, "", err
+			}
+		}
+		if target, targetType, err = parseTargetField(tag.Path, alien, opts); err != nil {
+			var notFound *FieldNotFoundError
+			if errors.As(err, &notFound) {
+				notFound.LocalField = field.Name
+				notFound.Path = tag.Path
+				notFound.ForeignType = foreignRoot
+			}
 			return tag, "", err
 		}
+		if resolved := opts.cache.foreigns[target].Path; len(resolved) != len(tag.Path) {
+			// promoted fields are mapped through the structs embedding them, which nested fields are resolved under
+			tag.Path = slices.Clone(resolved)
+		}
 	}
 
 	tag.TargetType = targetType
@@ -157,23 +369,377 @@ func getTagAndTarget(
 	return tag, target, err
 }
 
-// parseTag parses a field tag string into a FieldTag struct. The field tag string
-// is expected to be in the format "path,opt1,opt2,...". The path is split on
+// expandTag turns a tag parsed from a field into the tags the field is mapped with: one for each alternative path,
+// one for each per-version or per-kind path, one for each direction when the paths differ, or the tag itself. See
+// splitAlternatives, splitVersions, splitKinds and splitDirections.
+func expandTag(tag FieldTag) ([]FieldTag, error) {
+	if !tag.Skip && len(tag.Alternatives) > 0 {
+		return splitAlternatives(tag)
+	}
+	if !tag.Skip && len(tag.Opts.Versions) > 0 {
+		return splitVersions(tag)
+	}
+	if !tag.Skip && len(tag.Opts.Kinds) > 0 {
+		return splitKinds(tag)
+	}
+	return splitDirections(tag), nil
+}
+
+// splitAlternatives turns a tag listing fallback paths into one tag per path, in order. Every tag but the first one
+// is marked as fallback, see parseStructFields for how the ones whose path doesn't exist are handled.
+func splitAlternatives(tag FieldTag) ([]FieldTag, error) {
+	hasTypePath := slices.ContainsFunc(tag.Opts.MatchTypes, func(match TypeMatch) bool { return len(match.Path) > 0 })
+	hasDiscriminator := len(tag.Opts.Kinds) > 0 || len(tag.Opts.Versions) > 0
+	if hasTypePath || hasDiscriminator || len(tag.Opts.In) > 0 || len(tag.Opts.Out) > 0 {
+		return nil, ErrInvalidFallbackPath
+	}
+
+	tags := []FieldTag{tag}
+	for _, path := range tag.Alternatives {
+		fallback := tag
+		fallback.Path = path
+		fallback.Fallback = true
+		tags = append(tags, fallback)
+	}
+	return tags, nil
+}
+
+// splitKinds turns a tag setting a path for each value of the foreign discriminator field with the `kind<>` option
+// into one tag per value, mapped to its path only while the discriminator field holds the value, that is, using
+// a `when<>` condition. The discriminator is the "Kind" field unless set with the `kindfield<path>` option, and
+// entries without their own path use the main path.
+func splitKinds(tag FieldTag) ([]FieldTag, error) {
+	if tag.Opts.When != nil || len(tag.Opts.MatchTypes) > 0 || len(tag.Opts.In) > 0 || len(tag.Opts.Out) > 0 {
+		return nil, ErrInvalidKindPath
+	}
+	kindField := tag.Opts.KindField
+	if len(kindField) == 0 {
+		kindField = []string{DEFAULT_KIND_FIELD}
+	}
+	kinds := tag.Opts.Kinds
+	tag.Opts.Kinds = nil
+	return splitDiscriminated(tag, kinds, kindField, ErrMissingKindPath)
+}
+
+// splitVersions turns a tag setting a path for each value of the foreign API version field with the `version<>`
+// option into one tag per version, just like splitKinds does for kinds, so a single local struct can map payloads of
+// different versions sharing the same Go type but not the same layout. The version is read from the "APIVersion"
+// field unless set with the `versionfield<path>` option.
+func splitVersions(tag FieldTag) ([]FieldTag, error) {
+	if tag.Opts.When != nil || len(tag.Opts.Kinds) > 0 || len(tag.Opts.MatchTypes) > 0 ||
+		len(tag.Opts.In) > 0 || len(tag.Opts.Out) > 0 {
+		return nil, ErrInvalidVersionPath
+	}
+	versionField := tag.Opts.VersionField
+	if len(versionField) == 0 {
+		versionField = []string{DEFAULT_VERSION_FIELD}
+	}
+	versions := tag.Opts.Versions
+	tag.Opts.Versions = nil
+	return splitDiscriminated(tag, versions, versionField, ErrMissingVersionPath)
+}
+
+// splitDiscriminated turns a tag into one tag for each of the provided entries, mapped to the entry path, or the
+// main path when the entry doesn't set one, only while the foreign field found in discriminator holds the entry
+// name. missingPathErr is returned when neither the entry nor the tag set a path to map to.
+func splitDiscriminated(tag FieldTag, entries []TypeMatch, discriminator []string, missingPathErr error) (
+	[]FieldTag, error,
+) {
+	tags := []FieldTag{}
+	for _, entry := range entries {
+		variant := tag
+		variant.Opts.When = &FieldCondition{Path: discriminator, Value: entry.Name}
+		if len(entry.Path) > 0 {
+			variant.Path = entry.Path
+		} else if tag.Path[0] == MULTI_TYPE_NAME {
+			return nil, fmt.Errorf("%w %v", missingPathErr, entry.Name)
+		}
+		tags = append(tags, variant)
+	}
+	return tags, nil
+}
+
+// splitDirections turns a tag setting a different path for each direction with the `in<path>` and `out<path>`
+// options into one tag per direction: a readonly one holding the path decoded from, and a writeonly one holding
+// the path encoded into. The main path is used for the direction without its own path, unless it is "+", in which
+// case that direction is not mapped at all. Any other tag is returned as is.
+func splitDirections(tag FieldTag) []FieldTag {
+	if tag.Skip || (len(tag.Opts.In) == 0 && len(tag.Opts.Out) == 0) {
+		return []FieldTag{tag}
+	}
+
+	tags := []FieldTag{}
+	in, out := tag, tag
+	if len(tag.Opts.In) > 0 {
+		in.Path = tag.Opts.In
+	}
+	if in.Path[0] != MULTI_TYPE_NAME {
+		in.Opts.ReadOnly = true
+		tags = append(tags, in)
+	}
+	if len(tag.Opts.Out) > 0 {
+		out.Path = tag.Opts.Out
+	}
+	if out.Path[0] != MULTI_TYPE_NAME {
+		out.Opts.WriteOnly = true
+		tags = append(tags, out)
+	}
+	return tags
+}
+
+// scalarKinds are the kinds of the foreign fields that can be compared and built from strings.
+var scalarKinds = []reflect.Kind{
+	reflect.String, reflect.Bool,
+	reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+	reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+	reflect.Float32, reflect.Float64,
+}
+
+// isScalar reports whether the foreign field holds a single string, boolean or number.
+func (this TargetField) isScalar() bool {
+	return !this.IsArray && !this.IsMap && slices.Contains(scalarKinds, this.Kind)
+}
+
+// JoinPart is an element of the `join<>` tag option: either the path of a foreign field, relative to the path of
+// the parent field, or a literal when Path is nil.
+type JoinPart struct {
+	Path    []string
+	Literal string
+}
+
+// parseJoinParts parses the content of a `join<>` tag option, a comma separated list of foreign paths and double
+// quoted literals, eg `Metadata.Namespace,"/",Metadata.Name`.
+func parseJoinParts(data string) ([]JoinPart, error) {
+	parts := []JoinPart{}
+	for _, item := range splitTagParts(data) {
+		if strings.HasPrefix(item, `"`) {
+			literal, err := strconv.Unquote(item)
+			if err != nil {
+				return nil, fmt.Errorf("%w %v", ErrInvalidJoin, item)
+			}
+			parts = append(parts, JoinPart{Literal: literal})
+			continue
+		}
+		parts = append(parts, JoinPart{Path: strings.Split(item, ".")})
+	}
+	return parts, nil
+}
+
+// parseSplitParts parses the content of a `split<>` tag option, a double quoted separator followed by the foreign
+// paths the local string is split into, eg `"/",Metadata.Namespace,Metadata.Name`, into the equivalent `join<>`
+// parts.
+func parseSplitParts(data string) ([]JoinPart, error) {
+	items := splitTagParts(data)
+	if len(items) < 3 || !strings.HasPrefix(items[0], `"`) {
+		return nil, fmt.Errorf("%w split<%v> needs a separator and at least two paths", ErrInvalidJoin, data)
+	}
+	separator, err := strconv.Unquote(items[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w %v", ErrInvalidJoin, items[0])
+	}
+
+	parts := []JoinPart{}
+	for i, item := range items[1:] {
+		if strings.HasPrefix(item, `"`) {
+			return nil, fmt.Errorf("%w unexpected literal %v in split<%v>", ErrInvalidJoin, item, data)
+		}
+		if i > 0 {
+			parts = append(parts, JoinPart{Literal: separator})
+		}
+		parts = append(parts, JoinPart{Path: strings.Split(item, ".")})
+	}
+	return parts, nil
+}
+
+// resolveJoin locates the foreign fields joined by the `join<>` option of a tag, under the same parent path as the
+// tag path, and returns the ref key of each one, empty for literals. Joined fields must hold a string, a boolean or a
+// number, and every path but the last one must be followed by a literal so joined values can be split back.
+func resolveJoin(
+	tag FieldTag,
+	stfield reflect.StructField,
+	alien reflect.Type,
+	parentPath []string,
+	opts *options,
+) ([]string, error) {
+	if len(tag.Opts.Join) == 0 {
+		return nil, nil
+	}
+	if stfield.Type.Kind() != reflect.String {
+		return nil, fmt.Errorf("%w %v is not a string", ErrInvalidJoin, stfield.Name)
+	}
+
+	refs := make([]string, len(tag.Opts.Join))
+	for i, part := range tag.Opts.Join {
+		if part.Path == nil {
+			if part.Literal == "" {
+				return nil, fmt.Errorf("%w empty literal in %v", ErrInvalidJoin, stfield.Name)
+			}
+			continue
+		}
+		if i < len(tag.Opts.Join)-1 && tag.Opts.Join[i+1].Path != nil {
+			return nil, fmt.Errorf("%w paths must be separated by a literal in %v", ErrInvalidJoin, stfield.Name)
+		}
+		path := append(slices.Clone(parentPath), part.Path...)
+		key, _, err := parseTargetField(path, alien, opts)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.cache.foreigns[key].isScalar() {
+			return nil, fmt.Errorf("%w %v is not a string, boolean or number", ErrInvalidJoin, strings.Join(path, "."))
+		}
+		refs[i] = key
+	}
+	return refs, nil
+}
+
+// splitJoined splits a joined value back into the value of each part of a `join<>` option, empty for literals,
+// reporting whether the value matches the literals of the option. Every path takes the text up to the first
+// occurrence of the literal following it, the last one takes the rest.
+func splitJoined(value string, parts []JoinPart) ([]string, bool) {
+	pieces := make([]string, len(parts))
+	rest := value
+	for i, part := range parts {
+		if part.Path == nil {
+			if !strings.HasPrefix(rest, part.Literal) {
+				return nil, false
+			}
+			rest = rest[len(part.Literal):]
+			continue
+		}
+		if i == len(parts)-1 {
+			pieces[i], rest = rest, ""
+			continue
+		}
+		end := strings.Index(rest, parts[i+1].Literal)
+		if end < 0 {
+			return nil, false
+		}
+		pieces[i], rest = rest[:end], rest[end:]
+	}
+	return pieces, rest == ""
+}
+
+// splitTagParts splits a tag, or the content of a tag option, on the commas found outside of `<>` options and
+// double quoted literals.
+func splitTagParts(raw string) []string {
+	parts := []string{}
+	depth, quoted, start := 0, false, 0
+	for i := 0; i < len(raw); i++ {
+		switch {
+		case raw[i] == '\\' && quoted:
+			i++ // skip escaped characters in literals
+		case raw[i] == '"':
+			quoted = !quoted
+		case quoted:
+		case raw[i] == '<':
+			depth++
+		case raw[i] == '>' && depth > 0:
+			depth--
+		case raw[i] == ',' && depth == 0:
+			parts = append(parts, raw[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, raw[start:])
+}
+
+// resolveCondition locates the foreign field the `when<>` option of a tag compares, under the same parent path as
+// the tag path, and returns its ref key. Only fields holding a string, a boolean or a number can be compared.
+func resolveCondition(tag FieldTag, alien reflect.Type, parentPath []string, opts *options) (string, error) {
+	if tag.Opts.When == nil {
+		return "", nil
+	}
+	path := append(slices.Clone(parentPath), tag.Opts.When.Path...)
+	key, _, err := parseTargetField(path, alien, opts)
+	if err != nil {
+		return "", err
+	}
+	condition := opts.cache.foreigns[key]
+	if !condition.isScalar() {
+		return "", fmt.Errorf("%w %v", ErrInvalidCondition, strings.Join(path, "."))
+	}
+	return key, nil
+}
+
+// isAutoMappable reports whether a field is eligible for auto-mapping, that is, an exported field without a tag
+// under the given key, `se` by default, other than the embedded structs whose fields are squashed.
+func isAutoMappable(field reflect.StructField, key string) bool {
+	_, tagged := field.Tag.Lookup(key)
+	return field.IsExported() && !tagged && !isSquashed(field, key)
+}
+
+// isSquashed reports whether a field embeds a struct, or a pointer to a struct, without a tag under the given key,
+// whose fields are mapped as if declared by the struct embedding it, just like encoding/json promotes them. Leaf
+// types are copied as a whole instead, and pointers to unexported types can't be allocated.
+func isSquashed(field reflect.StructField, key string) bool {
+	if _, tagged := field.Tag.Lookup(key); tagged || !field.Anonymous {
+		return false
+	}
+	if field.Type.Kind() == reflect.Pointer && !field.IsExported() {
+		return false
+	}
+	embedded := derefType(field.Type)
+	return embedded.Kind() == reflect.Struct && !isLeafType(embedded)
+}
+
+// getAutoTagAndTarget builds the tag of an auto-mapped field, pointing to the foreign field of the same name under
+// the parent path, and returns it along with the target field ref key. The tag is marked to be skipped when the
+// foreign struct has no such field.
+func getAutoTagAndTarget(
+	field reflect.StructField,
+	alien reflect.Type,
+	parentPath []string,
+	opts *options,
+) (FieldTag, string) {
+	tag := FieldTag{Path: append(slices.Clone(parentPath), field.Name), Auto: true}
+	target, targetType, err := parseTargetField(tag.Path, alien, opts)
+	if err != nil {
+		tag.Skip = true
+		return tag, ""
+	}
+	tag.TargetType = targetType
+	return tag, target
+}
+
+// parseTag parses the field tag string found under the given key, `se` by default, into a FieldTag struct.
+// The field tag string is expected to be in the format "path,opt1,opt2,...". The path is split on
 // periods to create the Path field of the FieldTag struct. The remaining comma-
 // separated values are parsed into the Opts field of the FieldTag struct.
 //
-// If the field tag string is empty, the function returns a FieldTag with skip
-// set to true.
-func parseTag(field reflect.StructField) FieldTag {
-	tag := FieldTag{}
-	rawString := field.Tag.Get(FIELD_TAG_KEY)
-	if rawString == "" {
+// If the field tag string is empty or "-", the function returns a FieldTag with skip
+// set to true. A path set to "=" maps to the foreign field named after the local field, and several paths can be
+// listed separated by "||", the ones following the first one being kept as alternatives. Untagged embedded structs
+// are squashed, their fields being mapped under the path of the struct embedding them as with the "->" path.
+func parseTag(field reflect.StructField, key string) FieldTag {
+	if isSquashed(field, key) {
+		return FieldTag{Path: []string{DISMISS_NESTED}}
+	}
+	return parseTagValue(field.Name, field.Tag.Get(key))
+}
+
+// parseTagValue parses the value of a tag, see parseTag, for the field of the given name.
+func parseTagValue(fieldName, rawString string) FieldTag {
+	tag := FieldTag{Raw: rawString}
+	if rawString == "" || rawString == SKIP_FIELD {
 		tag.Skip = true
 		return tag
 	}
 
-	tagParts := strings.Split(rawString, ",")
-	tag.Path = strings.Split(tagParts[0], ".")
+	tagParts := splitTagParts(rawString)
+	paths := strings.Split(tagParts[0], FALLBACK_SPLIT)
+	for i, path := range paths {
+		segments, path := splitRelativePath(path)
+		if path == SAME_NAME {
+			segments = append(segments, fieldName)
+		} else if path != "" {
+			segments = append(segments, strings.Split(path, ".")...)
+		}
+		if i == 0 {
+			tag.Path = segments
+		} else {
+			tag.Alternatives = append(tag.Alternatives, segments)
+		}
+	}
 
 	if len(tagParts) > 1 {
 		tag.Opts = parseTagOpts(tagParts[1:])
@@ -182,17 +748,194 @@ func parseTag(field reflect.StructField) FieldTag {
 	return tag
 }
 
+// splitRelativePath splits the operators leading a path into segments of their own: the `/` of absolute paths, or
+// a `^` for each `^` or `../` of relative ones, eg `../../Spec.Name` results in "^", "^" and `Spec.Name`.
+//
+// Parameters:
+//   - path: The path as declared by the tag
+//
+// Returns:
+//   - []string: The segments of the operators, empty when the path is resolved under the parent path
+//   - string: The rest of the path
+func splitRelativePath(path string) ([]string, string) {
+	if rest, ok := strings.CutPrefix(path, ABSOLUTE_PATH); ok {
+		return []string{ABSOLUTE_PATH}, rest
+	}
+	segments := []string{}
+	for {
+		rest, ok := strings.CutPrefix(path, PARENT_PATH)
+		if !ok {
+			rest, ok = strings.CutPrefix(path, PARENT_PATH_ALIAS)
+		}
+		if !ok {
+			return segments, path
+		}
+		segments = append(segments, PARENT_PATH)
+		path = rest
+	}
+}
+
+// resolveRelativePath composes the path of a tag with the path inherited from its parent field: absolute paths are
+// resolved from the root of the mapping, see WithRoot, each `^` leading relative paths strips a segment of the
+// parent path, along with the `as<>` assertion following it if any, and any other path is appended to it.
+//
+// Parameters:
+//   - path: The path of the tag, as split by splitRelativePath
+//   - parentPath: The path inherited from the parent field
+//   - alien: The foreign struct type the paths are resolved against
+//   - opts: The options of the Codec requesting the representation
+//
+// Returns:
+//   - []string: The composed path
+//   - error: ErrInvalidRelativePath when the path strips more segments than the parent path has, or segments
+//     leading to collections mapped element by element, whose fields are resolved under each element
+func resolveRelativePath(path, parentPath []string, alien reflect.Type, opts *options) ([]string, error) {
+	base, kept := parentPath, len(parentPath) // segments of the parent path kept
+	if path[0] == ABSOLUTE_PATH {
+		base, kept, path = opts.root, 0, path[1:]
+	}
+	for len(path) > 0 && path[0] == PARENT_PATH {
+		if kept == 0 {
+			return nil, fmt.Errorf("%w no segment of %q left to strip", ErrInvalidRelativePath, strings.Join(parentPath, "."))
+		}
+		kept--
+		if _, ok := parseAssertionSegment(base[kept]); ok {
+			kept-- // assertions are stripped along with the interface field they follow
+		}
+		base, path = base[:kept], path[1:]
+	}
+	composed := append(slices.Clone(base), path...)
+	for i := kept; i < len(parentPath); i++ {
+		stripped, err := resolveTargetField(parentPath[:i+1], alien, opts)
+		if err == nil && (stripped.IsArray || stripped.IsMap) {
+			return nil, fmt.Errorf("%w %v leaves the elements of %v", ErrInvalidRelativePath,
+				strings.Join(composed, "."), strings.Join(parentPath[:i+1], "."))
+		}
+	}
+	return composed, nil
+}
+
+// ParseTag parses the tag of a field found under the given key, `se` by default, just like the mapping does, so
+// tools can inspect tags without introspecting types, eg the static checker of the analyzer package. Use Validate
+// to check the parsed tag.
+func ParseTag(field reflect.StructField, key string) FieldTag {
+	return parseTag(field, key)
+}
+
+// ParseTagValue parses the value of a tag, eg `Metadata.Name,types<Deployment>`, for the field of the given name,
+// the one `=` paths resolve to, just like ParseTag does, for tools reading tags from source code rather than from
+// reflected types.
+func ParseTagValue(fieldName, value string) FieldTag {
+	return parseTagValue(fieldName, value)
+}
+
+var (
+	typeOptsRegex         = regexp.MustCompile(TYPE_OPTS_REGEX)
+	mergeOptsRegex        = regexp.MustCompile(MERGE_OPTS_REGEX)
+	mergeKeyOptsRegex     = regexp.MustCompile(MERGE_KEY_OPTS_REGEX)
+	constOptsRegex        = regexp.MustCompile(CONST_OPTS_REGEX)
+	inOptsRegex           = regexp.MustCompile(IN_OPTS_REGEX)
+	outOptsRegex          = regexp.MustCompile(OUT_OPTS_REGEX)
+	whenOptsRegex         = regexp.MustCompile(WHEN_OPTS_REGEX)
+	kindOptsRegex         = regexp.MustCompile(KIND_OPTS_REGEX)
+	kindFieldOptsRegex    = regexp.MustCompile(KIND_FIELD_OPTS_REGEX)
+	versionOptsRegex      = regexp.MustCompile(VERSION_OPTS_REGEX)
+	versionFieldOptsRegex = regexp.MustCompile(VERSION_FIELD_OPTS_REGEX)
+	joinOptsRegex         = regexp.MustCompile(JOIN_OPTS_REGEX)
+	splitOptsRegex        = regexp.MustCompile(SPLIT_OPTS_REGEX)
+	computeOptsRegex      = regexp.MustCompile(COMPUTE_OPTS_REGEX)
+	convertOptsRegex      = regexp.MustCompile(CONVERT_OPTS_REGEX)
+	timeOptsRegex         = regexp.MustCompile(TIME_OPTS_REGEX)
+	asOptsRegex           = regexp.MustCompile(AS_OPTS_REGEX)
+)
+
 // parseTagOpts parses a list of tag options into a TagOpts struct.
 // The options are expected to be in the format "opt1,opt2,...".
-// The resulting TagOpts will contain a list of TypeMatch structs, one for each type option.
+// The resulting TagOpts will contain a list of TypeMatch structs, one for each type option,
+// the map merge mode set with the `merge<mode>` option, the slice merge key set with the `mergekey<Field>`
+// option, if any, the `const<value>` constant, the `in<path>` and `out<path>` per direction paths, the
+// `when<path=value>` condition, the `kind<>` per-kind paths along with the `kindfield<path>` discriminator, the
+// `version<>` per-version paths along with the `versionfield<path>` API version field, the
+// `join<>` or `split<>` joined fields, the `compute<name>` function, the `convert<name>` converter, the
+// `time<layout>` layout, the `as<>` concrete types,
+// and whether the `preserve`, `required`, `optional`, `base64`, `stringify`, `readonly` and `writeonly`
+// options are set.
 func parseTagOpts(opts []string) TagOpts {
 	options := TagOpts{}
-	matchTypeRegEx := regexp.MustCompile(TYPE_OPTS_REGEX)
 	for _, opt := range opts {
-		typeMatches := matchTypeRegEx.FindStringSubmatch(opt)
+		typeMatches := typeOptsRegex.FindStringSubmatch(opt)
 		if len(typeMatches) > 0 {
 			parseTypeMatches(typeMatches[1], &options.MatchTypes)
 		}
+		if mergeMatches := mergeOptsRegex.FindStringSubmatch(opt); len(mergeMatches) > 0 {
+			options.MapMerge = MapMergeMode(mergeMatches[1])
+		}
+		if keyMatches := mergeKeyOptsRegex.FindStringSubmatch(opt); len(keyMatches) > 0 {
+			options.MergeKey = keyMatches[1]
+		}
+		if constMatches := constOptsRegex.FindStringSubmatch(opt); len(constMatches) > 0 {
+			options.Const = constMatches[1]
+		}
+		if inMatches := inOptsRegex.FindStringSubmatch(opt); len(inMatches) > 0 {
+			options.In = strings.Split(inMatches[1], ".")
+		}
+		if outMatches := outOptsRegex.FindStringSubmatch(opt); len(outMatches) > 0 {
+			options.Out = strings.Split(outMatches[1], ".")
+		}
+		if whenMatches := whenOptsRegex.FindStringSubmatch(opt); len(whenMatches) > 0 {
+			options.When = &FieldCondition{Path: strings.Split(whenMatches[1], "."), Value: whenMatches[2]}
+		}
+		if kindMatches := kindOptsRegex.FindStringSubmatch(opt); len(kindMatches) > 0 {
+			parseTypeMatches(kindMatches[1], &options.Kinds)
+		}
+		if kindFieldMatches := kindFieldOptsRegex.FindStringSubmatch(opt); len(kindFieldMatches) > 0 {
+			options.KindField = strings.Split(kindFieldMatches[1], ".")
+		}
+		if versionMatches := versionOptsRegex.FindStringSubmatch(opt); len(versionMatches) > 0 {
+			parseTypeMatches(versionMatches[1], &options.Versions)
+		}
+		if versionFieldMatches := versionFieldOptsRegex.FindStringSubmatch(opt); len(versionFieldMatches) > 0 {
+			options.VersionField = strings.Split(versionFieldMatches[1], ".")
+		}
+		if joinMatches := joinOptsRegex.FindStringSubmatch(opt); len(joinMatches) > 0 {
+			options.Join, options.JoinError = parseJoinParts(joinMatches[1])
+		}
+		if splitMatches := splitOptsRegex.FindStringSubmatch(opt); len(splitMatches) > 0 {
+			options.Join, options.JoinError = parseSplitParts(splitMatches[1])
+		}
+		if computeMatches := computeOptsRegex.FindStringSubmatch(opt); len(computeMatches) > 0 {
+			options.Compute = computeMatches[1]
+		}
+		if convertMatches := convertOptsRegex.FindStringSubmatch(opt); len(convertMatches) > 0 {
+			options.Convert = convertMatches[1]
+		}
+		if timeMatches := timeOptsRegex.FindStringSubmatch(opt); len(timeMatches) > 0 {
+			options.TimeLayout = timeLayout(timeMatches[1])
+		}
+		if asMatches := asOptsRegex.FindStringSubmatch(opt); len(asMatches) > 0 {
+			options.As = strings.Split(asMatches[1], "|")
+		}
+		if opt == PRESERVE_OPT {
+			options.Preserve = true
+		}
+		if opt == REQUIRED_OPT {
+			options.Required = true
+		}
+		if opt == OPTIONAL_OPT {
+			options.Optional = true
+		}
+		if opt == BASE64_OPT {
+			options.Base64 = true
+		}
+		if opt == STRINGIFY_OPT {
+			options.Stringify = true
+		}
+		if opt == READ_ONLY_OPT {
+			options.ReadOnly = true
+		}
+		if opt == WRITE_ONLY_OPT {
+			options.WriteOnly = true
+		}
 	}
 	return options
 }
@@ -211,51 +954,186 @@ func parseTypeMatches(data string, matches *[]TypeMatch) {
 		if len(typeParts) > 1 {
 			fieldPath = strings.Split(typeParts[1], ".")
 		}
+		var pattern *regexp.Regexp
+		if expr, ok := strings.CutPrefix(typeName, TYPE_PATTERN_PREFIX); ok {
+			pattern, _ = regexp.Compile(expr) // reported when validating the tag
+		}
 		*matches = append(*matches, TypeMatch{
-			Name: typeName,
-			Path: fieldPath,
+			Name:    typeName,
+			Path:    fieldPath,
+			Pattern: pattern,
 		})
 	}
 }
 
+// assertConcreteTypes inserts a segment asserting the concrete type held by each interface field a path traverses,
+// or ends at, for the types named by the `as<>` tag option in order, eg `Object.Spec` and `Deployment` result in
+// `Object.(Deployment).Spec`. Interface fields already followed by an assertion, like the ones inherited from the
+// parent path, and interface collections addressed as a whole are left as they are.
+//
+// Parameters:
+//   - path: The path of the field, composed with the parent path
+//   - alien: The reflect.Type of the foreign struct the path is resolved in
+//   - names: The names of the concrete types, one for each interface field
+//   - opts: The options of the Codec requesting the representation
+//
+// Returns:
+//   - []string: The path holding the assertions
+//   - error: An error wrapping ErrConcreteType when some of the names assert no interface field
+func assertConcreteTypes(path []string, alien reflect.Type, names []string, opts *options) ([]string, error) {
+	asserted := make([]string, 0, len(path)+len(names))
+	for i, segment := range path {
+		asserted = append(asserted, segment)
+		if _, ok := parseAssertionSegment(segment); ok || len(names) == 0 {
+			continue
+		}
+		if i < len(path)-1 {
+			if _, ok := parseAssertionSegment(path[i+1]); ok {
+				continue
+			}
+		}
+		target, err := resolveTargetField(asserted, alien, opts)
+		if err != nil {
+			return append(asserted, path[i+1:]...), nil // the error is reported resolving the whole path
+		}
+		if target.Kind == reflect.Interface && !target.IsArray && !target.IsMap {
+			asserted = append(asserted, ASSERTION_PREFIX+names[0]+ASSERTION_SUFFIX)
+			names = names[1:]
+		}
+	}
+	if len(names) > 0 {
+		return nil, fmt.Errorf("%w %v asserts no interface field of %v", ErrConcreteType, names, strings.Join(path, "."))
+	}
+	return asserted, nil
+}
+
+// parseAssertionSegment returns the name of the concrete type asserted by a path segment, eg "(Deployment)"
+// results in "Deployment", and whether the segment is an assertion.
+func parseAssertionSegment(segment string) (string, bool) {
+	name, ok := strings.CutPrefix(segment, ASSERTION_PREFIX)
+	if !ok {
+		return "", false
+	}
+	return strings.CutSuffix(name, ASSERTION_SUFFIX)
+}
+
+// ResolvePath resolves an `se`-style path, eg `Config.SomeList[0].Config.Direction`, against a foreign struct type
+// just like the mapping resolves the paths of tags, without introspecting any local type nor caching the result.
+//
+// Parameters:
+//   - foreignType: The foreign struct type, or a pointer to it, the path is resolved against
+//   - path: The path to resolve, segments separated by dots
+//   - opts: The options tuning how segments are matched with fields, see WithProtoNames
+//
+// Returns:
+//   - TargetField: The description of the field the path leads to
+//   - error: ErrForeignTypeNotStruct when the type isn't a struct, or a *FieldNotFoundError when the path leads
+//     to no field
+func ResolvePath(foreignType reflect.Type, path string, opts ...Option) (TargetField, error) {
+	if foreignType == nil || derefType(foreignType).Kind() != reflect.Struct {
+		return TargetField{}, ErrForeignTypeNotStruct
+	}
+	target, err := resolveTargetField(strings.Split(path, "."), derefType(foreignType), newOptions(opts...))
+	var notFound *FieldNotFoundError
+	if errors.As(err, &notFound) {
+		notFound.ForeignType = derefType(foreignType).Name()
+	}
+	return target, err
+}
+
 // parseTargetField parses a path in the target structure to locate a specific field and generates
-// a unique reference key for that field. It traverses the target structure recursively following
-// the path components to find the desired field.
+// a unique reference key for that field, see resolveTargetField.
 //
 // Parameters:
 //   - path: A slice of strings representing the path to the target field (e.g., ["Person", "Address", "Street"]).
 //   - foreign: The reflect.Type of the target (foreign) structure to search within.
-//   - fullPath: An optional parameter tracking the full path traversed so far, used in recursive calls.
+//   - opts: The options of the Codec requesting the representation, see WithProtoNames.
 //
 // Returns:
-//   - string: A unique key for the target field that can be used to reference it in the foreignRepresentations map.
+//   - string: A unique key for the target field that can be used to reference it in the cache of foreign fields.
 //   - string: The name of the field's type.
-//   - error: An error if the field cannot be found or if there's an issue during traversal.
+//   - error: An error if the field cannot be found (a *FieldNotFoundError) or if there's an issue during traversal.
+//
+// The resolved field is stored in the cache of the options for later use during the mapping process.
+func parseTargetField(path []string, foreign reflect.Type, opts *options) (string, string, error) {
+	target, err := resolveTargetField(path, foreign, opts)
+	if err != nil {
+		return "", "", err
+	}
+
+	key := opts.cache.getForeignTargetKey(foreign, path)
+	opts.cache.foreigns[key] = target
+
+	return key, target.TypeName, nil
+}
+
+// resolveTargetField traverses the target structure following the path components to find the desired field.
+//
+// Parameters:
+//   - path: A slice of strings representing the path to the target field (e.g., ["Person", "Address", "Street"]).
+//   - foreign: The reflect.Type of the target (foreign) structure to search within.
+//   - opts: The options of the Codec requesting the representation, see WithProtoNames.
+//
+// Returns:
+//   - TargetField: The description of the field the path leads to.
+//   - error: An error if the field cannot be found (a *FieldNotFoundError) or if the path is empty.
 //
 // The function handles various field types including nested structs, arrays, maps, and pointers.
 // It builds both a string path representation and an index path that can be used for direct
-// field access via reflection. The results are stored in the foreignRepresentations map
-// for later use during the mapping process.
-func parseTargetField(path []string, foreign reflect.Type, fullPath ...[]interface{}) (string, string, error) {
+// field access via reflection.
+func resolveTargetField(path []string, foreign reflect.Type, opts *options) (TargetField, error) {
 	descendableFields := []reflect.Kind{reflect.Map, reflect.Array, reflect.Slice}
-	arrayReg := regexp.MustCompile(`^([a-zA-Z0-9_]+)\[0\]$`)
 	if len(path) == 0 {
-		return "", "", errors.New("empty tag path")
-	}
-	if len(fullPath) == 0 {
-		fullPath = [][]interface{}{}
+		return TargetField{}, errors.New("empty tag path")
 	}
 
-	for id := range foreign.NumField() {
-		field := foreign.Field(id)
+	target := TargetField{}
+	current := foreign
+	for depth, segment := range path {
+		if name, ok := parseAssertionSegment(segment); ok {
+			if current.Kind() != reflect.Interface {
+				return TargetField{}, fmt.Errorf("%w %v is not an interface", ErrConcreteType, strings.Join(path[:depth], "."))
+			}
+			id, concrete, err := lookupConcreteType(name, current)
+			if err != nil {
+				return TargetField{}, err
+			}
+			target.Path = append(target.Path, segment)
+			target.IndexPath = append(target.IndexPath, id)
+			target.Elements = append(target.Elements, []int{})
+			current = concrete.Elem()
+			if depth == len(path)-1 {
+				target.Id = id
+				target.Kind = current.Kind()
+				target.TypeName = current.Name()
+				target.Type = concrete
+			}
+			continue
+		}
+		if current.Kind() == reflect.Interface {
+			return TargetField{}, fmt.Errorf("%w %v is a %v", ErrConcreteType, strings.Join(path[:depth], "."), current)
+		}
+		pathName, indexes := parsePathSegment(segment)
+		id, field, found := findForeignField(current, pathName, opts.protoNames)
+		if !found {
+			var embedded []reflect.StructField
+			if id, field, embedded, found = findPromotedField(current, pathName); found {
+				for _, step := range embedded { // the structs the field is promoted through are traversed too
+					target.Path = append(target.Path, step.Name)
+					target.IndexPath = append(target.IndexPath, step.Index[0])
+					target.Elements = append(target.Elements, []int{})
+				}
+			}
+		}
+		if !found {
+			return TargetField{}, &FieldNotFoundError{Path: path[depth:]}
+		}
+		if !field.IsExported() && !field.Anonymous {
+			return TargetField{}, fmt.Errorf("%w %v in %v", ErrUnexportedField, strings.Join(path[:depth+1], "."), foreign)
+		}
+
 		fieldType := field.Type
 		fieldKind := fieldType.Kind()
-		pathName := path[0]
-
-		arrayMatch := arrayReg.FindStringSubmatch(path[0])
-		if len(arrayMatch) > 1 {
-			pathName = arrayMatch[1]
-		}
 		if slices.Contains(descendableFields, fieldKind) {
 			fieldType = fieldType.Elem()
 		}
@@ -263,45 +1141,161 @@ func parseTargetField(path []string, foreign reflect.Type, fullPath ...[]interfa
 			fieldType = fieldType.Elem()
 		}
 
-		if field.Name == pathName {
-			return extractTargetData(id, pathName, path, field, foreign, fieldType, fullPath...)
+		target.Path = append(target.Path, segment)
+		target.IndexPath = append(target.IndexPath, id)
+		target.Elements = append(target.Elements, parseElementIndexes(indexes))
+		wildcard := slices.Equal(indexes, []string{INDEX_ALL})
+		if depth == len(path)-1 {
+			target.Id = id
+			target.Kind = fieldType.Kind()
+			target.TypeName = typeDescription(fieldType)
+			target.Type = field.Type
+			target.IsArray = (fieldKind == reflect.Slice || fieldKind == reflect.Array) && (len(indexes) == 0 || wildcard)
+			target.IsMap = fieldKind == reflect.Map
+			if fieldKind == reflect.Array && target.IsArray {
+				target.ArrayLen = field.Type.Len()
+			}
+		} else if wildcard {
+			target.Wildcard = len(target.IndexPath)
 		}
+		current = unwrapCollections(fieldType)
 	}
 
-	return "", "", fmt.Errorf(ErrForeignTypeMissingField+" %v", path)
+	return target, nil
 }
 
-func extractTargetData(
-	id int,
-	pathName string,
-	path []string,
-	field reflect.StructField,
-	foreign, fieldType reflect.Type,
-	fullPath ...[]interface{},
-) (string, string, error) {
-	if len(path) == 1 {
-		namedPath := []string{}
-		indexPath := []int{}
-		for i := range fullPath {
-			p, ok := fullPath[i][0].(int)
-			if !ok {
-				return "", "", fmt.Errorf("ERROR: Somehow we messed up with index type inference")
-			}
-			indexPath = append(indexPath, p)
-			namedPath = append(namedPath, fmt.Sprintf("%v", fullPath[i][1]))
-		}
-		key := getForeignTargetKey(foreign, field.Name, namedPath)
-		namedPath = append(namedPath, pathName)
-		indexPath = append(indexPath, id)
-		// TODO: maybe namedPath is not needed at all
-		foreignRepresentations[key] = TargetField{
-			Id:        id,
-			Path:      namedPath,
-			Kind:      fieldType.Kind(),
-			IndexPath: indexPath,
-		}
-		return key, fieldType.Name(), nil
-	}
-	fullPath = append(fullPath, []interface{}{id, pathName})
-	return parseTargetField(path[1:], fieldType, fullPath...)
+var (
+	pathSegmentRegex = regexp.MustCompile(`^([a-zA-Z0-9_]+)((?:\[(?:0|-1|last)\])+|\[\*\])$`)
+	pathIndexRegex   = regexp.MustCompile(`\[(0|-1|last|\*)\]`)
+	pathIndexesRegex = regexp.MustCompile(`\[[^\]]*\]`) // any index, supported or not
+)
+
+// parsePathSegment splits a path segment into the field name and the element indexes explicitly requested, one
+// for each collection level, eg "SomeList[0]" results in "SomeList" and ["0"], "Matrix[0][last]" results in
+// "Matrix" and ["0", "last"], while "SomeList[*]" results in "SomeList" and ["*"].
+// Besides `0` and `*`, the last element can be requested with `last` or `-1`. The `*` index can't be combined
+// with other indexes.
+func parsePathSegment(segment string) (string, []string) {
+	arrayMatch := pathSegmentRegex.FindStringSubmatch(segment)
+	if len(arrayMatch) < 3 {
+		return segment, nil
+	}
+	var indexes []string
+	for _, index := range pathIndexRegex.FindAllStringSubmatch(arrayMatch[2], -1) {
+		indexes = append(indexes, index[1])
+	}
+	return arrayMatch[1], indexes
+}
+
+// parseElementIndexes translates the indexes of a path segment into the elements traversed in each collection
+// level, the first one unless the last one was explicitly requested.
+func parseElementIndexes(indexes []string) []int {
+	elements := make([]int, len(indexes))
+	for level, index := range indexes {
+		if index == INDEX_LAST || index == INDEX_LAST_NEGATIVE {
+			elements[level] = lastElement
+		}
+	}
+	return elements
+}
+
+// unwrapCollections returns the type of the values held by a type, going through every level of slices,
+// arrays and pointers, eg `[][]*Item` results in `Item`.
+func unwrapCollections(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Pointer:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// typeDescription returns the name of a type, describing unnamed slices and arrays by their element type so
+// nested collections can be compared, eg `[]string` results in "[]string".
+func typeDescription(t reflect.Type) string {
+	if t.Name() == "" && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		return "[]" + typeDescription(t.Elem())
+	}
+	return t.Name()
+}
+
+// findForeignField looks up a direct field of a struct type by name, returning its index. When protoNames is set,
+// fields whose protobuf schema name matches are found too, see protoFieldNames.
+func findForeignField(foreign reflect.Type, name string, protoNames bool) (int, reflect.StructField, bool) {
+	if foreign.Kind() != reflect.Struct {
+		return 0, reflect.StructField{}, false
+	}
+	for id := range foreign.NumField() {
+		field := foreign.Field(id)
+		if field.Name == name {
+			return id, field, true
+		}
+	}
+	if !protoNames {
+		return 0, reflect.StructField{}, false
+	}
+	for id := range foreign.NumField() {
+		field := foreign.Field(id)
+		if slices.Contains(protoFieldNames(field), name) {
+			return id, field, true
+		}
+	}
+	return 0, reflect.StructField{}, false
+}
+
+// findPromotedField looks up a field promoted through the structs embedded in a foreign struct, following the rules
+// of the Go selectors: the shallowest field of the given name is found, unless several fields share its depth.
+// Fields promoted through pointers to unexported types aren't found, as they can't be allocated.
+//
+// Parameters:
+//   - foreign: The foreign struct type the field is looked up in
+//   - name: The name of the field
+//
+// Returns:
+//   - int: The index of the field in the struct embedding it
+//   - reflect.StructField: The field
+//   - []reflect.StructField: The embedded fields leading to the struct holding the field, outermost first
+//   - bool: Whether the field was found
+func findPromotedField(foreign reflect.Type, name string) (int, reflect.StructField, []reflect.StructField, bool) {
+	if foreign.Kind() != reflect.Struct {
+		return 0, reflect.StructField{}, nil, false
+	}
+	promoted, ok := foreign.FieldByName(name)
+	if !ok || len(promoted.Index) < 2 {
+		return 0, reflect.StructField{}, nil, false
+	}
+	embedded := make([]reflect.StructField, 0, len(promoted.Index)-1)
+	current := foreign
+	for _, id := range promoted.Index[:len(promoted.Index)-1] {
+		step := current.Field(id)
+		if step.Type.Kind() == reflect.Pointer && !step.IsExported() {
+			return 0, reflect.StructField{}, nil, false
+		}
+		embedded = append(embedded, step)
+		current = derefType(step.Type)
+	}
+	id := promoted.Index[len(promoted.Index)-1]
+	return id, current.Field(id), embedded, true
+}
+
+// protoFieldNames returns the names a field of a protoc-gen-go generated struct has in the proto schema: the field name
+// and the protojson name read from the `protobuf` tag, or the oneof name read from the `protobuf_oneof` tag, eg
+// `protobuf:"varint,1,opt,name=min_replicas,json=minReplicas,proto3"` results in "min_replicas" and "minReplicas".
+// The protojson name is only set on the tag when it differs from the field name.
+func protoFieldNames(field reflect.StructField) []string {
+	if oneof, ok := field.Tag.Lookup(PROTOBUF_ONEOF_TAG_KEY); ok {
+		return []string{oneof}
+	}
+	names := []string{}
+	for _, part := range strings.Split(field.Tag.Get(PROTOBUF_TAG_KEY), ",") {
+		if name, ok := strings.CutPrefix(part, "name="); ok {
+			names = append(names, name)
+		}
+		if name, ok := strings.CutPrefix(part, "json="); ok {
+			names = append(names, name)
+		}
+	}
+	return names
 }
@@ -1,37 +1,325 @@
 package pkg
 
 import (
-	"encoding/base64"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
 )
 
-func randString() string {
-	now := strconv.Itoa(int(time.Now().UnixNano()))
-	return base64.StdEncoding.EncodeToString([]byte(now))
+func (this *typeCache) getNativeRepresentationKey(native, alien reflect.Type, field string) string {
+	return fmt.Sprintf("%v:%v~%v", this.typeIdentity(native), field, this.typeIdentity(alien))
 }
 
-func randStringIfEmpty(val string) string {
-	if val == "" {
-		return randString()
+func (this *typeCache) getForeignTargetKey(alien reflect.Type, path []string) string {
+	return fmt.Sprintf("%v:%v", this.typeIdentity(alien), strings.Join(path, "."))
+}
+
+// typeIdentity names a type for the keys of the cache: named types by package, name and fingerprint, unnamed types
+// such as anonymous structs by their kind and fingerprint, so the same anonymous struct always gets the same key.
+func (this *typeCache) typeIdentity(t reflect.Type) string {
+	if t.Name() == "" {
+		return fmt.Sprintf("%v#%v", t.Kind(), this.typeFingerprint(t))
+	}
+	return fmt.Sprintf("%v:%v#%v", t.PkgPath(), t.Name(), this.typeFingerprint(t))
+}
+
+// typeFingerprint computes a hash of the layout of a type, including the name, type and tag of every
+// struct field reachable from it. Types sharing name and package but differing in layout (function scoped
+// types, regenerated code, test doubles) get different fingerprints, preventing stale cached index paths
+// from being used against them.
+//
+// Fingerprints are cached per type as computing them requires walking the whole type.
+func (this *typeCache) typeFingerprint(t reflect.Type) string {
+	this.fingerprintMu.Lock()
+	defer this.fingerprintMu.Unlock()
+	if fingerprint, ok := this.fingerprints[t]; ok {
+		return fingerprint
+	}
+	hash := fnv.New64a()
+	writeTypeLayout(hash, t, map[reflect.Type]bool{})
+	fingerprint := strconv.FormatUint(hash.Sum64(), 36)
+	this.fingerprints[t] = fingerprint
+	return fingerprint
+}
+
+// writeTypeLayout writes a description of a type layout into the provided writer, descending into
+// struct fields and element types. Types already being described are only named to break cycles.
+func writeTypeLayout(w io.Writer, t reflect.Type, visiting map[reflect.Type]bool) {
+	fmt.Fprintf(w, "%v(%v)", t.Kind(), t.String())
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			fmt.Fprintf(w, "{%v`%v`", field.Name, field.Tag)
+			writeTypeLayout(w, field.Type, visiting)
+			fmt.Fprint(w, "}")
+		}
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		if t.Kind() == reflect.Map {
+			writeTypeLayout(w, t.Key(), visiting)
+		}
+		writeTypeLayout(w, t.Elem(), visiting)
+	}
+}
+
+// convertMapKey converts a map key so it can be used as key of a map with a different key type,
+// eg a named string type used as key of a plain string keyed map.
+func convertMapKey(key reflect.Value, to reflect.Type) (reflect.Value, error) {
+	if key.Type() == to {
+		return key, nil
+	}
+	if !mapKeyConvertible(key.Type(), to) {
+		return key, fmt.Errorf("%w map key %v is not %v", ErrForeignTypeMismatch, key.Type(), to)
+	}
+	return key.Convert(to), nil
+}
+
+// mapKeyConvertible reports whether keys of a map can be converted into keys of another map. Only keys of the
+// same class convert, a string into a string or an integer into an integer, leaving out the conversions Go
+// allows between other classes, such as an integer turned into the string of a single rune.
+func mapKeyConvertible(from, to reflect.Type) bool {
+	return from == to || (mapKeyClass(from.Kind()) == mapKeyClass(to.Kind()) && from.ConvertibleTo(to))
+}
+
+// mapKeyClass groups the kinds of map keys that convert into each other without changing their meaning.
+func mapKeyClass(kind reflect.Kind) reflect.Kind {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.Int
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	case reflect.Complex64, reflect.Complex128:
+		return reflect.Complex128
+	}
+	return kind
+}
+
+// newMergedMap allocates the map to be written over the destination map according to the merge mode, holding a
+// copy of the destination entries unless they are to be replaced.
+func newMergedMap(dst reflect.Value, length int, mode MapMergeMode) reflect.Value {
+	if mode == MapReplace || dst.IsNil() {
+		return reflect.MakeMapWithSize(dst.Type(), length)
+	}
+	merged := reflect.MakeMapWithSize(dst.Type(), dst.Len()+length)
+	iter := dst.MapRange()
+	for iter.Next() {
+		merged.SetMapIndex(iter.Key(), iter.Value())
+	}
+	return merged
+}
+
+// assignValue sets a value into a destination field. Collections of different kinds are transferred element by
+// element: a slice is copied into an array as long as it fits, zeroing the remaining array elements, while an
+// array is copied into a newly allocated slice of the same length. Values dereferenced from a pointer are copied
+// into a new pointer when the destination is a pointer too. Structs of a type registered with RegisterCopier are
+// copied with the registered function.
+func assignValue(dst, data reflect.Value) error {
+	if kind := data.Kind(); kind == reflect.Struct || kind == reflect.Pointer {
+		if copier, ok := lookupCopier(derefType(data.Type())); ok && derefType(dst.Type()) == derefType(data.Type()) {
+			copyValue(dst, data, copier)
+			return nil
+		}
+	}
+	switch {
+	case dst.Kind() == reflect.Array && data.Kind() == reflect.Slice:
+		if data.Len() > dst.Len() {
+			return fmt.Errorf("%w %v elements into %v", ErrArrayOverflow, data.Len(), dst.Type())
+		}
+		dst.SetZero()
+		reflect.Copy(dst, data)
+	case dst.Kind() == reflect.Slice && data.Kind() == reflect.Array:
+		slice := reflect.MakeSlice(dst.Type(), data.Len(), data.Len())
+		reflect.Copy(slice, data)
+		dst.Set(slice)
+	case dst.Kind() == reflect.Pointer && data.Type() == dst.Type().Elem():
+		ptr := reflect.New(data.Type()) // data was dereferenced, copy it into a value of its own
+		ptr.Elem().Set(data)
+		dst.Set(ptr)
+	case data.Type() != dst.Type() && isNumeric(data.Type()) && isNumeric(dst.Type()):
+		converted, err := convertNumber(data, dst.Type())
+		if err != nil {
+			return err
+		}
+		dst.Set(converted)
+	default:
+		dst.Set(data)
+	}
+	return nil
+}
+
+// copyValue sets a copy of a struct, or pointer to struct, made with a registered copier into a destination of the
+// same struct type, or pointer to it.
+func copyValue(dst, data reflect.Value, copier func(from, into any)) {
+	if data.Kind() != reflect.Pointer {
+		ptr := reflect.New(data.Type())
+		ptr.Elem().Set(data)
+		data = ptr
+	} else if data.IsNil() {
+		dst.SetZero()
+		return
+	}
+	into := reflect.New(data.Type().Elem())
+	copier(data.Interface(), into.Interface())
+	if dst.Kind() == reflect.Pointer {
+		dst.Set(into)
+	} else {
+		dst.Set(into.Elem())
+	}
+}
+
+// isNumeric reports whether a type holds a single integer or floating point number.
+func isNumeric(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convertNumber converts a number into another numeric type, failing when the value doesn't fit the type, or
+// would lose its fractional part when converted into an integer.
+func convertNumber(value reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	converted := reflect.New(typ).Elem()
+	fits := true
+	switch {
+	case value.CanInt() && converted.CanInt():
+		fits = !converted.OverflowInt(value.Int())
+	case value.CanInt() && converted.CanUint():
+		fits = value.Int() >= 0 && !converted.OverflowUint(uint64(value.Int()))
+	case value.CanUint() && converted.CanInt():
+		fits = value.Uint() <= math.MaxInt64 && !converted.OverflowInt(int64(value.Uint()))
+	case value.CanUint() && converted.CanUint():
+		fits = !converted.OverflowUint(value.Uint())
+	case value.CanFloat() && converted.CanFloat():
+		fits = !converted.OverflowFloat(value.Float())
+	case value.CanFloat():
+		number := value.Float()
+		fits = number == math.Trunc(number) && number >= -math.MaxInt64-1 && number < math.MaxInt64
+		fits = fits && (converted.CanInt() && !converted.OverflowInt(int64(number)) ||
+			converted.CanUint() && number >= 0 && !converted.OverflowUint(uint64(number)))
+	}
+	if !fits {
+		return converted, fmt.Errorf("%w %v does not fit %v", ErrNumericOverflow, value.Interface(), typ)
 	}
-	return val
+	converted.Set(value.Convert(typ))
+	return converted, nil
 }
 
-func getNativeRepresentationKey(native, alien reflect.Type, field string) string {
-	nativePkg := randStringIfEmpty(native.PkgPath())
-	nativeType := randStringIfEmpty(native.Name())
-	alienPkg := randStringIfEmpty(alien.PkgPath())
-	alienType := randStringIfEmpty(alien.Name())
-	return fmt.Sprintf("%v:%v:%v~%v:%v", nativePkg, nativeType, field, alienPkg, alienType)
+// parseConstValue converts the value of a `const<>` tag option into a value of the given type, allocating a new
+// value when the type is a pointer. Only strings, booleans and numbers are supported.
+func parseConstValue(value string, typ reflect.Type) (reflect.Value, error) {
+	if typ.Kind() == reflect.Pointer {
+		elem, err := parseConstValue(value, typ.Elem())
+		if err != nil {
+			return elem, err
+		}
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	}
+
+	result := reflect.New(typ).Elem()
+	var err error
+	switch typ.Kind() {
+	case reflect.String:
+		result.SetString(value)
+	case reflect.Bool:
+		var parsed bool
+		parsed, err = strconv.ParseBool(value)
+		result.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var parsed int64
+		parsed, err = strconv.ParseInt(value, 10, typ.Bits())
+		result.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var parsed uint64
+		parsed, err = strconv.ParseUint(value, 10, typ.Bits())
+		result.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		var parsed float64
+		parsed, err = strconv.ParseFloat(value, typ.Bits())
+		result.SetFloat(parsed)
+	default:
+		err = fmt.Errorf("unsupported type %v", typ)
+	}
+	if err != nil {
+		return result, fmt.Errorf("%w %q for %v: %w", ErrInvalidConstValue, value, typ, err)
+	}
+	return result, nil
+}
+
+// formatScalar formats a string, boolean or number the way parseConstValue parses it back, floats using the
+// shortest decimal representation that parses back to the same value.
+func formatScalar(value reflect.Value) string {
+	switch {
+	case value.Kind() == reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case value.CanInt():
+		return strconv.FormatInt(value.Int(), 10)
+	case value.CanUint():
+		return strconv.FormatUint(value.Uint(), 10)
+	case value.CanFloat():
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits())
+	}
+	return value.String()
+}
+
+// timeLayouts holds the layouts of the time package that can be referenced by name in the `time<layout>` option.
+var timeLayouts = map[string]string{
+	"Layout":      time.Layout,
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+// timeLayout resolves the layout set with the `time<layout>` option, either the name of a layout of the time
+// package or a literal layout.
+func timeLayout(layout string) string {
+	if named, ok := timeLayouts[layout]; ok {
+		return named
+	}
+	return layout
 }
 
-func getForeignTargetKey(alien reflect.Type, field string, path []string) string {
-	alienPkg := randStringIfEmpty(alien.PkgPath())
-	alienType := randStringIfEmpty(alien.Name())
-	pathName := strings.Join(path, ".")
-	return fmt.Sprintf("%v:%v:%v:%v", alienPkg, alienType, pathName, field)
+// elementPath returns the local path of the element of a slice field followed by a dot, eg `Spec.Containers[3].`,
+// built with a single allocation.
+//
+// Parameters:
+//   - prefix: The local path of the struct holding the slice field
+//   - name: The name of the slice field
+//   - index: The index of the element
+//
+// Returns:
+//   - string: The path of the element
+func elementPath(prefix, name string, index int) string {
+	return prefix + name + "[" + strconv.Itoa(index) + "]."
 }
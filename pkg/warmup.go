@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Pair is a pair of local and foreign types to introspect up front, given by a value or pointer of each, see
+// IntrospectAll.
+type Pair struct {
+	Local   interface{}
+	Foreign interface{}
+}
+
+// PairOf returns the Pair of a local type L and a foreign type F.
+func PairOf[L, F any]() Pair {
+	return Pair{Local: new(L), Foreign: new(F)}
+}
+
+// IntrospectAll introspects many pairs of local and foreign types up front, just like calling Introspect for each
+// of them, so services can fail fast at boot instead of on the first request mapping a broken pair.
+// Every pair is introspected even when some of them fail, see Codec.IntrospectAll to introspect them in parallel.
+//
+// Parameters:
+//   - pairs: The pairs of types to introspect
+//
+// Returns:
+//   - error: The errors found introspecting the pairs joined with errors.Join, each one along with the position
+//     and types of the pair that failed, nil when every pair was introspected
+func IntrospectAll(pairs ...Pair) error {
+	return defaultCodec.IntrospectAll(pairs...)
+}
+
+// IntrospectAll introspects many pairs of types up front with the options of the codec, see the package-level
+// IntrospectAll. Codecs created with WithParallelism split the pairs among the given number of goroutines, each
+// introspecting into a cache of its own merged into the cache of the codec once they're done.
+func (this *Codec) IntrospectAll(pairs ...Pair) error {
+	errs := make([]error, len(pairs))
+	workers := min(this.opts.parallelism, len(pairs))
+	if workers <= 1 {
+		for i, pair := range pairs {
+			errs[i] = introspectPair(pair, this.opts)
+		}
+		return joinPairErrors(pairs, errs)
+	}
+
+	caches := make([]*typeCache, workers)
+	wg := sync.WaitGroup{}
+	for w := range workers {
+		opts := *this.opts
+		opts.cache = newTypeCache()
+		caches[w] = opts.cache
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; i < len(pairs); i += workers {
+				errs[i] = introspectPair(pairs[i], &opts)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, cache := range caches {
+		this.opts.cache.merge(cache)
+	}
+	return joinPairErrors(pairs, errs)
+}
+
+// introspectPair introspects a pair of types with the provided options.
+func introspectPair(pair Pair, opts *options) error {
+	if pair.Local == nil {
+		return ErrLocalTypeNotStruct
+	}
+	if pair.Foreign == nil {
+		return ErrForeignTypeNotStruct
+	}
+	repr := &StructRepr{}
+	return repr.introspect(pair.Local, pair.Foreign, opts)
+}
+
+// joinPairErrors joins the errors found introspecting each pair, adding the position and types of the pair.
+func joinPairErrors(pairs []Pair, errs []error) error {
+	failed := []error{}
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Errorf("pair %v (%T, %T): %w", i, pairs[i].Local, pairs[i].Foreign, err))
+		}
+	}
+	return errors.Join(failed...)
+}
@@ -0,0 +1,87 @@
+// Package protoconv teaches the struct mapper to translate between protobuf well-known types held by generated API
+// structs and the plain Go types of internal models. It lives apart from the main package so only the users
+// importing it depend on the protobuf module.
+//
+// Importing the package registers the converters, see pkg.RegisterConverter:
+//
+//	import _ "github.com/ilexPar/struct-marshal/protoconv"
+//
+// Supported conversions:
+//   - `*timestamppb.Timestamp` foreign fields map `time.Time` and `*time.Time` local fields
+//   - wrapper foreign fields, eg `*wrapperspb.StringValue`, map fields holding the wrapped type or a pointer to it,
+//     unwrapping the value when unmarshaling and allocating the wrapper when marshaling
+package protoconv
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func init() {
+	pkg.RegisterConverter(timeToTimestamp, timestampToTime)
+	pkg.RegisterConverter(pointerTo(timeToTimestamp), pointerFrom(timestampToTime))
+
+	registerWrapper(wrapperspb.Double)
+	registerWrapper(wrapperspb.Float)
+	registerWrapper(wrapperspb.Int64)
+	registerWrapper(wrapperspb.UInt64)
+	registerWrapper(wrapperspb.Int32)
+	registerWrapper(wrapperspb.UInt32)
+	registerWrapper(wrapperspb.Bool)
+	registerWrapper(wrapperspb.String)
+	registerWrapper(wrapperspb.Bytes)
+}
+
+// registerWrapper registers the converters between a wrapper type and the type it wraps, or a pointer to it.
+//
+// Parameters:
+//   - wrap: The constructor of the wrapper, eg wrapperspb.String
+func registerWrapper[V any, W interface{ GetValue() V }](wrap func(V) W) {
+	encode := func(value V) (W, error) { return wrap(value), nil }
+	decode := func(wrapper W) (V, error) { return wrapper.GetValue(), nil }
+	pkg.RegisterConverter(encode, decode)
+	pkg.RegisterConverter(pointerTo(encode), pointerFrom(decode))
+}
+
+// timeToTimestamp converts a time into a protobuf timestamp.
+func timeToTimestamp(t time.Time) (*timestamppb.Timestamp, error) {
+	return timestamppb.New(t), nil
+}
+
+// timestampToTime converts a protobuf timestamp into a time, validating it's within the supported range.
+func timestampToTime(ts *timestamppb.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+	if err := ts.CheckValid(); err != nil {
+		return time.Time{}, err
+	}
+	return ts.AsTime(), nil
+}
+
+// pointerTo adapts a conversion from a value so it converts from a pointer to the value, nil pointers converting
+// into the zero value of the result.
+func pointerTo[L, F any](convert func(L) (F, error)) func(*L) (F, error) {
+	return func(value *L) (F, error) {
+		if value == nil {
+			var zero F
+			return zero, nil
+		}
+		return convert(*value)
+	}
+}
+
+// pointerFrom adapts a conversion into a value so it converts into a pointer to the value.
+func pointerFrom[F, L any](convert func(F) (L, error)) func(F) (*L, error) {
+	return func(value F) (*L, error) {
+		converted, err := convert(value)
+		if err != nil {
+			return nil, err
+		}
+		return &converted, nil
+	}
+}
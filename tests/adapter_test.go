@@ -0,0 +1,101 @@
+package pkg_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type APIAdapted struct {
+	Spec struct {
+		Image string
+	}
+}
+
+type SystemAdapted struct {
+	Image string `se:"Spec.Image"`
+}
+
+func TestRegisterAdapter(t *testing.T) {
+	t.Run("should map registered types with the adapter functions", func(t *testing.T) {
+		err := pkg.RegisterAdapter(
+			reflect.TypeFor[SystemAdapted](),
+			reflect.TypeFor[*APIAdapted](),
+			func(from, into any) error {
+				into.(*APIAdapted).Spec.Image = "adapted:" + from.(*SystemAdapted).Image
+				return nil
+			},
+			func(from, into any) error {
+				into.(*SystemAdapted).Image = "adapted:" + from.(*APIAdapted).Spec.Image
+				return nil
+			},
+		)
+		assert.Nil(t, err)
+
+		foreign := APIAdapted{}
+		err = pkg.Marshal(SystemAdapted{Image: "nginx"}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, "adapted:nginx", foreign.Spec.Image)
+
+		local := SystemAdapted{}
+		err = pkg.Unmarshal(foreign, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, "adapted:adapted:nginx", local.Image)
+
+		err = pkg.NewCodec(pkg.WithStrict()).Marshal(SystemAdapted{Image: "nginx"}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, "nginx", foreign.Spec.Image, "options changing the mapping should walk the representation")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should walk the representation when reporting skipped fields", func(t *testing.T) {
+		err := pkg.RegisterAdapter(reflect.TypeFor[SystemAdapted](), reflect.TypeFor[APIAdapted](),
+			func(from, into any) error {
+				into.(*APIAdapted).Spec.Image = "adapted"
+				return nil
+			},
+			nil,
+		)
+		assert.Nil(t, err)
+
+		foreign := APIAdapted{}
+		report, reportErr := pkg.MarshalWithReport(SystemAdapted{}, &foreign)
+		strictErr := pkg.MarshalStrict(SystemAdapted{}, &foreign)
+
+		assert.Nil(t, reportErr)
+		assert.Empty(t, foreign.Spec.Image)
+		_, found := findSkip(report.Skipped, "Image")
+		assert.True(t, found)
+		var strict *pkg.StrictError
+		assert.ErrorAs(t, strictErr, &strict)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should walk the representation for directions without adapter function", func(t *testing.T) {
+		err := pkg.RegisterAdapter(reflect.TypeFor[SystemAdapted](), reflect.TypeFor[APIAdapted](), nil,
+			func(from, into any) error {
+				return errors.New("adapter failed")
+			},
+		)
+		assert.Nil(t, err)
+
+		foreign := APIAdapted{}
+		err = pkg.Marshal(&SystemAdapted{Image: "nginx"}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, "nginx", foreign.Spec.Image)
+
+		err = pkg.Unmarshal(&foreign, &SystemAdapted{})
+		assert.EqualError(t, err, "adapter failed")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail to register types other than structs", func(t *testing.T) {
+		err := pkg.RegisterAdapter(reflect.TypeFor[string](), reflect.TypeFor[APIAdapted](), nil, nil)
+		assert.ErrorIs(t, err, pkg.ErrLocalTypeNotStruct)
+
+		err = pkg.RegisterAdapter(reflect.TypeFor[SystemAdapted](), nil, nil, nil)
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeNotStruct)
+		pkg.ClearTypeCache()
+	})
+}
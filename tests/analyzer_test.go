@@ -0,0 +1,15 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ilexPar/struct-marshal/analyzer"
+)
+
+func TestAnalyzer(t *testing.T) {
+	t.Run("should report the paths and types the mapping would reject", func(t *testing.T) {
+		analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "a")
+	})
+}
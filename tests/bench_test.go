@@ -0,0 +1,59 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+var benchmarkObject = APIObject{
+	Metadata: APIMetadata{NameField: "test", Flag: true},
+	Config: APIConfig{
+		SomeCount: 3,
+		SomeList: []APIListedObj{
+			{List: []string{"a", "b"}, Config: APIListedObjConfig{Direction: "first"}},
+			{Config: APIListedObjConfig{Direction: "second"}},
+		},
+		SomeList2: []*APIListedObj{{Config: APIListedObjConfig{Direction: "pointed"}}},
+	},
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	b.ReportAllocs()
+	for range b.N {
+		dst := SystemStruct{}
+		if err := pkg.Unmarshal(benchmarkObject, &dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+	pkg.ClearTypeCache()
+}
+
+func BenchmarkUnmarshalSlice(b *testing.B) {
+	src := make([]APIObject, 100)
+	for i := range src {
+		src[i] = benchmarkObject
+	}
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := pkg.UnmarshalSlice[SystemStruct](src); err != nil {
+			b.Fatal(err)
+		}
+	}
+	pkg.ClearTypeCache()
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	src := SystemStruct{}
+	if err := pkg.Unmarshal(benchmarkObject, &src); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for range b.N {
+		dst := APIObject{}
+		if err := pkg.Marshal(src, &dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+	pkg.ClearTypeCache()
+}
@@ -0,0 +1,66 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type APISecret struct {
+	Data    []byte
+	Payload []byte
+	Token   string
+}
+
+type SystemSecret struct {
+	Data    string `se:"Data"`
+	Payload string `se:"Payload,base64"`
+	Token   []byte `se:"Token"`
+}
+
+func TestBytesConversion(t *testing.T) {
+	t.Run("should convert between strings and byte slices when unmarshaling", func(t *testing.T) {
+		src := APISecret{Data: []byte("password"), Payload: []byte("hello"), Token: "abc"}
+		dst := SystemSecret{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, SystemSecret{Data: "password", Payload: "aGVsbG8=", Token: []byte("abc")}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should convert between strings and byte slices when marshaling", func(t *testing.T) {
+		dst := APISecret{}
+
+		err := pkg.Marshal(SystemSecret{Data: "password", Payload: "aGVsbG8=", Token: []byte("abc")}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, APISecret{Data: []byte("password"), Payload: []byte("hello"), Token: "abc"}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error on invalid base64 payloads", func(t *testing.T) {
+		err := pkg.Marshal(SystemSecret{Payload: "not base64!"}, &APISecret{})
+
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should compare the converted values", func(t *testing.T) {
+		equal, err := pkg.MappedEqual(SystemSecret{Data: "password"}, APISecret{Data: []byte("password")})
+
+		assert.Nil(t, err)
+		assert.True(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when base64 is set on other fields", func(t *testing.T) {
+		local := struct {
+			Token string `se:"Token,base64"`
+		}{}
+
+		err := pkg.Unmarshal(APISecret{}, &local)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidBase64)
+		pkg.ClearTypeCache()
+	})
+}
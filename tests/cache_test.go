@@ -0,0 +1,244 @@
+package pkg_test
+
+import (
+	"expvar"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestTypeCache(t *testing.T) {
+	t.Run("should not reuse cached representations of same named types with different layout", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: "test", Flag: true}}
+		func() {
+			type LocalStruct struct {
+				Name string `se:"Metadata.NameField"`
+			}
+			dst := LocalStruct{}
+			err := pkg.Unmarshal(src, &dst)
+			assert.Nil(t, err)
+			assert.Equal(t, "test", dst.Name)
+		}()
+		func() {
+			type LocalStruct struct {
+				Flag bool   `se:"Metadata.Flag"`
+				Name string `se:"Metadata.NameField"`
+			}
+			dst := LocalStruct{}
+			err := pkg.Unmarshal(src, &dst)
+			assert.Nil(t, err)
+			assert.Equal(t, "test", dst.Name)
+			assert.True(t, dst.Flag)
+		}()
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not reuse cached foreign paths of same named types with different layout", func(t *testing.T) {
+		type LocalStruct struct {
+			Name string `se:"NameField"`
+		}
+		func() {
+			type APIObject struct {
+				NameField string
+			}
+			dst := APIObject{}
+			err := pkg.Marshal(LocalStruct{Name: "test"}, &dst)
+			assert.Nil(t, err)
+			assert.Equal(t, "test", dst.NameField)
+		}()
+		func() {
+			type APIObject struct {
+				Flag      bool
+				NameField string
+			}
+			dst := APIObject{}
+			err := pkg.Marshal(LocalStruct{Name: "test"}, &dst)
+			assert.Nil(t, err)
+			assert.Equal(t, "test", dst.NameField)
+		}()
+		pkg.ClearTypeCache()
+	})
+	t.Run("should reuse cached representations of anonymous structs", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: "test"}}
+		decode := func() string {
+			dst := struct {
+				Name string `se:"Metadata.NameField"`
+			}{}
+			err := pkg.Unmarshal(src, &dst)
+			assert.Nil(t, err)
+			return dst.Name
+		}
+		assert.Equal(t, "test", decode())
+		usage := pkg.CacheMemoryUsage()
+
+		assert.Equal(t, "test", decode())
+
+		assert.Equal(t, usage, pkg.CacheMemoryUsage())
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not reuse cached representations of anonymous structs with different layout", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: "test", Flag: true}}
+		first := struct {
+			Name string `se:"Metadata.NameField"`
+		}{}
+		second := struct {
+			Flag bool `se:"Metadata.Flag"`
+		}{}
+
+		assert.Nil(t, pkg.Unmarshal(src, &first))
+		assert.Nil(t, pkg.Unmarshal(src, &second))
+
+		assert.Equal(t, "test", first.Name)
+		assert.True(t, second.Flag)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestCacheMemoryUsage(t *testing.T) {
+	t.Run("should report empty usage after clearing the cache", func(t *testing.T) {
+		pkg.ClearTypeCache()
+
+		usage := pkg.CacheMemoryUsage()
+
+		assert.Equal(t, pkg.CacheUsage{}, usage)
+	})
+	t.Run("should report entries and grow with introspected types", func(t *testing.T) {
+		err := pkg.Introspect(SystemNested{}, APIListedObjConfig{})
+		assert.Nil(t, err)
+		small := pkg.CacheMemoryUsage()
+
+		err = pkg.Introspect(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		usage := pkg.CacheMemoryUsage()
+
+		assert.Greater(t, small.LocalEntries, 0)
+		assert.Greater(t, small.ForeignEntries, 0)
+		assert.Greater(t, small.FingerprintEntries, 0)
+		assert.Greater(t, small.ApproxBytes, 0)
+		assert.Greater(t, usage.LocalEntries, small.LocalEntries)
+		assert.Greater(t, usage.ForeignEntries, small.ForeignEntries)
+		assert.Greater(t, usage.ApproxBytes, small.ApproxBytes)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestPrivateCache(t *testing.T) {
+	t.Run("should keep representations apart from the shared cache", func(t *testing.T) {
+		pkg.ClearTypeCache()
+		codec := pkg.NewCodec(pkg.WithPrivateCache())
+
+		dst := APIObject{}
+		err := codec.Marshal(SystemStruct{Name: "test"}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.Greater(t, codec.CacheMemoryUsage().LocalEntries, 0)
+		assert.Equal(t, pkg.CacheUsage{}, pkg.CacheMemoryUsage())
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not be emptied by the package-level ClearTypeCache", func(t *testing.T) {
+		codec := pkg.NewCodec(pkg.WithPrivateCache())
+		err := codec.Marshal(SystemStruct{Name: "test"}, &APIObject{})
+		assert.Nil(t, err)
+		usage := codec.CacheMemoryUsage()
+
+		pkg.ClearTypeCache()
+
+		assert.Equal(t, usage, codec.CacheMemoryUsage())
+		codec.ClearTypeCache()
+		assert.Equal(t, pkg.CacheUsage{}, codec.CacheMemoryUsage())
+	})
+	t.Run("should only empty the shared cache when clearing a codec without a private cache", func(t *testing.T) {
+		private := pkg.NewCodec(pkg.WithPrivateCache())
+		err := private.Marshal(SystemStruct{Name: "test"}, &APIObject{})
+		assert.Nil(t, err)
+		err = pkg.Marshal(SystemStruct{Name: "test"}, &APIObject{})
+		assert.Nil(t, err)
+
+		pkg.NewCodec().ClearTypeCache()
+
+		assert.Equal(t, pkg.CacheUsage{}, pkg.CacheMemoryUsage())
+		assert.Greater(t, private.CacheMemoryUsage().LocalEntries, 0)
+	})
+	t.Run("should describe mappings with the private cache", func(t *testing.T) {
+		repr, err := pkg.Describe(SystemStruct{}, APIObject{}, pkg.WithPrivateCache())
+		assert.Nil(t, err)
+
+		assert.Contains(t, repr.DOT(), "NameField")
+		assert.Equal(t, pkg.CacheUsage{}, pkg.CacheMemoryUsage())
+	})
+}
+
+func TestCacheStats(t *testing.T) {
+	t.Run("should count hits and misses along with the introspection time", func(t *testing.T) {
+		pkg.ClearTypeCache()
+		assert.Equal(t, pkg.CacheStatistics{}, pkg.CacheStats())
+
+		err := pkg.Introspect(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		first := pkg.CacheStats()
+		err = pkg.Introspect(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		second := pkg.CacheStats()
+
+		assert.Greater(t, first.Misses, uint64(0))
+		assert.Greater(t, first.LocalEntries, 0)
+		assert.Greater(t, first.ForeignEntries, 0)
+		assert.Greater(t, first.IntrospectionTime, time.Duration(0))
+		assert.Equal(t, first.Misses, second.Misses)
+		assert.Equal(t, first.Hits+1, second.Hits)
+		assert.Equal(t, first.IntrospectionTime, second.IntrospectionTime)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should notify the observer of introspections missing the cache", func(t *testing.T) {
+		observed := []reflect.Type{}
+		codec := pkg.NewCodec(pkg.WithPrivateCache(), pkg.WithIntrospectionObserver(
+			func(local, foreign reflect.Type, elapsed time.Duration) {
+				observed = append(observed, local, foreign)
+			},
+		))
+
+		assert.Nil(t, codec.Marshal(SystemStruct{Name: "test"}, &APIObject{}))
+		assert.Nil(t, codec.Marshal(SystemStruct{Name: "test"}, &APIObject{}))
+
+		assert.Equal(t, []reflect.Type{reflect.TypeFor[SystemStruct](), reflect.TypeFor[APIObject]()}, observed)
+		assert.Equal(t, uint64(1), codec.CacheStats().Hits)
+	})
+	t.Run("should publish the stats with expvar", func(t *testing.T) {
+		pkg.PublishCacheStats("se_cache_test")
+		err := pkg.Introspect(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+
+		published := expvar.Get("se_cache_test")
+
+		assert.NotNil(t, published)
+		assert.Contains(t, published.String(), `"Misses":`)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestConcurrentCache(t *testing.T) {
+	t.Run("should introspect the same types from concurrent callers with a cold cache", func(t *testing.T) {
+		pkg.ClearTypeCache()
+		src := SystemStruct{Name: "test"}
+		wg := sync.WaitGroup{}
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				foreign := APIObject{}
+				assert.Nil(t, pkg.Marshal(src, &foreign))
+				dst := SystemStruct{}
+				assert.Nil(t, pkg.Unmarshal(foreign, &dst))
+				assert.Equal(t, src.Name, dst.Name)
+				assert.Greater(t, pkg.CacheStats().LocalEntries, 0)
+			}()
+		}
+		wg.Wait()
+		pkg.ClearTypeCache()
+	})
+}
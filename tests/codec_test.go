@@ -0,0 +1,606 @@
+package pkg_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+// Mock a service depending on the translation layer
+type mockMapper struct {
+	marshalCalls int
+	err          error
+}
+
+func (this *mockMapper) Marshal(from interface{}, into interface{}) error {
+	this.marshalCalls++
+	return this.err
+}
+
+func (this *mockMapper) Unmarshal(from interface{}, into interface{}) error {
+	return this.err
+}
+
+// Mock a logging stack keeping every debug message
+type recordingLogger struct {
+	messages []string
+}
+
+func (this *recordingLogger) Debug(msg string, args ...any) {
+	this.messages = append(this.messages, msg)
+}
+
+type apiService struct {
+	mapper pkg.Mapper
+}
+
+func (this apiService) publish(local SystemStruct) (APIObject, error) {
+	dst := APIObject{}
+	err := this.mapper.Marshal(local, &dst)
+	return dst, err
+}
+
+func TestCodec(t *testing.T) {
+	t.Run("should translate structs when injected as a Mapper", func(t *testing.T) {
+		service := apiService{mapper: pkg.NewCodec()}
+
+		dst, err := service.publish(SystemStruct{Name: "test"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should decode like the package-level Unmarshal", func(t *testing.T) {
+		var mapper pkg.Mapper = pkg.NewCodec()
+		dst := SystemStruct{}
+
+		err := mapper.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "test"}}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should allow mocking the translation layer", func(t *testing.T) {
+		mock := &mockMapper{err: errors.New("mocked")}
+		service := apiService{mapper: mock}
+
+		_, err := service.publish(SystemStruct{})
+
+		assert.EqualError(t, err, "mocked")
+		assert.Equal(t, 1, mock.marshalCalls)
+	})
+}
+
+func TestCodecLogging(t *testing.T) {
+	t.Run("should log internal decisions at debug level", func(t *testing.T) {
+		logger := &recordingLogger{}
+		codec := pkg.NewCodec(pkg.WithLogger(logger))
+		dst := SystemStruct{}
+
+		err := codec.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "test"}}, &dst)
+
+		assert.Nil(t, err)
+		assert.Contains(t, logger.messages, "introspection cache miss")
+		assert.Contains(t, logger.messages, "field skipped")
+		assert.Contains(t, logger.messages, "allocating local value")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not log cache misses once the representation is cached", func(t *testing.T) {
+		logger := &recordingLogger{}
+		codec := pkg.NewCodec(pkg.WithLogger(logger))
+		err := pkg.Introspect(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+
+		err = codec.Marshal(SystemStruct{Nested: SystemNested{Direction: "up"}}, &APIObject{})
+
+		assert.Nil(t, err)
+		assert.NotContains(t, logger.messages, "introspection cache miss")
+		assert.Contains(t, logger.messages, "allocating foreign slice")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should accept a slog.Logger", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		codec := pkg.NewCodec(pkg.WithLogger(logger))
+
+		err := codec.Marshal(SystemStruct{Name: "test"}, &APIObject{})
+
+		assert.Nil(t, err)
+		assert.Contains(t, buf.String(), "field skipped")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not build the arguments of debug messages without a logger", func(t *testing.T) {
+		logged := pkg.NewCodec(pkg.WithLogger(&recordingLogger{}))
+		src := APIObject{Metadata: APIMetadata{NameField: "test"}}
+		assert.Nil(t, pkg.Unmarshal(src, &SystemStruct{}))
+
+		unlogged := testing.AllocsPerRun(10, func() { _ = pkg.Unmarshal(src, &SystemStruct{}) })
+		allocs := testing.AllocsPerRun(10, func() { _ = logged.Unmarshal(src, &SystemStruct{}) })
+
+		assert.Less(t, unlogged, allocs)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestCodecZeroValues(t *testing.T) {
+	t.Run("should copy zero values of the configured kinds on unmarshal", func(t *testing.T) {
+		codec := pkg.NewCodec(pkg.WithCopyZero(reflect.Bool, reflect.Int))
+		dst := SystemStruct{Name: "keep", Count: 5, Flag: true}
+
+		err := codec.Unmarshal(APIObject{}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, dst.Count)
+		assert.False(t, dst.Flag)
+		assert.Equal(t, "keep", dst.Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should copy zero values of the configured kinds on marshal", func(t *testing.T) {
+		codec := pkg.NewCodec(pkg.WithCopyZero(reflect.Bool))
+		dst := APIObject{
+			Metadata: APIMetadata{NameField: "keep", Flag: true},
+			Config:   APIConfig{SomeCount: 5},
+		}
+
+		err := codec.Marshal(SystemStruct{}, &dst)
+
+		assert.Nil(t, err)
+		assert.False(t, dst.Metadata.Flag)
+		assert.Equal(t, 5, dst.Config.SomeCount)
+		assert.Equal(t, "keep", dst.Metadata.NameField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should skip every zero value by default", func(t *testing.T) {
+		dst := SystemStruct{Count: 5, Flag: true}
+
+		err := pkg.NewCodec().Unmarshal(APIObject{}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 5, dst.Count)
+		assert.True(t, dst.Flag)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestCodecMapMerge(t *testing.T) {
+	t.Run("should merge map entries using the configured mode", func(t *testing.T) {
+		codec := pkg.NewCodec(pkg.WithMapMerge(pkg.MapMergeOverwrite))
+		dst := SystemMapped{
+			Labels: map[string]string{"app": "old", "local": "kept"},
+		}
+		src := APIMappedObj{}
+		src.Metadata.Labels = map[string]string{"app": "new"}
+
+		err := codec.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"app": "new", "local": "kept"}, dst.Labels)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should use the merge mode set on the tag over the configured one", func(t *testing.T) {
+		type Local struct {
+			Labels map[string]string `se:"Metadata.Labels,merge<keep>"`
+		}
+		codec := pkg.NewCodec(pkg.WithMapMerge(pkg.MapMergeOverwrite))
+		dst := APIMappedObj{}
+		dst.Metadata.Labels = map[string]string{"app": "controller", "owner": "controller"}
+
+		err := codec.Marshal(Local{Labels: map[string]string{"app": "local", "tier": "local"}}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{
+			"app":   "controller",
+			"owner": "controller",
+			"tier":  "local",
+		}, dst.Metadata.Labels)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should replace map entries by default", func(t *testing.T) {
+		dst := APIMappedObj{}
+		dst.Metadata.Labels = map[string]string{"owner": "controller"}
+
+		err := pkg.NewCodec().Marshal(SystemMapped{Labels: map[string]string{"app": "local"}}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"app": "local"}, dst.Metadata.Labels)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error on unknown merge modes", func(t *testing.T) {
+		type Local struct {
+			Labels map[string]string `se:"Metadata.Labels,merge<append>"`
+		}
+
+		err := pkg.Introspect(Local{}, APIMappedObj{})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidMapMergeMode)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestCodecPreservedSlices(t *testing.T) {
+	type Item struct {
+		Direction string `se:"Config.Direction"`
+		State     string
+	}
+	src := APIObject{
+		Config: APIConfig{
+			SomeList: []APIListedObj{
+				{Config: APIListedObjConfig{Direction: "up"}},
+			},
+		},
+	}
+	t.Run("should keep the local slice elements the mapping doesn't touch", func(t *testing.T) {
+		type Local struct {
+			Items []Item `se:"Config.SomeList"`
+		}
+		codec := pkg.NewCodec(pkg.WithPreservedSlices())
+		dst := Local{Items: []Item{{State: "first"}, {Direction: "down", State: "second"}}}
+
+		err := codec.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Item{{Direction: "up", State: "first"}, {Direction: "down", State: "second"}}, dst.Items)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep the local slice elements of fields setting the preserve option", func(t *testing.T) {
+		type Local struct {
+			Items []Item `se:"Config.SomeList[0],preserve"`
+		}
+		dst := Local{Items: []Item{{State: "first"}, {Direction: "down", State: "second"}}}
+
+		err := pkg.NewCodec().Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Item{{Direction: "up", State: "first"}, {Direction: "down", State: "second"}}, dst.Items)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should replace local slices by default", func(t *testing.T) {
+		type Local struct {
+			Items []Item `se:"Config.SomeList"`
+		}
+		dst := Local{Items: []Item{{State: "first"}, {Direction: "down", State: "second"}}}
+
+		err := pkg.NewCodec().Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Item{{Direction: "up"}}, dst.Items)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestCodecAutoMap(t *testing.T) {
+	type AutoListedConfig struct {
+		Direction string
+	}
+	type AutoListed struct {
+		List   []string
+		Config AutoListedConfig
+	}
+	type AutoMetadata struct {
+		NameField string
+		Flag      string // incompatible with the foreign type
+	}
+	type AutoConfig struct {
+		SomeList []AutoListed
+	}
+	type Local struct {
+		Metadata AutoMetadata
+		Config   AutoConfig
+		Total    int    `se:"Config.SomeCount"`
+		Ignored  string `se:"-"`
+		Extra    string // no foreign counterpart
+	}
+	src := APIObject{
+		Metadata: APIMetadata{NameField: "test", Flag: true},
+		Config: APIConfig{
+			SomeCount: 3,
+			SomeList:  []APIListedObj{{List: []string{"a"}, Config: APIListedObjConfig{Direction: "up"}}},
+		},
+	}
+
+	t.Run("should map untagged fields by name", func(t *testing.T) {
+		dst := Local{}
+
+		err := pkg.NewCodec(pkg.WithAutoMap()).Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.Empty(t, dst.Metadata.Flag)
+		assert.Equal(t, 3, dst.Total)
+		assert.Equal(t, []AutoListed{{List: []string{"a"}, Config: AutoListedConfig{Direction: "up"}}}, dst.Config.SomeList)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal untagged fields by name", func(t *testing.T) {
+		local := Local{
+			Metadata: AutoMetadata{NameField: "test", Flag: "yes"},
+			Config:   AutoConfig{SomeList: []AutoListed{{Config: AutoListedConfig{Direction: "down"}}}},
+			Ignored:  "ignored",
+		}
+		dst := APIObject{}
+
+		err := pkg.NewCodec(pkg.WithAutoMap()).Marshal(local, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.False(t, dst.Metadata.Flag)
+		assert.Equal(t, "down", dst.Config.SomeList[0].Config.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report the auto-mapped fields", func(t *testing.T) {
+		fields, err := pkg.NewCodec(pkg.WithAutoMap()).AutoMapped(Local{}, APIObject{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"Config",
+			"Config.SomeList",
+			"Config.SomeList.Config",
+			"Config.SomeList.Config.Direction",
+			"Config.SomeList.List",
+			"Metadata",
+			"Metadata.NameField",
+		}, fields.Paths())
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not auto-map without the option", func(t *testing.T) {
+		dst := Local{}
+
+		err := pkg.NewCodec(pkg.WithAutoMap()).Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		dst = Local{}
+		err = pkg.NewCodec().Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, dst.Total)
+		assert.Empty(t, dst.Metadata.NameField)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestWithOptions(t *testing.T) {
+	t.Run("should unmarshal with the provided options", func(t *testing.T) {
+		dst := SystemStruct{Name: "keep", Flag: true}
+
+		err := pkg.UnmarshalWithOptions(APIObject{}, &dst, pkg.WithCopyZero(reflect.Bool))
+
+		assert.Nil(t, err)
+		assert.False(t, dst.Flag)
+		assert.Equal(t, "keep", dst.Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal with the provided options", func(t *testing.T) {
+		dst := APIObject{Metadata: APIMetadata{Flag: true}}
+
+		err := pkg.MarshalWithOptions(SystemStruct{Name: "test"}, &dst, pkg.WithCopyZero(reflect.Bool))
+
+		assert.Nil(t, err)
+		assert.False(t, dst.Metadata.Flag)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report skipped fields when strict", func(t *testing.T) {
+		dst := SystemStruct{}
+
+		err := pkg.UnmarshalWithOptions(APIObject{Metadata: APIMetadata{NameField: "test"}}, &dst, pkg.WithStrict())
+
+		var strictErr *pkg.StrictError
+		assert.True(t, errors.As(err, &strictErr))
+		assert.Equal(t, "test", dst.Name)
+		_, found := findSkip(strictErr.Skips, "Count")
+		assert.True(t, found)
+
+		err = pkg.MarshalWithOptions(SystemStruct{Name: "test"}, &APIObject{}, pkg.WithStrict())
+		assert.True(t, errors.As(err, &strictErr))
+		pkg.ClearTypeCache()
+	})
+	t.Run("should default to the lenient behavior", func(t *testing.T) {
+		err := pkg.UnmarshalWithOptions(APIObject{Metadata: APIMetadata{NameField: "test"}}, &SystemStruct{})
+		assert.Nil(t, err)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestCodecRoot(t *testing.T) {
+	t.Run("should map under the root path", func(t *testing.T) {
+		codec := pkg.NewCodec(pkg.WithRoot("Spec.Template"))
+		dst := APIWorkload{}
+
+		err := codec.Marshal(SystemPodTemplate{Image: "nginx"}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "nginx", dst.Spec.Template.Image)
+
+		local := SystemPodTemplate{}
+		err = codec.Unmarshal(dst, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, "nginx", local.Image)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep the representations of different roots apart", func(t *testing.T) {
+		dst := APIWorkload{}
+
+		err := pkg.MarshalWithOptions(SystemPodTemplate{Image: "nginx"}, &dst, pkg.WithRoot("Spec.Template"))
+		assert.Nil(t, err)
+		err = pkg.MarshalWithOptions(SystemPodTemplate{Image: "busybox"}, &dst,
+			pkg.WithRoot("Spec.JobTemplate.Spec.Template"))
+		assert.Nil(t, err)
+
+		assert.Equal(t, "nginx", dst.Spec.Template.Image)
+		assert.Equal(t, "busybox", dst.Spec.JobTemplate.Spec.Template.Image)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail when the root path doesn't exist", func(t *testing.T) {
+		err := pkg.MarshalWithOptions(SystemPodTemplate{Image: "nginx"}, &APIWorkload{}, pkg.WithRoot("Spec.Missing"))
+
+		var notFound *pkg.FieldNotFoundError
+		assert.True(t, errors.As(err, &notFound))
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestCodecFieldSelection(t *testing.T) {
+	src := APIObject{
+		Metadata: APIMetadata{NameField: "test", Flag: true},
+		Config: APIConfig{
+			SomeCount: 3,
+			SomeList: []APIListedObj{
+				{List: []string{"a"}, Config: APIListedObjConfig{Direction: "up", DeepNested: APIDeepNested{Direction2: "down"}}},
+			},
+		},
+	}
+
+	t.Run("should map only the selected fields", func(t *testing.T) {
+		dst := SystemStruct{}
+
+		err := pkg.UnmarshalWithOptions(src, &dst, pkg.WithOnly("Name", "Nested.Direction"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		assert.Equal(t, "up", dst.Nested.Direction)
+		assert.Equal(t, "", dst.Nested.DeeepNested.Direction)
+		assert.False(t, dst.Flag)
+		assert.Equal(t, 0, dst.Count)
+		assert.Nil(t, dst.ListedStuff)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should select every field nested in a selected one", func(t *testing.T) {
+		dst := SystemStruct{}
+
+		err := pkg.UnmarshalWithOptions(src, &dst, pkg.WithOnly("Nested", "StructSlice.Direction"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "up", dst.Nested.Direction)
+		assert.Equal(t, "down", dst.Nested.DeeepNested.Direction)
+		assert.Len(t, dst.StructSlice, 1)
+		assert.Equal(t, "", dst.Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should leave excluded fields out", func(t *testing.T) {
+		dst := APIObject{Config: APIConfig{SomeCount: 7}}
+
+		err := pkg.MarshalWithOptions(SystemStruct{Name: "test", Count: 3, Nested: SystemNested{Direction: "up"}}, &dst,
+			pkg.WithExclude("Count", "Nested"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.Equal(t, 7, dst.Config.SomeCount)
+		assert.Nil(t, dst.Config.SomeList)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should give precedence to excluded fields", func(t *testing.T) {
+		dst := SystemStruct{}
+
+		err := pkg.UnmarshalWithOptions(src, &dst, pkg.WithOnly("Nested.Direction", "Name"), pkg.WithExclude("Nested"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		assert.Equal(t, "", dst.Nested.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		err := pkg.UnmarshalWithOptions(src, &SystemStruct{}, pkg.WithOnly("Name", "Nested.Direcion"))
+		assert.ErrorIs(t, err, pkg.ErrUnknownLocalField)
+		assert.ErrorContains(t, err, "Nested.Direcion")
+
+		err = pkg.MarshalWithOptions(SystemStruct{}, &APIObject{}, pkg.WithExclude("Missing"))
+		assert.ErrorIs(t, err, pkg.ErrUnknownLocalField)
+		assert.ErrorContains(t, err, "Missing")
+
+		_, err = pkg.NewMapper(SystemStruct{}, APIObject{}, pkg.WithOnly("StructSlice[0].Direction"))
+		assert.Nil(t, err)
+		pkg.ClearTypeCache()
+	})
+}
+
+type SystemVersioned struct {
+	Name string `se:"Metadata.NameField" se_v2:"Config.SomeList[0].Config.Direction"`
+	Flag bool   `se:"Metadata.Flag"`
+}
+
+func TestCodecTagKey(t *testing.T) {
+	t.Run("should read the mappings from the configured tag key", func(t *testing.T) {
+		codec := pkg.NewCodec(pkg.WithTagKey("se_v2"))
+		dst := APIObject{}
+
+		err := codec.Marshal(SystemVersioned{Name: "test", Flag: true}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Config.SomeList[0].Config.Direction)
+		assert.Equal(t, "", dst.Metadata.NameField)
+		assert.False(t, dst.Metadata.Flag)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep the representations of each tag key apart", func(t *testing.T) {
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "v1", Flag: true},
+			Config:   APIConfig{SomeList: []APIListedObj{{Config: APIListedObjConfig{Direction: "v2"}}}},
+		}
+		v1 := SystemVersioned{}
+		v2 := SystemVersioned{}
+
+		assert.Nil(t, pkg.Unmarshal(src, &v1))
+		assert.Nil(t, pkg.UnmarshalWithOptions(src, &v2, pkg.WithTagKey("se_v2")))
+
+		assert.Equal(t, SystemVersioned{Name: "v1", Flag: true}, v1)
+		assert.Equal(t, SystemVersioned{Name: "v2"}, v2)
+		pkg.ClearTypeCache()
+	})
+}
+
+// Mock a protoc-gen-go generated struct, whose Go field names differ from the proto schema names
+type ProtoScalerSpec struct {
+	MinReplicas int32  `protobuf:"varint,1,opt,name=min_replicas,json=minReplicas,proto3"`
+	Target      string `protobuf:"bytes,2,opt,name=target,proto3"`
+}
+type ProtoScaler struct {
+	Spec *ProtoScalerSpec `protobuf:"bytes,1,opt,name=spec,proto3"`
+}
+
+func TestCodecProtoNames(t *testing.T) {
+	t.Run("should resolve paths by proto and protojson names", func(t *testing.T) {
+		codec := pkg.NewCodec(pkg.WithProtoNames())
+		src := ProtoScaler{Spec: &ProtoScalerSpec{MinReplicas: 2, Target: "web"}}
+		dst := struct {
+			MinReplicas int32  `se:"spec.min_replicas"`
+			Replicas    int32  `se:"spec.minReplicas"`
+			Target      string `se:"Spec.target"`
+		}{}
+
+		err := codec.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int32(2), dst.MinReplicas)
+		assert.Equal(t, int32(2), dst.Replicas)
+		assert.Equal(t, "web", dst.Target)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal into the fields resolved by proto names", func(t *testing.T) {
+		codec := pkg.NewCodec(pkg.WithProtoNames())
+		src := struct {
+			MinReplicas int32 `se:"spec.min_replicas"`
+		}{MinReplicas: 3}
+		dst := ProtoScaler{}
+
+		err := codec.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int32(3), dst.Spec.MinReplicas)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not resolve proto names unless requested", func(t *testing.T) {
+		dst := struct {
+			MinReplicas int32 `se:"spec.min_replicas"`
+		}{}
+
+		err := pkg.Unmarshal(ProtoScaler{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		pkg.ClearTypeCache()
+	})
+}
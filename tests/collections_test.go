@@ -0,0 +1,277 @@
+package pkg_test
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestMarshalSlice(t *testing.T) {
+	t.Run("should encode every element of the slice", func(t *testing.T) {
+		src := []SystemStruct{
+			{Name: "first", Count: 1},
+			{Name: "second", Flag: true},
+			{Name: "third", Nested: SystemNested{Direction: "up"}},
+		}
+
+		dst, err := pkg.MarshalSlice[SystemStruct, APIObject](src)
+
+		assert.Nil(t, err)
+		assert.Len(t, dst, len(src))
+		assert.Equal(t, "first", dst[0].Metadata.NameField)
+		assert.Equal(t, 1, dst[0].Config.SomeCount)
+		assert.Equal(t, "second", dst[1].Metadata.NameField)
+		assert.True(t, dst[1].Metadata.Flag)
+		assert.False(t, dst[0].Metadata.Flag)
+		assert.Equal(t, "up", dst[2].Config.SomeList[0].Config.Direction)
+		assert.Nil(t, dst[0].Config.SomeList)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return an empty slice when source is empty", func(t *testing.T) {
+		dst, err := pkg.MarshalSlice[SystemStruct, APIObject](nil)
+
+		assert.Nil(t, err)
+		assert.Empty(t, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when the types can't be mapped", func(t *testing.T) {
+		_, err := pkg.MarshalSlice[SystemStruct, string]([]SystemStruct{{}})
+
+		assert.NotNil(t, err)
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestUnmarshalSlice(t *testing.T) {
+	t.Run("should decode every element of the slice", func(t *testing.T) {
+		src := []APIObject{
+			{Metadata: APIMetadata{NameField: "first"}, Config: APIConfig{SomeCount: 1}},
+			{Metadata: APIMetadata{NameField: "second", Flag: true}},
+		}
+
+		dst, err := pkg.UnmarshalSlice[SystemStruct](src)
+
+		assert.Nil(t, err)
+		assert.Len(t, dst, len(src))
+		assert.Equal(t, "first", dst[0].Name)
+		assert.Equal(t, 1, dst[0].Count)
+		assert.Equal(t, "second", dst[1].Name)
+		assert.True(t, dst[1].Flag)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when the types can't be mapped", func(t *testing.T) {
+		_, err := pkg.UnmarshalSlice[string]([]APIObject{{}})
+
+		assert.NotNil(t, err)
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should map large batches in parallel", func(t *testing.T) {
+		src := make([]SystemStruct, 1000)
+		for i := range src {
+			src[i] = SystemStruct{Count: i + 1, Nested: SystemNested{Direction: "up"}}
+		}
+
+		foreign, err := pkg.MarshalSlice[SystemStruct, APIObject](src, pkg.WithParallelism(8))
+		assert.Nil(t, err)
+		local, err := pkg.UnmarshalSlice[SystemStruct](foreign, pkg.WithParallelism(8))
+		assert.Nil(t, err)
+		assert.Len(t, local, len(src))
+		for i := range src {
+			assert.Equal(t, src[i].Count, local[i].Count)
+			assert.Equal(t, "up", local[i].Nested.Direction)
+		}
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report the first failing element", func(t *testing.T) {
+		type Local struct {
+			Name string `se:"Metadata.NameField"`
+			Flag bool   `se:"Metadata.Flag"`
+		}
+		src := []APIObject{
+			{Metadata: APIMetadata{NameField: "first", Flag: true}},
+			{Metadata: APIMetadata{NameField: "second"}},
+			{Metadata: APIMetadata{NameField: "third"}},
+		}
+
+		_, err := pkg.UnmarshalSlice[Local](src, pkg.WithStrict(), pkg.WithParallelism(3))
+
+		assert.ErrorContains(t, err, "element 1:")
+		assert.ErrorIs(t, err, pkg.ErrStrictSkippedFields)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestMapHelpers(t *testing.T) {
+	t.Run("should encode and decode every value of the map", func(t *testing.T) {
+		src := map[string]SystemStruct{
+			"first":  {Name: "first", Count: 1},
+			"second": {Name: "second", Flag: true},
+		}
+
+		foreign, err := pkg.MarshalMap[SystemStruct, APIObject](src)
+		assert.Nil(t, err)
+		assert.Len(t, foreign, len(src))
+		assert.Equal(t, 1, foreign["first"].Config.SomeCount)
+		assert.True(t, foreign["second"].Metadata.Flag)
+
+		local, err := pkg.UnmarshalMap[SystemStruct](foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, slices.Sorted(maps.Keys(src)), slices.Sorted(maps.Keys(local)))
+		assert.Equal(t, "first", local["first"].Name)
+		assert.Equal(t, 1, local["first"].Count)
+		assert.True(t, local["second"].Flag)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return an empty map when source is empty", func(t *testing.T) {
+		dst, err := pkg.UnmarshalMap[SystemStruct, int, APIObject](nil)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, dst)
+		assert.Empty(t, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when the types can't be mapped", func(t *testing.T) {
+		_, err := pkg.MarshalMap[SystemStruct, string](map[int]SystemStruct{1: {}})
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+
+		_, err = pkg.UnmarshalMap[SystemStruct](map[string]APIObject{"bad": {}}, pkg.WithStrict())
+		assert.ErrorContains(t, err, "key bad:")
+		assert.ErrorIs(t, err, pkg.ErrStrictSkippedFields)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestUnmarshalSeq(t *testing.T) {
+	sources := []APIObject{
+		{Metadata: APIMetadata{NameField: "first"}},
+		{Metadata: APIMetadata{NameField: "second"}},
+		{Metadata: APIMetadata{NameField: "third"}},
+	}
+
+	t.Run("should lazily decode every value of the iterator", func(t *testing.T) {
+		names := []string{}
+		for local, err := range pkg.UnmarshalSeq[SystemStruct](slices.Values(sources)) {
+			assert.Nil(t, err)
+			names = append(names, local.Name)
+		}
+
+		assert.Equal(t, []string{"first", "second", "third"}, names)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should stop consuming the source when the caller breaks", func(t *testing.T) {
+		consumed := 0
+		src := func(yield func(APIObject) bool) {
+			for _, obj := range sources {
+				consumed++
+				if !yield(obj) {
+					return
+				}
+			}
+		}
+
+		for range pkg.UnmarshalSeq[SystemStruct](src) {
+			break
+		}
+
+		assert.Equal(t, 1, consumed)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should yield the error and stop when decoding fails", func(t *testing.T) {
+		var nilSrc *APIObject
+		errs := []error{}
+		for _, err := range pkg.UnmarshalSeq[SystemStruct](slices.Values([]*APIObject{{}, nilSrc, {}})) {
+			errs = append(errs, err)
+		}
+
+		assert.Len(t, errs, 2)
+		assert.Nil(t, errs[0])
+		assert.ErrorIs(t, errs[1], pkg.ErrUnmarshalSrcType)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestUnmarshalSeq2(t *testing.T) {
+	t.Run("should decode every value preserving keys", func(t *testing.T) {
+		sources := map[string]APIObject{
+			"a": {Metadata: APIMetadata{NameField: "first"}},
+			"b": {Metadata: APIMetadata{NameField: "second"}},
+		}
+
+		seq, errf := pkg.UnmarshalSeq2[SystemStruct](maps.All(sources))
+		locals := maps.Collect(seq)
+
+		assert.Nil(t, errf())
+		assert.Len(t, locals, 2)
+		assert.Equal(t, "first", locals["a"].Name)
+		assert.Equal(t, "second", locals["b"].Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should stop and report decoding errors", func(t *testing.T) {
+		seq, errf := pkg.UnmarshalSeq2[SystemStruct](slices.All([]string{"not", "structs"}))
+		locals := maps.Collect(seq)
+
+		assert.Empty(t, locals)
+		assert.ErrorIs(t, errf(), pkg.ErrUnmarshalSrcType)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestParallelSlices(t *testing.T) {
+	t.Run("should map the elements of large slice fields concurrently", func(t *testing.T) {
+		src := SystemStruct{Name: "test", StructSlice: make([]SystemNestedFromSlice, 1000)}
+		for i := range src.StructSlice {
+			src.StructSlice[i].Direction = fmt.Sprint(i)
+		}
+		codec := pkg.NewCodec(pkg.WithParallelSlices(4, 100))
+
+		parallel := APIObject{}
+		assert.Nil(t, codec.Marshal(src, &parallel))
+		sequential := APIObject{}
+		assert.Nil(t, pkg.Marshal(src, &sequential))
+		assert.Equal(t, sequential, parallel)
+
+		dst := SystemStruct{}
+		assert.Nil(t, codec.Unmarshal(parallel, &dst))
+		assert.Equal(t, src.StructSlice, dst.StructSlice)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report skipped elements in order", func(t *testing.T) {
+		type Local struct {
+			Directions []string `se:"Config.SomeList[*].Config.Direction"`
+		}
+		src := Local{Directions: make([]string, 100)}
+		for i := range src.Directions {
+			if i%3 != 0 {
+				src.Directions[i] = "up"
+			}
+		}
+
+		sequential := pkg.NewCodec(pkg.WithStrict()).Marshal(src, &APIObject{})
+		parallel := pkg.NewCodec(pkg.WithStrict(), pkg.WithParallelSlices(8, 10)).Marshal(src, &APIObject{})
+
+		var sequentialErr, parallelErr *pkg.StrictError
+		assert.True(t, errors.As(sequential, &sequentialErr))
+		assert.True(t, errors.As(parallel, &parallelErr))
+		assert.Len(t, parallelErr.Skips, 34)
+		assert.Equal(t, sequentialErr.Skips, parallelErr.Skips)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should map small slices sequentially", func(t *testing.T) {
+		src := SystemStruct{StructSlice: []SystemNestedFromSlice{{Direction: "up"}, {Direction: "down"}}}
+		foreign := APIObject{}
+
+		err := pkg.NewCodec(pkg.WithParallelSlices(4, 100)).Marshal(src, &foreign)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "down", foreign.Config.SomeList[1].Config.Direction)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,118 @@
+package pkg_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func init() {
+	pkg.RegisterFunc("describe", func(foreign any) (any, error) {
+		obj := foreign.(APIObject)
+		return fmt.Sprintf("%v (%v)", obj.Metadata.NameField, obj.Config.SomeCount), nil
+	})
+	pkg.RegisterFunc("countLabel", func(foreign any) (any, error) {
+		return strconv.Itoa(foreign.(int)), nil
+	})
+	pkg.RegisterInverseFunc("countLabel", func(local any, foreign any) error {
+		count, err := strconv.Atoi(local.(string))
+		if err != nil {
+			return err
+		}
+		*foreign.(*int) = count
+		return nil
+	})
+	pkg.RegisterFunc("upperName", func(foreign any) (any, error) {
+		return fmt.Sprintf("NAME:%v", foreign.(APIMetadata).NameField), nil
+	})
+	pkg.RegisterFunc("failing", func(foreign any) (any, error) {
+		return nil, errors.New("boom")
+	})
+	pkg.RegisterFunc("wrongType", func(foreign any) (any, error) {
+		return 42, nil
+	})
+}
+
+type SystemComputedMetadata struct {
+	Label string `se:"+,compute<upperName>"`
+}
+
+type SystemComputed struct {
+	Description string                 `se:"+,compute<describe>"`
+	CountLabel  string                 `se:"Config.SomeCount,compute<countLabel>"`
+	Metadata    SystemComputedMetadata `se:"Metadata"`
+}
+
+func TestComputedFields(t *testing.T) {
+	t.Run("should derive the fields from the foreign struct when unmarshaling", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: "test"}, Config: APIConfig{SomeCount: 3}}
+		dst := SystemComputed{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test (3)", dst.Description)
+		assert.Equal(t, "3", dst.CountLabel)
+		assert.Equal(t, "NAME:test", dst.Metadata.Label)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should only encode the fields with an inverse function", func(t *testing.T) {
+		src := SystemComputed{Description: "ignored", CountLabel: "7", Metadata: SystemComputedMetadata{Label: "ignored"}}
+		dst := APIObject{Metadata: APIMetadata{NameField: "kept"}}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, APIObject{Metadata: APIMetadata{NameField: "kept"}, Config: APIConfig{SomeCount: 7}}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report errors of the inverse function", func(t *testing.T) {
+		err := pkg.Marshal(SystemComputed{CountLabel: "seven"}, &APIObject{})
+
+		assert.ErrorIs(t, err, pkg.ErrComputeFunc)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should compare the computed fields with an inverse function", func(t *testing.T) {
+		dst := APIObject{Config: APIConfig{SomeCount: 3}}
+
+		equal, err := pkg.MappedEqual(SystemComputed{Description: "ignored", CountLabel: "3"}, dst)
+		assert.Nil(t, err)
+		assert.True(t, equal)
+
+		equal, err = pkg.MappedEqual(SystemComputed{CountLabel: "4"}, dst)
+		assert.Nil(t, err)
+		assert.False(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error on invalid functions", func(t *testing.T) {
+		unknown := struct {
+			Name string `se:"+,compute<unknown>"`
+		}{}
+		err := pkg.Unmarshal(APIObject{}, &unknown)
+		assert.ErrorIs(t, err, pkg.ErrUnknownFunc)
+
+		failing := struct {
+			Name string `se:"+,compute<failing>"`
+		}{}
+		err = pkg.Unmarshal(APIObject{}, &failing)
+		assert.ErrorContains(t, err, "boom")
+
+		wrongType := struct {
+			Name string `se:"+,compute<wrongType>"`
+		}{}
+		err = pkg.Unmarshal(APIObject{}, &wrongType)
+		assert.ErrorIs(t, err, pkg.ErrComputeFunc)
+
+		constant := struct {
+			Name string `se:"Metadata.NameField,compute<describe>,const<test>"`
+		}{}
+		err = pkg.Unmarshal(APIObject{}, &constant)
+		assert.ErrorIs(t, err, pkg.ErrConflictingCompute)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,103 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemConditional struct {
+	Name  string `se:"Metadata.NameField"`
+	Count int    `se:"Config.SomeCount,when<Metadata.NameField=counted>"`
+	Items []struct {
+		Direction string   `se:"Config.Direction"`
+		List      []string `se:"List,when<Config.Direction=up>"`
+	} `se:"Config.SomeList"`
+}
+
+func TestConditionalMapping(t *testing.T) {
+	t.Run("should only unmarshal fields whose condition holds", func(t *testing.T) {
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "counted"},
+			Config: APIConfig{
+				SomeCount: 3,
+				SomeList: []APIListedObj{
+					{List: []string{"a"}, Config: APIListedObjConfig{Direction: "up"}},
+					{List: []string{"b"}, Config: APIListedObjConfig{Direction: "down"}},
+				},
+			},
+		}
+		dst := SystemConditional{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, dst.Count)
+		assert.Len(t, dst.Items, 2)
+		assert.Equal(t, []string{"a"}, dst.Items[0].List)
+		assert.Empty(t, dst.Items[1].List)
+
+		src.Metadata.NameField = "other"
+		dst = SystemConditional{}
+		err = pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "other", dst.Name)
+		assert.Equal(t, 0, dst.Count)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should evaluate the condition against the foreign object when marshaling", func(t *testing.T) {
+		src := SystemConditional{Name: "counted", Count: 3}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, dst.Config.SomeCount)
+
+		src.Name = "other"
+		dst = APIObject{}
+		err = pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, dst.Config.SomeCount)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should compare non string foreign fields", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"Metadata.NameField,when<Metadata.Flag=true>"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "test"}}, &dst)
+		assert.Nil(t, err)
+		assert.Empty(t, dst.Name)
+
+		err = pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "test", Flag: true}}, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not hold when the compared field can't be reached", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"Metadata.NameField,when<Config.SomePointed.Config.Direction=up>"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "test"}}, &dst)
+
+		assert.Nil(t, err)
+		assert.Empty(t, dst.Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when the condition compares a collection", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"Metadata.NameField,when<Config.SomeList=up>"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidCondition)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,99 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemConst struct {
+	Name  string `se:"Metadata.NameField,const<v1>"`
+	Count int    `se:"Config.SomeCount,const<3>"`
+	Flag  bool   `se:"Metadata.Flag"`
+}
+
+func TestConstFields(t *testing.T) {
+	t.Run("should always marshal the constant value", func(t *testing.T) {
+		src := SystemConst{Name: "ignored", Flag: true}
+		dst := APIObject{Metadata: APIMetadata{NameField: "existing"}}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "v1", dst.Metadata.NameField)
+		assert.Equal(t, 3, dst.Config.SomeCount)
+		assert.True(t, dst.Metadata.Flag)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal constants into slice elements", func(t *testing.T) {
+		src := struct {
+			Items []struct {
+				Direction string `se:"Config.Direction,const<up>"`
+			} `se:"Config.SomeList"`
+		}{}
+		src.Items = make([]struct {
+			Direction string `se:"Config.Direction,const<up>"`
+		}, 2)
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Len(t, dst.Config.SomeList, 2)
+		assert.Equal(t, "up", dst.Config.SomeList[1].Config.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should ignore constant fields when unmarshaling", func(t *testing.T) {
+		dst := SystemConst{Name: "local"}
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "v2", Flag: true},
+			Config:   APIConfig{SomeCount: 5},
+		}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "local", dst.Name)
+		assert.Equal(t, 0, dst.Count)
+		assert.True(t, dst.Flag)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail when the constant doesn't fit the field type", func(t *testing.T) {
+		src := struct {
+			Count int `se:"Config.SomeCount,const<three>"`
+		}{}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidConstValue)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail when the constant is set on a struct field", func(t *testing.T) {
+		src := struct {
+			Nested SystemNested `se:"Config.SomeList[0].Config,const<up>"`
+		}{}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidConstValue)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should compare constants with the foreign values", func(t *testing.T) {
+		src := SystemConst{Name: "ignored"}
+		dst := APIObject{Metadata: APIMetadata{NameField: "v1"}, Config: APIConfig{SomeCount: 3}}
+
+		equal, err := pkg.MappedEqual(src, dst)
+		assert.Nil(t, err)
+		assert.True(t, equal)
+
+		dst.Config.SomeCount = 4
+		equal, err = pkg.MappedEqual(src, dst)
+		assert.Nil(t, err)
+		assert.False(t, equal)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,191 @@
+package pkg_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemLevel int
+
+const (
+	LevelUnknown SystemLevel = iota
+	LevelLow
+	LevelHigh
+)
+
+var levelNames = map[SystemLevel]string{LevelLow: "low", LevelHigh: "high"}
+
+func init() {
+	pkg.RegisterNamedConverter("level",
+		func(level SystemLevel) (string, error) {
+			if name, ok := levelNames[level]; ok {
+				return name, nil
+			}
+			return "", fmt.Errorf("unknown level %v", int(level))
+		},
+		func(name string) (SystemLevel, error) {
+			for level, levelName := range levelNames {
+				if levelName == name {
+					return level, nil
+				}
+			}
+			return LevelUnknown, fmt.Errorf("unknown level %q", name)
+		},
+	)
+}
+
+type SystemConverted struct {
+	Level SystemLevel `se:"Metadata.NameField,convert<level>"`
+	Count int         `se:"Config.SomeCount"`
+}
+
+func TestConvertedFields(t *testing.T) {
+	t.Run("should convert the foreign value when unmarshaling", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: "high"}, Config: APIConfig{SomeCount: 2}}
+		dst := SystemConverted{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, SystemConverted{Level: LevelHigh, Count: 2}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should convert the local value when marshaling", func(t *testing.T) {
+		dst := APIObject{}
+
+		err := pkg.Marshal(SystemConverted{Level: LevelLow}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "low", dst.Metadata.NameField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should skip zero values", func(t *testing.T) {
+		dst := SystemConverted{Level: LevelLow}
+
+		err := pkg.Unmarshal(APIObject{}, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, LevelLow, dst.Level)
+
+		foreign := APIObject{Metadata: APIMetadata{NameField: "kept"}}
+		err = pkg.Marshal(SystemConverted{}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, "kept", foreign.Metadata.NameField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report conversion errors", func(t *testing.T) {
+		err := pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "medium"}}, &SystemConverted{})
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+		assert.ErrorContains(t, err, "Level")
+
+		err = pkg.Marshal(SystemConverted{Level: 7}, &APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should compare the converted value", func(t *testing.T) {
+		dst := APIObject{Metadata: APIMetadata{NameField: "high"}}
+
+		equal, err := pkg.MappedEqual(SystemConverted{Level: LevelHigh}, dst)
+		assert.Nil(t, err)
+		assert.True(t, equal)
+
+		equal, err = pkg.MappedEqual(SystemConverted{Level: LevelLow}, dst)
+		assert.Nil(t, err)
+		assert.False(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error on invalid converters", func(t *testing.T) {
+		unknown := struct {
+			Level SystemLevel `se:"Metadata.NameField,convert<unknown>"`
+		}{}
+		err := pkg.Unmarshal(APIObject{}, &unknown)
+		assert.ErrorIs(t, err, pkg.ErrUnknownConverter)
+
+		wrongLocal := struct {
+			Level int `se:"Metadata.NameField,convert<level>"`
+		}{}
+		err = pkg.Unmarshal(APIObject{}, &wrongLocal)
+		assert.ErrorIs(t, err, pkg.ErrConverterTypeMismatch)
+
+		wrongForeign := struct {
+			Level SystemLevel `se:"Config.SomeCount,convert<level>"`
+		}{}
+		err = pkg.Unmarshal(APIObject{}, &wrongForeign)
+		assert.ErrorIs(t, err, pkg.ErrConverterTypeMismatch)
+		pkg.ClearTypeCache()
+	})
+}
+
+func init() {
+	pkg.RegisterConverter(
+		func(t time.Time) (string, error) { return t.Format(time.RFC3339), nil },
+		func(s string) (time.Time, error) { return time.Parse(time.RFC3339, s) },
+	)
+}
+
+type SystemTimestamped struct {
+	Created time.Time `se:"Metadata.NameField"`
+	Count   int       `se:"Config.SomeCount"`
+}
+
+func TestTypePairConverters(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	t.Run("should convert fields whose types match a registered pair", func(t *testing.T) {
+		foreign := APIObject{}
+		err := pkg.Marshal(SystemTimestamped{Created: created, Count: 1}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, "2024-05-01T10:30:00Z", foreign.Metadata.NameField)
+
+		local := SystemTimestamped{}
+		err = pkg.Unmarshal(foreign, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, SystemTimestamped{Created: created, Count: 1}, local)
+
+		equal, err := pkg.MappedEqual(local, foreign)
+		assert.Nil(t, err)
+		assert.True(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report conversion errors", func(t *testing.T) {
+		err := pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "yesterday"}}, &SystemTimestamped{})
+
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should still require matching types without a registered pair", func(t *testing.T) {
+		local := struct {
+			Timeout time.Duration `se:"Metadata.NameField"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{}, &local)
+
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMismatch)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should apply converters registered after introspecting the types they convert", func(t *testing.T) {
+		type Version struct {
+			Major int
+		}
+		local := struct {
+			Version Version `se:"Config.SomeCount"`
+		}{}
+		err := pkg.Unmarshal(APIObject{Config: APIConfig{SomeCount: 2}}, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, Version{}, local.Version)
+
+		pkg.RegisterConverter(
+			func(v Version) (int, error) { return v.Major, nil },
+			func(n int) (Version, error) { return Version{Major: n}, nil },
+		)
+		err = pkg.Unmarshal(APIObject{Config: APIConfig{SomeCount: 2}}, &local)
+
+		assert.Nil(t, err)
+		assert.Equal(t, Version{Major: 2}, local.Version)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,120 @@
+package pkg_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+// Mock the named hook types declared by the mapstructure package
+type (
+	DecodeHookFuncType  func(reflect.Type, reflect.Type, interface{}) (interface{}, error)
+	DecodeHookFuncKind  func(reflect.Kind, reflect.Kind, interface{}) (interface{}, error)
+	DecodeHookFuncValue func(from reflect.Value, to reflect.Value) (interface{}, error)
+)
+
+type HookTimeout time.Duration
+type HookPriority int
+
+// stringToTimeoutHook mocks the mapstructure.StringToTimeDurationHookFunc hook
+func stringToTimeoutHook() DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeFor[HookTimeout]() {
+			return data, nil
+		}
+		duration, err := time.ParseDuration(data.(string))
+		return HookTimeout(duration), err
+	}
+}
+
+// priorityHook converts between numbers and strings in both directions
+func priorityHook() DecodeHookFuncKind {
+	return func(from reflect.Kind, to reflect.Kind, data interface{}) (interface{}, error) {
+		switch {
+		case from == reflect.String && to == reflect.Int:
+			priority, err := strconv.Atoi(data.(string))
+			return HookPriority(priority), err
+		case from == reflect.Int && to == reflect.String:
+			return strconv.Itoa(int(data.(HookPriority))), nil
+		}
+		return data, nil
+	}
+}
+
+func init() {
+	decode, err := pkg.FromDecodeHook[string, HookTimeout](stringToTimeoutHook())
+	if err != nil {
+		panic(err)
+	}
+	pkg.RegisterConverter(func(timeout HookTimeout) (string, error) { return time.Duration(timeout).String(), nil }, decode)
+	if err := pkg.RegisterDecodeHook[HookPriority, string](priorityHook()); err != nil {
+		panic(err)
+	}
+}
+
+func TestDecodeHooks(t *testing.T) {
+	t.Run("should convert with a hook adapted into a converter", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: "1m30s"}}
+		dst := struct {
+			Timeout HookTimeout `se:"Metadata.NameField"`
+		}{}
+
+		err := pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, HookTimeout(90*time.Second), dst.Timeout)
+
+		out := APIObject{}
+		err = pkg.Marshal(dst, &out)
+		assert.Nil(t, err)
+		assert.Equal(t, "1m30s", out.Metadata.NameField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should convert both directions with a registered hook", func(t *testing.T) {
+		src := struct {
+			Priority HookPriority `se:"Metadata.NameField"`
+		}{Priority: 7}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "7", dst.Metadata.NameField)
+
+		src.Priority = 0
+		err = pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "3"}}, &src)
+		assert.Nil(t, err)
+		assert.Equal(t, HookPriority(3), src.Priority)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should adapt hooks taking values", func(t *testing.T) {
+		hook := DecodeHookFuncValue(func(from reflect.Value, to reflect.Value) (interface{}, error) {
+			return strconv.Itoa(int(from.Int())), nil
+		})
+
+		convert, err := pkg.FromDecodeHook[int, string](hook)
+		assert.Nil(t, err)
+
+		value, err := convert(42)
+		assert.Nil(t, err)
+		assert.Equal(t, "42", value)
+	})
+	t.Run("should error when the hook result is not of the target type", func(t *testing.T) {
+		convert, err := pkg.FromDecodeHook[string, HookTimeout](priorityHook())
+		assert.Nil(t, err)
+
+		_, err = convert("10s")
+
+		assert.ErrorIs(t, err, pkg.ErrDecodeHookResult)
+	})
+	t.Run("should error when the hook is not a decode hook", func(t *testing.T) {
+		_, err := pkg.FromDecodeHook[string, int](func(s string) int { return len(s) })
+		assert.ErrorIs(t, err, pkg.ErrInvalidDecodeHook)
+
+		err = pkg.RegisterDecodeHook[string, int](nil)
+		assert.ErrorIs(t, err, pkg.ErrInvalidDecodeHook)
+	})
+}
@@ -0,0 +1,63 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type RecursiveNode struct {
+	Name string         `se:"Metadata.NameField"`
+	Next *RecursiveNode `se:"->"`
+}
+
+func TestMaxDepth(t *testing.T) {
+	t.Run("should fail when a path has too many segments", func(t *testing.T) {
+		type Local struct {
+			Direction string `se:"Config.SomeList[0].Config.Direction"`
+		}
+
+		err := pkg.NewCodec(pkg.WithMaxDepth(3)).Unmarshal(APIObject{}, &Local{})
+
+		assert.ErrorIs(t, err, pkg.ErrMaxDepth)
+		assert.ErrorContains(t, err, "Direction maps 4 path segments")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should map paths within the depth", func(t *testing.T) {
+		type Local struct {
+			Direction string `se:"Config.SomeList[0].Config.Direction"`
+		}
+		src := APIObject{Config: APIConfig{SomeList: []APIListedObj{{Config: APIListedObjConfig{Direction: "up"}}}}}
+		dst := Local{}
+
+		err := pkg.NewCodec(pkg.WithMaxDepth(4)).Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "up", dst.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail when structs are nested too deep", func(t *testing.T) {
+		codec := pkg.NewCodec(pkg.WithMaxDepth(5))
+
+		err := codec.Marshal(RecursiveNode{Name: "test"}, &APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrMaxDepth)
+		assert.ErrorContains(t, err, "nested 6 levels deep")
+
+		err = codec.Marshal(RecursiveNode{Name: "test"}, &APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrMaxDepth)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not reuse representations introspected without a limit", func(t *testing.T) {
+		type Local struct {
+			Direction string `se:"Config.SomeList[0].Config.Direction"`
+		}
+		assert.Nil(t, pkg.Unmarshal(APIObject{}, &Local{}))
+
+		err := pkg.NewCodec(pkg.WithMaxDepth(3)).Unmarshal(APIObject{}, &Local{})
+
+		assert.ErrorIs(t, err, pkg.ErrMaxDepth)
+		pkg.ClearTypeCache()
+	})
+}
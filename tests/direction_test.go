@@ -0,0 +1,151 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemDirection struct {
+	Name  string       `se:"Metadata.NameField"`
+	Count int          `se:"Config.SomeCount,readonly"`
+	Flag  bool         `se:"Metadata.Flag,writeonly"`
+	Items []SystemItem `se:"Config.SomeList,readonly"`
+}
+
+type SystemItem struct {
+	Direction string `se:"Config.Direction"`
+}
+
+func TestMappingDirection(t *testing.T) {
+	t.Run("should unmarshal readonly fields and ignore writeonly ones", func(t *testing.T) {
+		dst := SystemDirection{}
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "test", Flag: true},
+			Config: APIConfig{
+				SomeCount: 3,
+				SomeList:  []APIListedObj{{Config: APIListedObjConfig{Direction: "up"}}},
+			},
+		}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		assert.Equal(t, 3, dst.Count)
+		assert.False(t, dst.Flag)
+		assert.Equal(t, []SystemItem{{Direction: "up"}}, dst.Items)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal writeonly fields and ignore readonly ones", func(t *testing.T) {
+		src := SystemDirection{
+			Name:  "test",
+			Count: 3,
+			Flag:  true,
+			Items: []SystemItem{{Direction: "up"}},
+		}
+		dst := APIObject{Config: APIConfig{SomeCount: 5}}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.True(t, dst.Metadata.Flag)
+		assert.Equal(t, 5, dst.Config.SomeCount)
+		assert.Empty(t, dst.Config.SomeList)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should ignore readonly fields when comparing", func(t *testing.T) {
+		src := SystemDirection{Name: "test", Count: 3}
+		dst := APIObject{Metadata: APIMetadata{NameField: "test"}, Config: APIConfig{SomeCount: 5}}
+
+		equal, err := pkg.MappedEqual(src, dst)
+
+		assert.Nil(t, err)
+		assert.True(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when a field is both readonly and writeonly", func(t *testing.T) {
+		src := struct {
+			Name string `se:"Metadata.NameField,readonly,writeonly"`
+		}{}
+
+		err := pkg.Marshal(src, &APIObject{})
+
+		assert.ErrorIs(t, err, pkg.ErrConflictingDirection)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestPerDirectionPaths(t *testing.T) {
+	t.Run("should unmarshal from the in paths", func(t *testing.T) {
+		dst := struct {
+			Name   string       `se:"+,in<Metadata.NameField>,out<Config.SomeList[0].Config.Direction>"`
+			Nested SystemNested `se:"+,in<Config.SomeList[0].Config>,out<Config.SomeList2[0].Config>"`
+			Status string       `se:"+,in<Metadata.NameField>"`
+		}{}
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "test"},
+			Config: APIConfig{
+				SomeList:  []APIListedObj{{Config: APIListedObjConfig{Direction: "up"}}},
+				SomeList2: []*APIListedObj{{Config: APIListedObjConfig{Direction: "down"}}},
+			},
+		}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		assert.Equal(t, "up", dst.Nested.Direction)
+		assert.Equal(t, "test", dst.Status)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal into the out paths", func(t *testing.T) {
+		src := struct {
+			Name   string       `se:"+,in<Metadata.NameField>,out<Config.SomeList[0].Config.Direction>"`
+			Nested SystemNested `se:"+,in<Config.SomeList[0].Config>,out<Config.SomeList2[0].Config>"`
+			Status string       `se:"+,in<Metadata.NameField>"`
+		}{Name: "test", Nested: SystemNested{Direction: "down"}, Status: "ignored"}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Empty(t, dst.Metadata.NameField)
+		assert.Equal(t, "test", dst.Config.SomeList[0].Config.Direction)
+		assert.Equal(t, "down", dst.Config.SomeList2[0].Config.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should use the main path for the direction without its own path", func(t *testing.T) {
+		local := struct {
+			Name string `se:"Metadata.NameField,in<Config.SomeList[0].Config.Direction>"`
+		}{}
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "name"},
+			Config:   APIConfig{SomeList: []APIListedObj{{Config: APIListedObjConfig{Direction: "up"}}}},
+		}
+
+		err := pkg.Unmarshal(src, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, "up", local.Name)
+
+		dst := APIObject{}
+		err = pkg.Marshal(local, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "up", dst.Metadata.NameField)
+		assert.Empty(t, dst.Config.SomeList)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when combined with per-type paths", func(t *testing.T) {
+		local := struct {
+			Name string `se:"+,types<APIObject:Metadata.NameField>,in<Metadata.NameField>"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{}, &local)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidDirectionalPath)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,98 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemMeta struct {
+	Name string `se:"Metadata.NameField"`
+	Flag bool   `se:"Metadata.Flag"`
+}
+
+type systemCounted struct {
+	Count int `se:"Config.SomeCount"`
+}
+
+type SystemEmbedding struct {
+	SystemMeta
+	systemCounted
+	Direction string `se:"Config.SomePointed.Config.Direction"`
+}
+
+type SystemEmbeddingPointer struct {
+	*SystemMeta
+	Count int `se:"Config.SomeCount"`
+}
+
+func TestEmbeddedStructs(t *testing.T) {
+	t.Run("should squash the fields of untagged embedded structs when unmarshaling", func(t *testing.T) {
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "test", Flag: true},
+			Config:   APIConfig{SomeCount: 2, SomePointed: &APIListedObj{Config: APIListedObjConfig{Direction: "up"}}},
+		}
+		dst := SystemEmbedding{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, SystemEmbedding{
+			SystemMeta:    SystemMeta{Name: "test", Flag: true},
+			systemCounted: systemCounted{Count: 2},
+			Direction:     "up",
+		}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should squash the fields of untagged embedded structs when marshaling", func(t *testing.T) {
+		src := SystemEmbedding{SystemMeta: SystemMeta{Name: "test"}, systemCounted: systemCounted{Count: 2}}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.Equal(t, 2, dst.Config.SomeCount)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should squash embedded pointers to structs", func(t *testing.T) {
+		dst := SystemEmbeddingPointer{}
+
+		err := pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "test"}}, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+
+		foreign := APIObject{}
+		err = pkg.Marshal(SystemEmbeddingPointer{Count: 2}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, APIObject{Config: APIConfig{SomeCount: 2}}, foreign)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep mapping tagged embedded structs as named children", func(t *testing.T) {
+		dst := struct {
+			SystemNested `se:"Config.SomePointed.Config"`
+		}{}
+		src := APIObject{Config: APIConfig{SomePointed: &APIListedObj{Config: APIListedObjConfig{Direction: "up"}}}}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "up", dst.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should ignore embedded structs tagged to be skipped", func(t *testing.T) {
+		dst := struct {
+			SystemMeta `se:"-"`
+			Count      int `se:"Config.SomeCount"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "test"}, Config: APIConfig{SomeCount: 2}}, &dst)
+
+		assert.Nil(t, err)
+		assert.Empty(t, dst.Name)
+		assert.Equal(t, 2, dst.Count)
+		pkg.ClearTypeCache()
+	})
+}
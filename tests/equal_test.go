@@ -0,0 +1,137 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestMappedEqual(t *testing.T) {
+	local := SystemStruct{
+		Name:        "test",
+		Count:       999,
+		Nested:      SystemNested{Direction: "up"},
+		ListedStuff: []string{"a", "b"},
+	}
+
+	t.Run("should be equal after marshaling", func(t *testing.T) {
+		foreign := &APIObject{}
+		err := pkg.Marshal(local, foreign)
+		assert.Nil(t, err)
+
+		equal, err := pkg.MappedEqual(local, foreign)
+
+		assert.Nil(t, err)
+		assert.True(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not be equal when a mapped value differs", func(t *testing.T) {
+		foreign := &APIObject{}
+		err := pkg.Marshal(local, foreign)
+		assert.Nil(t, err)
+		foreign.Config.SomeList[0].Config.Direction = "down"
+
+		equal, err := pkg.MappedEqual(local, *foreign)
+
+		assert.Nil(t, err)
+		assert.False(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not be equal when the foreign path is missing", func(t *testing.T) {
+		equal, err := pkg.MappedEqual(local, APIObject{Metadata: APIMetadata{NameField: "test"}})
+
+		assert.Nil(t, err)
+		assert.False(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should ignore local values that marshal would skip", func(t *testing.T) {
+		foreign := APIObject{
+			Metadata: APIMetadata{NameField: "test", Flag: true},
+			Config:   APIConfig{SomeCount: 1},
+		}
+
+		equal, err := pkg.MappedEqual(SystemStruct{Name: "test"}, foreign)
+
+		assert.Nil(t, err)
+		assert.True(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should compare every element of mapped slices", func(t *testing.T) {
+		local := SystemStruct{
+			StructSlice: []SystemNestedFromSlice{{Direction: "up"}, {Direction: "down"}},
+		}
+		foreign := &APIObject{}
+		err := pkg.Marshal(local, foreign)
+		assert.Nil(t, err)
+
+		equal, err := pkg.MappedEqual(local, foreign)
+		assert.Nil(t, err)
+		assert.True(t, equal)
+
+		foreign.Config.SomeList[1].Config.Direction = "up"
+		equal, err = pkg.MappedEqual(local, foreign)
+		assert.Nil(t, err)
+		assert.False(t, equal)
+
+		foreign.Config.SomeList = foreign.Config.SomeList[:1]
+		equal, err = pkg.MappedEqual(local, foreign)
+		assert.Nil(t, err)
+		assert.False(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should compare every element mapped through a wildcard", func(t *testing.T) {
+		type Wildcard struct {
+			Directions []string `se:"Config.SomeList[*].Config.Direction"`
+		}
+		wildcard := Wildcard{Directions: []string{"left", "right"}}
+		foreign := &APIObject{}
+		err := pkg.Marshal(wildcard, foreign)
+		assert.Nil(t, err)
+
+		equal, err := pkg.MappedEqual(wildcard, foreign)
+		assert.Nil(t, err)
+		assert.True(t, equal)
+
+		foreign.Config.SomeList[1].Config.Direction = "up"
+		equal, err = pkg.MappedEqual(wildcard, foreign)
+		assert.Nil(t, err)
+		assert.False(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should compare every entry of mapped maps", func(t *testing.T) {
+		mapped := SystemMapped{
+			Labels: map[string]string{"app": "test"},
+			Values: map[string]SystemMappedValue{"first": {Direction: "up"}},
+		}
+		foreign := &APIMappedObj{}
+		err := pkg.Marshal(mapped, foreign)
+		assert.Nil(t, err)
+
+		equal, err := pkg.MappedEqual(mapped, foreign)
+		assert.Nil(t, err)
+		assert.True(t, equal)
+
+		foreign.Values["first"].Direction = "down"
+		equal, err = pkg.MappedEqual(mapped, foreign)
+		assert.Nil(t, err)
+		assert.False(t, equal)
+
+		foreign.Values["first"].Direction = "up"
+		foreign.Metadata.Labels["extra"] = "label"
+		equal, err = pkg.MappedEqual(mapped, foreign)
+		assert.Nil(t, err)
+		assert.False(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error with invalid inputs", func(t *testing.T) {
+		var nilLocal *SystemStruct
+		_, err := pkg.MappedEqual(nilLocal, APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrLocalTypeNotStruct)
+
+		_, err = pkg.MappedEqual(local, "foreign")
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeNotStruct)
+		pkg.ClearTypeCache()
+	})
+}
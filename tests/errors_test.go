@@ -0,0 +1,109 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestFieldErrors(t *testing.T) {
+	t.Run("should identify the field failing introspection", func(t *testing.T) {
+		type Local struct {
+			Name  string `se:"Metadata.NameField"`
+			Count string `se:"Config.SomeCount"`
+		}
+
+		err := pkg.Unmarshal(APIObject{}, &Local{})
+
+		var fieldErr *pkg.FieldError
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMismatch)
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, pkg.FieldError{
+			LocalType: "pkg_test.Local",
+			Field:     "Count",
+			Tag:       "Config.SomeCount",
+			Path:      "Config.SomeCount",
+			Err:       fieldErr.Err,
+		}, *fieldErr)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should identify the nested field failing introspection", func(t *testing.T) {
+		type Item struct {
+			Direction int `se:"Config.Direction"`
+		}
+		type Local struct {
+			Items []Item `se:"Config.SomeList"`
+		}
+
+		err := pkg.Unmarshal(APIObject{}, &Local{})
+
+		var fieldErr *pkg.FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "pkg_test.Local", fieldErr.LocalType)
+		assert.Equal(t, "Items.Direction", fieldErr.Field)
+		assert.Equal(t, "Config.SomeList.Config.Direction", fieldErr.Path)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should identify the field failing to map its value", func(t *testing.T) {
+		type Item struct {
+			Level SystemLevel `se:"Config.Direction,convert<level>"`
+		}
+		type Local struct {
+			Items []Item `se:"Config.SomeList"`
+		}
+		src := Local{Items: []Item{{Level: LevelLow}, {Level: SystemLevel(7)}}}
+
+		err := pkg.Marshal(src, &APIObject{})
+
+		var fieldErr *pkg.FieldError
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Items[1].Level", fieldErr.Field)
+		assert.Equal(t, "Config.Direction,convert<level>", fieldErr.Tag)
+		assert.Contains(t, err.Error(), "(field Items[1].Level of pkg_test.Local")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report every invalid field when collecting all errors", func(t *testing.T) {
+		type Item struct {
+			Direction int    `se:"Config.Direction"`
+			Missing   string `se:"Config.Missing"`
+		}
+		type Local struct {
+			Name  string `se:"Metadata.NameField"`
+			Count string `se:"Config.SomeCount"`
+			Items []Item `se:"Config.SomeList"`
+			Flag  bool   `se:"Metadata.Missing"`
+		}
+
+		err := pkg.NewCodec(pkg.WithAllErrors()).Unmarshal(APIObject{}, &Local{})
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		assert.True(t, ok)
+		fields := []string{}
+		for _, err := range joined.Unwrap() {
+			var fieldErr *pkg.FieldError
+			assert.ErrorAs(t, err, &fieldErr)
+			fields = append(fields, fieldErr.Field)
+		}
+		assert.Equal(t, []string{"Count", "Items.Direction", "Items.Missing", "Flag"}, fields)
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMismatch)
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should stop at the first invalid field by default", func(t *testing.T) {
+		type Local struct {
+			Count string `se:"Config.SomeCount"`
+			Flag  bool   `se:"Metadata.Missing"`
+		}
+
+		err := pkg.Unmarshal(APIObject{}, &Local{})
+
+		var fieldErr *pkg.FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Count", fieldErr.Field)
+		assert.NotErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,203 @@
+package pkg_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestExplain(t *testing.T) {
+	t.Run("should describe how every field maps into the foreign type", func(t *testing.T) {
+		out, err := pkg.Explain(SystemStruct{}, APIObject{})
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "pkg_test.SystemStruct -> pkg_test.APIObject\n")
+		assert.Contains(t, out, "  Name -> Metadata.NameField\n")
+		assert.Contains(t, out, "  Nested -> Config.SomeList[0].Config\n")
+		assert.Contains(t, out, "    Direction -> Config.SomeList[0].Config.Direction\n")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should show the selected type match and skipped fields", func(t *testing.T) {
+		out, err := pkg.Explain(SystemStructWithMultipleDestination{}, SecondaryAPIObject{})
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "Name -> Metadata.NameField [types<SecondaryAPIObject> matched by name]")
+		assert.Contains(t, out, "Flag -> ConfigFlag [types<SecondaryAPIObject> matched by name]")
+		assert.Contains(t, out, "Blackhole: skipped, no types<> entry matched SecondaryAPIObject")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should propagate introspection errors", func(t *testing.T) {
+		_, err := pkg.Explain(SystemStruct{}, "not a struct")
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeNotStruct)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestTypeMatchPrecedence(t *testing.T) {
+	t.Run("should prefer the most specific matching entry", func(t *testing.T) {
+		dst := struct {
+			Flag bool   `se:"+,types<APIObject:Metadata.Flag|pkg_test.APIObject:Config.SomeCount|Other:Metadata.Flag>"`
+			Name string `se:"+,types<pkg_test.APIObject:Config.SomeCount|github.com/ilexPar/struct-marshal/tests_test.APIObject:Metadata.NameField>"`
+		}{}
+		src := APIObject{Metadata: APIMetadata{Flag: true, NameField: "test"}}
+
+		out, err := pkg.Explain(dst, src)
+
+		assert.NotNil(t, err, "qualified entry for Flag points to an int field")
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMismatch)
+		assert.Empty(t, out)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should resolve entries with the same precedence by declaration order", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"+,types<APIObject:Metadata.NameField|APIObject:Config.SomeCount>"`
+		}{}
+		src := APIObject{Metadata: APIMetadata{NameField: "test"}}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should match qualified and import path type names", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"+,types<APIObject:Config.SomeCount|github.com/ilexPar/struct-marshal/tests_test.APIObject:Metadata.NameField>"`
+			Flag bool   `se:"+,types<APIObject:ConfigFlag|pkg_test.APIObject:Metadata.Flag>"`
+		}{}
+		src := APIObject{Metadata: APIMetadata{NameField: "test", Flag: true}}
+
+		err := pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		assert.True(t, dst.Flag)
+
+		out, err := pkg.Explain(dst, src)
+		assert.Nil(t, err)
+		assert.Contains(t, out, "Name -> Metadata.NameField [types<github.com/ilexPar/struct-marshal/tests_test.APIObject> matched by import path]")
+		assert.Contains(t, out, "Flag -> Metadata.Flag [types<pkg_test.APIObject> matched by qualified name]")
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestTypePatterns(t *testing.T) {
+	t.Run("should match every type the pattern covers", func(t *testing.T) {
+		type Local struct {
+			Name string `se:"Metadata.NameField,types<~APIObject$>"`
+			Flag bool   `se:"Metadata.Flag,types<~^API>"`
+		}
+		primary := Local{}
+		secondary := Local{}
+
+		err := pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "primary", Flag: true}}, &primary)
+		assert.Nil(t, err)
+		err = pkg.Unmarshal(SecondaryAPIObject{Metadata: APIMetadata{NameField: "secondary", Flag: true}}, &secondary)
+		assert.Nil(t, err)
+
+		assert.Equal(t, Local{Name: "primary", Flag: true}, primary)
+		assert.Equal(t, Local{Name: "secondary"}, secondary)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should prefer entries naming the type", func(t *testing.T) {
+		type Local struct {
+			Name string `se:"+,types<~Object$:Metadata.NameField|SecondaryAPIObject:Child.Direction>"`
+		}
+
+		out, err := pkg.Explain(Local{}, SecondaryAPIObject{})
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "Name -> Child.Direction [types<SecondaryAPIObject> matched by name]")
+
+		out, err = pkg.Explain(Local{}, APIObject{})
+		assert.Nil(t, err)
+		assert.Contains(t, out, "Name -> Metadata.NameField [types<~Object$> matched by pattern]")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail when the pattern doesn't compile", func(t *testing.T) {
+		type Local struct {
+			Name string `se:"Metadata.NameField,types<~API[>"`
+		}
+
+		err := pkg.Unmarshal(APIObject{}, &Local{})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidTypePattern)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestDOT(t *testing.T) {
+	t.Run("should render the mapping as a graph", func(t *testing.T) {
+		repr, err := pkg.Describe(SystemStructWithMultipleDestination{}, SecondaryAPIObject{})
+		assert.Nil(t, err)
+
+		expected := `digraph "SecondaryAPIObject" {
+	rankdir=LR
+	node [shape=box]
+	"local:Blackhole" [label="Blackhole", style=dotted]
+	"local:Name" [label="Name"]
+	"foreign:Metadata.NameField" [label="Metadata.NameField", shape=ellipse]
+	"local:Name" -> "foreign:Metadata.NameField" [label="types<SecondaryAPIObject>"]
+	"local:Flag" [label="Flag"]
+	"foreign:ConfigFlag" [label="ConfigFlag", shape=ellipse]
+	"local:Flag" -> "foreign:ConfigFlag" [label="types<SecondaryAPIObject>"]
+	"local:DismissNested" [label="DismissNested"]
+	"local:DismissNested" -> "local:DismissNested.Direction" [style=dashed]
+	"local:DismissNested.Direction" [label="Direction"]
+	"foreign:Child.Direction" [label="Child.Direction", shape=ellipse]
+	"local:DismissNested.Direction" -> "foreign:Child.Direction" [label="types<SecondaryAPIObject>"]
+	"local:DismissNestedPointer" [label="DismissNestedPointer"]
+	"local:DismissNestedPointer" -> "local:DismissNestedPointer.Direction" [style=dashed]
+	"local:DismissNestedPointer.Direction" [label="Direction"]
+	"local:DismissNestedPointer.Direction" -> "foreign:Child.Direction" [label="types<SecondaryAPIObject>"]
+}
+`
+		assert.Equal(t, expected, repr.DOT())
+		pkg.ClearTypeCache()
+	})
+	t.Run("should label the conditions selecting the paths", func(t *testing.T) {
+		repr, err := pkg.Describe(SystemWorkload{}, APIWorkload{})
+		assert.Nil(t, err)
+
+		dot := repr.DOT()
+
+		assert.Contains(t, dot, `"local:Template" -> "foreign:Spec.Template" [label="when<Kind=Deployment>"]`)
+		assert.Contains(t, dot, `"local:Template" -> "foreign:Spec.JobTemplate.Spec.Template" [label="when<Kind=CronJob>"]`)
+		assert.Contains(t, dot, `"local:Template.Image" -> "foreign:Spec.JobTemplate.Spec.Template.Image"`)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestIntrospectRepr(t *testing.T) {
+	t.Run("should return a representation that can be inspected", func(t *testing.T) {
+		repr, err := pkg.IntrospectRepr(&SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		pkg.ClearTypeCache()
+
+		assert.Equal(t, reflect.TypeFor[APIObject](), repr.ForeignType())
+
+		field, ok := repr.Field("Count")
+		assert.True(t, ok)
+		assert.Equal(t, reflect.Int, field.Kind)
+
+		path, ok := repr.PathFor("Name")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"Metadata", "NameField"}, path)
+
+		path, ok = repr.PathFor("Nested.DeeepNested.Direction")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"Config", "SomeList[0]", "Config", "DeepNested", "Direction2"}, path)
+
+		_, ok = repr.PathFor("Nested.Missing")
+		assert.False(t, ok)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail for types that can't be mapped", func(t *testing.T) {
+		repr, err := pkg.IntrospectRepr(SystemStruct{}, "foreign")
+		assert.Nil(t, repr)
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeNotStruct)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,86 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemFallback struct {
+	Name      string `se:"Config.SomeList[0].Config.Direction||Metadata.NameField"`
+	Direction string `se:"Child.Direction||Config.SomePointed.Config.Direction"`
+}
+
+func TestFallbackPaths(t *testing.T) {
+	t.Run("should unmarshal the first non zero path", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: "old"}}
+		dst := SystemFallback{}
+
+		err := pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "old", dst.Name)
+
+		src.Config.SomeList = []APIListedObj{{Config: APIListedObjConfig{Direction: "new"}}}
+		err = pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "new", dst.Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal into the first path only", func(t *testing.T) {
+		src := SystemFallback{Name: "new", Direction: "up"}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "new", dst.Config.SomeList[0].Config.Direction)
+		assert.Empty(t, dst.Metadata.NameField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should dismiss paths missing from the foreign type", func(t *testing.T) {
+		src := SystemFallback{Direction: "up"}
+
+		dst := APIObject{}
+		err := pkg.Marshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "up", dst.Config.SomePointed.Config.Direction)
+
+		secondary := struct {
+			Direction string `se:"Child.Direction||Config.SomePointed.Config.Direction"`
+		}{}
+		err = pkg.Unmarshal(SecondaryAPIObject{Child: SecondaryAPIObjectChild{Direction: "down"}}, &secondary)
+		assert.Nil(t, err)
+		assert.Equal(t, "down", secondary.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should show fallback paths when explaining", func(t *testing.T) {
+		out, err := pkg.Explain(SystemFallback{}, APIObject{})
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "Name -> Config.SomeList[0].Config.Direction\n")
+		assert.Contains(t, out, "Name -> Metadata.NameField [fallback]\n")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when none of the paths exist", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"Spec.Name||Spec.OldName"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when combined with per direction paths", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"Metadata.NameField||Spec.OldName,in<Metadata.NameField>"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidFallbackPath)
+		pkg.ClearTypeCache()
+	})
+}
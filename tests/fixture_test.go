@@ -60,6 +60,84 @@ type APIConfig struct {
 	SomePointed *APIListedObj
 }
 
+// Mock an API object holding maps
+type APIMappedObjValue struct {
+	Direction string
+	Count     int
+}
+type APIMappedObj struct {
+	Metadata struct {
+		Labels map[string]string
+	}
+	Values map[string]*APIMappedObjValue
+}
+type SystemMappedValue struct {
+	Direction string `se:"Direction"`
+}
+type SystemMapped struct {
+	Labels map[string]string            `se:"Metadata.Labels"`
+	Values map[string]SystemMappedValue `se:"Values"`
+}
+
+// Mock an API object holding fixed-size arrays
+type APIArrayObj struct {
+	Tags  [3]string
+	Items [2]APIListedObj
+}
+
+// Mock an API object holding nested slices
+type APIMatrixObj struct {
+	Matrix [][]APIListedObj
+	Grid   [][]string
+}
+type SystemMatrix struct {
+	First string     `se:"Matrix.Config.Direction"`
+	Last  string     `se:"Matrix[last][-1].Config.Direction"`
+	Grid  [][]string `se:"Grid"`
+}
+
+// Mock an API object whose layout depends on the value of a discriminator field
+type APIPodTemplate struct {
+	Image string
+}
+type APIWorkload struct {
+	Kind string
+	Meta struct {
+		Type string
+	}
+	Spec struct {
+		Template    APIPodTemplate
+		JobTemplate struct {
+			Spec struct {
+				Template APIPodTemplate
+			}
+		}
+	}
+}
+type SystemPodTemplate struct {
+	Image string `se:"Image"`
+}
+type SystemWorkload struct {
+	Kind     string            `se:"Kind"`
+	Template SystemPodTemplate `se:"+,kind<Deployment:Spec.Template|CronJob:Spec.JobTemplate.Spec.Template>"`
+}
+
+// Mock an API object whose layout changed between API versions while keeping the same Go type
+type APIScaler struct {
+	APIVersion string
+	Spec       struct {
+		Replicas    int
+		MinReplicas int
+		Scale       struct {
+			Min int
+		}
+	}
+}
+type SystemScaler struct {
+	APIVersion  string `se:"APIVersion"`
+	MinReplicas int    `se:"+,version<autoscaling/v1:Spec.MinReplicas|autoscaling/v2:Spec.Scale.Min>"`
+}
+
 // Mock another struct that differs in structure from both our internal struct and the APIObject
 // to test multiple types compatibility
 type SecondaryAPIObjectChild struct {
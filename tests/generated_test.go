@@ -0,0 +1,116 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type APIGenerated struct {
+	Metadata struct {
+		Name string
+	}
+	Replicas int
+}
+
+type SystemGenerated struct {
+	Name     string `se:"Metadata.Name"`
+	Replicas int    `se:"Replicas"`
+	Audited  bool
+}
+
+// generatedCalls counts the calls to the mappers of SystemGenerated, shaped as the ones segen generates.
+var generatedCalls int
+
+func EncodeSystemGeneratedToAPIGenerated(from *SystemGenerated, into *APIGenerated) error {
+	generatedCalls++
+	if from.Name != "" {
+		into.Metadata.Name = from.Name
+	}
+	if from.Replicas != 0 {
+		into.Replicas = from.Replicas
+	}
+	return nil
+}
+
+func DecodeAPIGeneratedToSystemGenerated(from *APIGenerated, into *SystemGenerated) error {
+	generatedCalls++
+	if from.Metadata.Name != "" {
+		into.Name = from.Metadata.Name
+	}
+	if from.Replicas != 0 {
+		into.Replicas = from.Replicas
+	}
+	return nil
+}
+
+func init() {
+	pkg.RegisterGenerated(EncodeSystemGeneratedToAPIGenerated, DecodeAPIGeneratedToSystemGenerated)
+}
+
+func TestRegisterGenerated(t *testing.T) {
+	t.Run("should map registered types with the generated functions", func(t *testing.T) {
+		generatedCalls = 0
+		foreign := APIGenerated{}
+		err := pkg.Marshal(SystemGenerated{Name: "app", Replicas: 2}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, "app", foreign.Metadata.Name)
+		assert.Equal(t, 2, foreign.Replicas)
+
+		local := SystemGenerated{}
+		err = pkg.Unmarshal(&foreign, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, SystemGenerated{Name: "app", Replicas: 2}, local)
+		assert.Equal(t, 2, generatedCalls)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should validate the values given to the generated functions", func(t *testing.T) {
+		err := pkg.Marshal(SystemGenerated{}, APIGenerated{})
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+
+		err = pkg.Unmarshal(APIGenerated{}, SystemGenerated{})
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+
+		var missing *SystemGenerated
+		err = pkg.Marshal(missing, &APIGenerated{})
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalSrcType)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should run the hooks registered for the types", func(t *testing.T) {
+		pkg.RegisterHooks(pkg.Hooks[SystemGenerated, APIGenerated]{
+			BeforeEncode: func(local *SystemGenerated, foreign *APIGenerated) error {
+				local.Replicas++
+				return nil
+			},
+			AfterDecode: func(local *SystemGenerated, foreign *APIGenerated) error {
+				local.Audited = true
+				return nil
+			},
+		})
+		defer pkg.RegisterHooks(pkg.Hooks[SystemGenerated, APIGenerated]{})
+
+		foreign := APIGenerated{}
+		err := pkg.Marshal(SystemGenerated{Replicas: 1}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, foreign.Replicas)
+
+		local := SystemGenerated{}
+		err = pkg.Unmarshal(foreign, &local)
+		assert.Nil(t, err)
+		assert.True(t, local.Audited)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should walk the representation when options change the mapping", func(t *testing.T) {
+		generatedCalls = 0
+		codec := pkg.NewCodec(pkg.WithOnly("Name"))
+		foreign := APIGenerated{}
+		err := codec.Marshal(SystemGenerated{Name: "app", Replicas: 2}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, "app", foreign.Metadata.Name)
+		assert.Equal(t, 0, foreign.Replicas)
+		assert.Equal(t, 0, generatedCalls)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,76 @@
+package pkg_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilexPar/struct-marshal/generator"
+)
+
+// checkPackage type-checks the files of a package found in the testdata directory.
+func checkPackage(t *testing.T, name string, files ...string) *types.Package {
+	fset := token.NewFileSet()
+	parsed := []*ast.File{}
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, filepath.Join("testdata", "src", name, file), nil, 0)
+		require.NoError(t, err)
+		parsed = append(parsed, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(name, fset, parsed, nil)
+	require.NoError(t, err)
+	return pkg
+}
+
+func TestGenerator(t *testing.T) {
+	t.Run("should generate the mappers of every pair of types", func(t *testing.T) {
+		pkg := checkPackage(t, "gen", "gen.go")
+		src, err := generator.Generate(pkg, []generator.Pair{
+			{Local: "App", Foreign: "Deployment"},
+			{Local: "App", Foreign: "gen.Service"},
+			{Local: "Release", Foreign: "Deployment"},
+		})
+		require.NoError(t, err)
+
+		if os.Getenv("UPDATE_GOLDEN") != "" {
+			require.NoError(t, os.WriteFile(filepath.Join("testdata", "src", "gen", "mappers_se.go.golden"), src, 0o644))
+		}
+		golden, err := os.ReadFile(filepath.Join("testdata", "src", "gen", "mappers_se.go.golden"))
+		require.NoError(t, err)
+		assert.Equal(t, string(golden), string(src))
+
+		checkPackage(t, "gen", "gen.go", "mappers_se.go.golden")
+	})
+
+	t.Run("should fail for fields relying on unsupported options", func(t *testing.T) {
+		pkg := checkPackage(t, "gen", "gen.go")
+		_, err := generator.Generate(pkg, []generator.Pair{{Local: "Unsupported", Foreign: "Deployment"}})
+		assert.ErrorContains(t, err, "Unsupported=Deployment: field Name: tag options other than")
+	})
+
+	t.Run("should fail for types that can't be found", func(t *testing.T) {
+		pkg := checkPackage(t, "gen", "gen.go")
+		_, err := generator.Generate(pkg, []generator.Pair{{Local: "App", Foreign: "api.Deployment"}})
+		assert.ErrorContains(t, err, "foreign struct api.Deployment not found")
+	})
+}
+
+func TestParsePair(t *testing.T) {
+	t.Run("should parse pairs of types", func(t *testing.T) {
+		pair, err := generator.ParsePair("App=api.Deployment")
+		require.NoError(t, err)
+		assert.Equal(t, generator.Pair{Local: "App", Foreign: "api.Deployment"}, pair)
+
+		_, err = generator.ParsePair("App")
+		assert.ErrorContains(t, err, "expected Local=Foreign")
+	})
+}
@@ -0,0 +1,142 @@
+package pkg_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type APIHooked struct {
+	Name     string
+	FullName string
+	Source   string
+}
+
+type SystemHooked struct {
+	First    string `se:"Name"`
+	Last     string
+	FullName string `se:"FullName"`
+	Source   string
+}
+
+func (this *SystemHooked) BeforeMarshal(foreign any) error {
+	if this.First == "" {
+		return errors.New("missing first name")
+	}
+	this.FullName = this.First + " " + this.Last
+	foreign.(*APIHooked).Source = "system"
+	return nil
+}
+
+func (this *SystemHooked) AfterUnmarshal(foreign any) error {
+	if this.First == "" {
+		return errors.New("missing first name")
+	}
+	this.First = strings.ToLower(this.First)
+	this.Last = strings.TrimPrefix(this.FullName, this.First+" ")
+	this.Source = foreign.(APIHooked).Source
+	return nil
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	t.Run("should call BeforeMarshal before encoding", func(t *testing.T) {
+		local := SystemHooked{First: "ada", Last: "lovelace"}
+		foreign := APIHooked{}
+		err := pkg.Marshal(local, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, APIHooked{Name: "ada", FullName: "ada lovelace", Source: "system"}, foreign)
+		assert.Equal(t, "", local.FullName, "values passed by copy should not be modified")
+
+		err = pkg.Marshal(&local, &APIHooked{})
+		assert.Nil(t, err)
+		assert.Equal(t, "ada lovelace", local.FullName)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should call AfterUnmarshal after decoding", func(t *testing.T) {
+		local := SystemHooked{}
+		err := pkg.Unmarshal(APIHooked{Name: "ADA", FullName: "ada lovelace", Source: "api"}, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, SystemHooked{First: "ada", Last: "lovelace", FullName: "ada lovelace", Source: "api"}, local)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return hook errors", func(t *testing.T) {
+		err := pkg.Marshal(SystemHooked{}, &APIHooked{})
+		assert.ErrorIs(t, err, pkg.ErrHookFunc)
+		assert.ErrorContains(t, err, "missing first name")
+
+		err = pkg.Unmarshal(APIHooked{}, &SystemHooked{})
+		assert.ErrorIs(t, err, pkg.ErrHookFunc)
+		pkg.ClearTypeCache()
+	})
+}
+
+type APIAudited struct {
+	Name     string
+	Replicas int
+	Audit    []string
+}
+
+type SystemAudited struct {
+	Name     string   `se:"Name"`
+	Replicas int      `se:"Replicas"`
+	Audit    []string `se:"Audit"`
+}
+
+func init() {
+	pkg.RegisterHooks(pkg.Hooks[SystemAudited, APIAudited]{
+		BeforeEncode: func(local *SystemAudited, foreign *APIAudited) error {
+			if local.Name == "" {
+				return errors.New("missing name")
+			}
+			local.Audit = append(local.Audit, "before encode")
+			return nil
+		},
+		AfterEncode: func(local *SystemAudited, foreign *APIAudited) error {
+			foreign.Audit = append(foreign.Audit, "after encode")
+			return nil
+		},
+		BeforeDecode: func(local *SystemAudited, foreign *APIAudited) error {
+			local.Replicas = 1
+			return nil
+		},
+		AfterDecode: func(local *SystemAudited, foreign *APIAudited) error {
+			local.Audit = append(local.Audit, "after decode "+foreign.Name)
+			return nil
+		},
+	})
+}
+
+func TestRegisteredHooks(t *testing.T) {
+	t.Run("should run encoding hooks", func(t *testing.T) {
+		local := SystemAudited{Name: "web", Replicas: 3}
+		foreign := APIAudited{}
+		err := pkg.Marshal(local, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, APIAudited{Name: "web", Replicas: 3, Audit: []string{"before encode", "after encode"}}, foreign)
+		assert.Nil(t, local.Audit, "values passed by copy should not be modified")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should run decoding hooks", func(t *testing.T) {
+		local := SystemAudited{}
+		err := pkg.Unmarshal(&APIAudited{Name: "web"}, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, SystemAudited{Name: "web", Replicas: 1, Audit: []string{"after decode web"}}, local)
+
+		local = SystemAudited{}
+		err = pkg.Unmarshal(APIAudited{Name: "web", Replicas: 5}, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, local.Replicas)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return hook errors", func(t *testing.T) {
+		err := pkg.Marshal(SystemAudited{}, &APIAudited{})
+		assert.ErrorIs(t, err, pkg.ErrHookFunc)
+		assert.ErrorContains(t, err, "BeforeEncode")
+		assert.ErrorContains(t, err, "missing name")
+		pkg.ClearTypeCache()
+	})
+}
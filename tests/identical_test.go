@@ -0,0 +1,97 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type IdenticalLocal struct {
+	Metadata APIMetadata                  `se:"Metadata"`
+	Listed   []APIListedObj               `se:"Config.SomeList"`
+	Pointed  *APIListedObj                `se:"Config.SomePointed"`
+	Values   map[string]APIMappedObjValue `se:"Values"`
+}
+
+type APISwapped struct {
+	Left  string `se:"Right"`
+	Right string `se:"Left"`
+}
+
+type APIWithIdentical struct {
+	Metadata APIMetadata
+	Config   APIConfig
+	Values   map[string]APIMappedObjValue
+	Swapped  APISwapped
+}
+
+type SwappedLocal struct {
+	Swapped APISwapped `se:"Swapped"`
+}
+
+type CopiedConfig struct {
+	List []string
+}
+
+type CopiedLocal struct {
+	Config CopiedConfig `se:"Config"`
+}
+
+type CopiedForeign struct {
+	Config CopiedConfig
+}
+
+func TestIdenticalTypes(t *testing.T) {
+	t.Run("should assign nested structs of the same type as a whole", func(t *testing.T) {
+		src := IdenticalLocal{
+			Metadata: APIMetadata{NameField: "test", Flag: true},
+			Listed:   []APIListedObj{{List: []string{"a"}, Config: APIListedObjConfig{Direction: "first"}}},
+			Pointed:  &APIListedObj{Config: APIListedObjConfig{DeepNested: APIDeepNested{Direction2: "deep"}}},
+			Values:   map[string]APIMappedObjValue{"key": {Direction: "d", Count: 1}},
+		}
+		dst := APIWithIdentical{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, src.Metadata, dst.Metadata)
+		assert.Equal(t, src.Listed, dst.Config.SomeList)
+		assert.Equal(t, src.Pointed, dst.Config.SomePointed)
+		assert.Equal(t, src.Values, dst.Values)
+
+		decoded := IdenticalLocal{}
+		err = pkg.Unmarshal(dst, &decoded)
+
+		assert.Nil(t, err)
+		assert.Equal(t, src, decoded)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep mapping field by field structs declaring their own mappings", func(t *testing.T) {
+		dst := APIWithIdentical{}
+
+		err := pkg.Marshal(SwappedLocal{Swapped: APISwapped{Left: "l", Right: "r"}}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, APISwapped{Left: "r", Right: "l"}, dst.Swapped)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should copy values with the registered copier", func(t *testing.T) {
+		copies := 0
+		pkg.RegisterCopier(func(from, into *CopiedConfig) {
+			copies++
+			into.List = append([]string{}, from.List...)
+		})
+		src := CopiedLocal{Config: CopiedConfig{List: []string{"a"}}}
+		dst := CopiedForeign{}
+
+		err := pkg.Marshal(src, &dst)
+		src.Config.List[0] = "changed"
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, copies)
+		assert.Equal(t, []string{"a"}, dst.Config.List)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,181 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+// Mock foreign types holding objects behind interfaces, as Kubernetes API types do with runtime.Object
+type APIRuntimeObject interface {
+	GetKind() string
+}
+type APIDeploymentSpec struct {
+	Replicas int
+	Paused   *bool
+}
+type APIDeployment struct {
+	Kind string
+	Spec APIDeploymentSpec
+}
+type APIService struct {
+	Kind string
+	Port int
+}
+type APIEnvelope struct {
+	Name    string
+	Object  APIRuntimeObject
+	Payload any
+}
+
+func (this *APIDeployment) GetKind() string { return this.Kind }
+func (this *APIService) GetKind() string    { return this.Kind }
+
+func init() {
+	pkg.RegisterConcreteType[*APIDeployment]()
+	pkg.RegisterConcreteType[*APIService]()
+}
+
+type SystemEnveloped struct {
+	Name     string `se:"Name"`
+	Kind     string `se:"Object.Kind,as<APIDeployment>"`
+	Replicas int    `se:"Object.Spec.Replicas,as<APIDeployment>"`
+	Port     int    `se:"Payload.Port,as<APIService>"`
+}
+
+type SystemDeploymentSpec struct {
+	Replicas int `se:"Replicas"`
+}
+
+type SystemDeployment struct {
+	Kind string               `se:"Kind"`
+	Spec SystemDeploymentSpec `se:"Spec"`
+}
+
+func TestInterfaceFields(t *testing.T) {
+	t.Run("should decode fields traversing interfaces holding the asserted type", func(t *testing.T) {
+		src := APIEnvelope{
+			Name:    "test",
+			Object:  &APIDeployment{Kind: "Deployment", Spec: APIDeploymentSpec{Replicas: 2}},
+			Payload: &APIService{Port: 80},
+		}
+		dst := SystemEnveloped{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, SystemEnveloped{Name: "test", Kind: "Deployment", Replicas: 2, Port: 80}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should allocate the asserted type when encoding", func(t *testing.T) {
+		src := SystemEnveloped{Name: "test", Kind: "Deployment", Replicas: 2, Port: 80}
+		dst := APIEnvelope{Object: &APIService{Kind: "Service"}}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, APIEnvelope{
+			Name:    "test",
+			Object:  &APIDeployment{Kind: "Deployment", Spec: APIDeploymentSpec{Replicas: 2}},
+			Payload: &APIService{Port: 80},
+		}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should skip interfaces holding another type when decoding", func(t *testing.T) {
+		src := APIEnvelope{Name: "test", Object: &APIService{Kind: "Service"}}
+
+		report, err := pkg.UnmarshalWithReport(src, &SystemEnveloped{})
+
+		assert.Nil(t, err)
+		assert.Contains(t, report.Skipped, pkg.FieldSkip{
+			Field:  "Kind",
+			Path:   []string{"Object", "(APIDeployment)", "Kind"},
+			Reason: pkg.SkipConcrete,
+		})
+		assert.Contains(t, report.Skipped, pkg.FieldSkip{
+			Field:  "Port",
+			Path:   []string{"Payload", "(APIService)", "Port"},
+			Reason: pkg.SkipNilPointer,
+		})
+		pkg.ClearTypeCache()
+	})
+	t.Run("should map nested structs under asserted interfaces", func(t *testing.T) {
+		type Local struct {
+			Deployment SystemDeployment `se:"Object,as<APIDeployment>"`
+		}
+		src := Local{Deployment: SystemDeployment{Kind: "Deployment", Spec: SystemDeploymentSpec{Replicas: 2}}}
+		foreign := APIEnvelope{}
+
+		err := pkg.Marshal(src, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, &APIDeployment{Kind: "Deployment", Spec: APIDeploymentSpec{Replicas: 2}}, foreign.Object)
+
+		dst := Local{}
+		err = pkg.Unmarshal(foreign, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, src, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should strip asserted interfaces along with relative paths", func(t *testing.T) {
+		type Spec struct {
+			Replicas int    `se:"Replicas"`
+			Name     string `se:"^^Name"`
+		}
+		type Local struct {
+			Spec Spec `se:"Object.Spec,as<APIDeployment>"`
+		}
+		src := APIEnvelope{Name: "test", Object: &APIDeployment{Spec: APIDeploymentSpec{Replicas: 2}}}
+		dst := Local{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, Local{Spec: Spec{Replicas: 2, Name: "test"}}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should assign the concrete value as a whole when the path ends asserting it", func(t *testing.T) {
+		type Local struct {
+			Deployment *APIDeployment `se:"Object,as<APIDeployment>"`
+		}
+		deployment := &APIDeployment{Kind: "Deployment"}
+		foreign := APIEnvelope{}
+
+		err := pkg.Marshal(Local{Deployment: deployment}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, APIRuntimeObject(deployment), foreign.Object)
+
+		dst := Local{}
+		err = pkg.Unmarshal(foreign, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, deployment, dst.Deployment)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should reject traversing interfaces without asserting their type", func(t *testing.T) {
+		dst := struct {
+			Kind string `se:"Object.Kind"`
+		}{}
+
+		err := pkg.Unmarshal(APIEnvelope{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrConcreteType)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should reject asserting types that are unregistered or the interface can't hold", func(t *testing.T) {
+		unregistered := struct {
+			Kind string `se:"Object.Kind,as<APIObject>"`
+		}{}
+		unimplemented := struct {
+			Kind string `se:"Object.Kind,as<APIEnvelope>"`
+		}{}
+		unused := struct {
+			Name string `se:"Name,as<APIDeployment>"`
+		}{}
+
+		assert.ErrorIs(t, pkg.Unmarshal(APIEnvelope{}, &unregistered), pkg.ErrConcreteType)
+		assert.ErrorIs(t, pkg.Unmarshal(APIEnvelope{}, &unimplemented), pkg.ErrConcreteType)
+		assert.ErrorContains(t, pkg.Unmarshal(APIEnvelope{}, &unused), "asserts no interface field of Name")
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,162 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemJoined struct {
+	Ref string `se:"+,join<Metadata.NameField,\"/\",Config.SomeCount>"`
+}
+
+func TestJoinedFields(t *testing.T) {
+	t.Run("should join the foreign fields when unmarshaling", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: "test"}, Config: APIConfig{SomeCount: 3}}
+		dst := SystemJoined{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test/3", dst.Ref)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should skip the field when every joined field is zero", func(t *testing.T) {
+		dst := SystemJoined{Ref: "existing"}
+
+		report, err := pkg.UnmarshalWithReport(APIObject{}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "existing", dst.Ref)
+		skip, found := findSkip(report.Skipped, "Ref")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipZeroValue, skip.Reason)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should split the local value when marshaling", func(t *testing.T) {
+		src := SystemJoined{Ref: "test/3"}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.Equal(t, 3, dst.Config.SomeCount)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should support leading and trailing literals", func(t *testing.T) {
+		type Local struct {
+			Ref string `se:"+,join<\"[\",Metadata.NameField,\"|\",Config.SomeList[0].Config.Direction,\"]\">"`
+		}
+		dst := APIObject{}
+
+		err := pkg.Marshal(Local{Ref: "[a,b|up]"}, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "a,b", dst.Metadata.NameField)
+		assert.Equal(t, "up", dst.Config.SomeList[0].Config.Direction)
+
+		local := Local{}
+		err = pkg.Unmarshal(dst, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, "[a,b|up]", local.Ref)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when the local value doesn't match the literals", func(t *testing.T) {
+		err := pkg.Marshal(SystemJoined{Ref: "test"}, &APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrJoinMismatch)
+
+		err = pkg.Marshal(SystemJoined{Ref: "test/three"}, &APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrInvalidConstValue)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should compare the joined value", func(t *testing.T) {
+		dst := APIObject{Metadata: APIMetadata{NameField: "test"}, Config: APIConfig{SomeCount: 3}}
+
+		equal, err := pkg.MappedEqual(SystemJoined{Ref: "test/3"}, dst)
+		assert.Nil(t, err)
+		assert.True(t, equal)
+
+		equal, err = pkg.MappedEqual(SystemJoined{Ref: "test/4"}, dst)
+		assert.Nil(t, err)
+		assert.False(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error on invalid joins", func(t *testing.T) {
+		adjacent := struct {
+			Ref string `se:"+,join<Metadata.NameField,Config.SomeCount>"`
+		}{}
+		err := pkg.Unmarshal(APIObject{}, &adjacent)
+		assert.ErrorIs(t, err, pkg.ErrInvalidJoin)
+
+		notString := struct {
+			Ref int `se:"+,join<Metadata.NameField,\"/\",Config.SomeCount>"`
+		}{}
+		err = pkg.Unmarshal(APIObject{}, &notString)
+		assert.ErrorIs(t, err, pkg.ErrInvalidJoin)
+
+		collection := struct {
+			Ref string `se:"+,join<Metadata.NameField,\"/\",Config.SomeList>"`
+		}{}
+		err = pkg.Unmarshal(APIObject{}, &collection)
+		assert.ErrorIs(t, err, pkg.ErrInvalidJoin)
+
+		mainPath := struct {
+			Ref string `se:"Metadata.NameField,join<Metadata.NameField,\"/\",Config.SomeCount>"`
+		}{}
+		err = pkg.Unmarshal(APIObject{}, &mainPath)
+		assert.ErrorIs(t, err, pkg.ErrInvalidJoin)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestSplitFields(t *testing.T) {
+	type Local struct {
+		Ref string `se:"+,split<\"/\",Metadata.NameField,Config.SomeList[0].Config.Direction>"`
+	}
+
+	t.Run("should split the local value into the foreign fields when marshaling", func(t *testing.T) {
+		dst := APIObject{}
+
+		err := pkg.Marshal(Local{Ref: "test/up"}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.Equal(t, "up", dst.Config.SomeList[0].Config.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should reassemble the local value when unmarshaling", func(t *testing.T) {
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "test"},
+			Config:   APIConfig{SomeList: []APIListedObj{{Config: APIListedObjConfig{Direction: "up"}}}},
+		}
+		dst := Local{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test/up", dst.Ref)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when the separator is missing", func(t *testing.T) {
+		err := pkg.Marshal(Local{Ref: "test"}, &APIObject{})
+
+		assert.ErrorIs(t, err, pkg.ErrJoinMismatch)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error on invalid splits", func(t *testing.T) {
+		noSeparator := struct {
+			Ref string `se:"+,split<Metadata.NameField,Config.SomeCount>"`
+		}{}
+		err := pkg.Unmarshal(APIObject{}, &noSeparator)
+		assert.ErrorIs(t, err, pkg.ErrInvalidJoin)
+
+		singlePath := struct {
+			Ref string `se:"+,split<\"/\",Metadata.NameField>"`
+		}{}
+		err = pkg.Unmarshal(APIObject{}, &singlePath)
+		assert.ErrorIs(t, err, pkg.ErrInvalidJoin)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,92 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestPerKindPaths(t *testing.T) {
+	t.Run("should unmarshal from the path selected by the discriminator", func(t *testing.T) {
+		deployment := APIWorkload{Kind: "Deployment"}
+		deployment.Spec.Template.Image = "nginx"
+		cronJob := APIWorkload{Kind: "CronJob"}
+		cronJob.Spec.Template.Image = "ignored"
+		cronJob.Spec.JobTemplate.Spec.Template.Image = "busybox"
+
+		dst := SystemWorkload{}
+		err := pkg.Unmarshal(deployment, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "nginx", dst.Template.Image)
+
+		dst = SystemWorkload{}
+		err = pkg.Unmarshal(cronJob, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "CronJob", dst.Kind)
+		assert.Equal(t, "busybox", dst.Template.Image)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal into the path selected by the discriminator", func(t *testing.T) {
+		src := SystemWorkload{Kind: "CronJob", Template: SystemPodTemplate{Image: "busybox"}}
+		dst := APIWorkload{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Empty(t, dst.Spec.Template.Image)
+		assert.Equal(t, "busybox", dst.Spec.JobTemplate.Spec.Template.Image)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should skip the field for unknown discriminator values", func(t *testing.T) {
+		src := APIWorkload{Kind: "StatefulSet"}
+		src.Spec.Template.Image = "nginx"
+		dst := SystemWorkload{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Empty(t, dst.Template.Image)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should use the discriminator field and main path set on the tag", func(t *testing.T) {
+		src := APIWorkload{}
+		src.Meta.Type = "Job"
+		src.Spec.Template.Image = "nginx"
+		src.Spec.JobTemplate.Spec.Template.Image = "busybox"
+		dst := struct {
+			Image string `se:"Spec.Template.Image,kind<Deployment|Job:Spec.JobTemplate.Spec.Template.Image>,kindfield<Meta.Type>"`
+		}{}
+
+		err := pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "busybox", dst.Image)
+
+		src.Meta.Type = "Deployment"
+		err = pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "nginx", dst.Image)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when an entry has no path to map to", func(t *testing.T) {
+		dst := struct {
+			Image string `se:"+,kind<Deployment>"`
+		}{}
+
+		err := pkg.Unmarshal(APIWorkload{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrMissingKindPath)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when combined with conditions", func(t *testing.T) {
+		dst := struct {
+			Image string `se:"+,kind<Deployment:Spec.Template.Image>,when<Meta.Type=x>"`
+		}{}
+
+		err := pkg.Unmarshal(APIWorkload{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidKindPath)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,89 @@
+package pkg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type Quantity struct {
+	value int64
+	unit  string
+}
+
+func NewQuantity(value int64, unit string) Quantity {
+	return Quantity{value: value, unit: unit}
+}
+
+type OtherQuantity struct {
+	value int64
+}
+
+type APILeaves struct {
+	Memory   Quantity
+	Limit    *Quantity
+	Disks    []Quantity
+	Started  time.Time
+	Finished *time.Time
+}
+
+type SystemLeaves struct {
+	Memory   Quantity   `se:"Memory"`
+	Limit    *Quantity  `se:"Limit"`
+	Disks    []Quantity `se:"Disks"`
+	Started  time.Time  `se:"Started"`
+	Finished *time.Time `se:"Finished"`
+}
+
+type SystemMismatchedLeaf struct {
+	Memory OtherQuantity `se:"Memory"`
+}
+
+func init() {
+	pkg.RegisterLeafType[Quantity]()
+	pkg.RegisterLeafType[OtherQuantity]()
+}
+
+func TestLeafTypes(t *testing.T) {
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(time.Hour)
+	limit := NewQuantity(4, "Gi")
+
+	t.Run("should copy leaf structs as a whole", func(t *testing.T) {
+		foreign := APILeaves{
+			Memory:   NewQuantity(2, "Gi"),
+			Limit:    &limit,
+			Disks:    []Quantity{NewQuantity(10, "Gi"), NewQuantity(1, "Ti")},
+			Started:  started,
+			Finished: &finished,
+		}
+
+		local := SystemLeaves{}
+		err := pkg.Unmarshal(foreign, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, SystemLeaves(foreign), local)
+
+		result := APILeaves{}
+		err = pkg.Marshal(local, &result)
+		assert.Nil(t, err)
+		assert.Equal(t, foreign, result)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep nil pointers to leaf structs", func(t *testing.T) {
+		local := SystemLeaves{}
+		err := pkg.Unmarshal(APILeaves{Memory: NewQuantity(1, "Mi")}, &local)
+		assert.Nil(t, err)
+		assert.Nil(t, local.Limit)
+		assert.Nil(t, local.Finished)
+		assert.Equal(t, NewQuantity(1, "Mi"), local.Memory)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail mapping a different type", func(t *testing.T) {
+		err := pkg.Unmarshal(APILeaves{}, &SystemMismatchedLeaf{})
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMismatch)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,88 @@
+package pkg_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestCompiledMapper(t *testing.T) {
+	src := APIObject{
+		Metadata: APIMetadata{NameField: "test", Flag: true},
+		Config: APIConfig{
+			SomeCount: 3,
+			SomeList: []APIListedObj{
+				{List: []string{"a", "b"}, Config: APIListedObjConfig{Direction: "up"}},
+			},
+			SomeList2: []*APIListedObj{
+				{Config: APIListedObjConfig{Direction: "down"}},
+			},
+		},
+	}
+
+	t.Run("should map just like the package functions", func(t *testing.T) {
+		mapper, err := pkg.NewMapper(SystemStruct{}, &APIObject{})
+		assert.Nil(t, err)
+
+		expected := SystemStruct{}
+		assert.Nil(t, pkg.Unmarshal(src, &expected))
+		local := SystemStruct{}
+		err = mapper.Decode(src, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, local)
+
+		expectedForeign := APIObject{}
+		assert.Nil(t, pkg.Marshal(local, &expectedForeign))
+		foreign := APIObject{}
+		err = mapper.Encode(&local, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedForeign, foreign)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not depend on the introspection cache", func(t *testing.T) {
+		mapper, err := pkg.NewMapper(&SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		pkg.ClearTypeCache()
+
+		local := SystemStruct{}
+		err = mapper.Decode(&src, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, "up", local.Nested.Direction)
+		assert.Equal(t, "down", local.NestedPointer.Direction)
+		assert.Equal(t, []string{"a", "b"}, local.ListedStuff)
+		assert.Equal(t, 0, pkg.CacheMemoryUsage().LocalEntries)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error mapping values of other types", func(t *testing.T) {
+		mapper, err := pkg.NewMapper(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+
+		err = mapper.Decode(APIMappedObj{}, &SystemStruct{})
+		assert.ErrorIs(t, err, pkg.ErrMapperTypeMismatch)
+		err = mapper.Encode(SystemMapped{}, &APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrMapperTypeMismatch)
+		err = mapper.Decode(APIObject{}, SystemStruct{})
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error introspecting invalid types", func(t *testing.T) {
+		_, err := pkg.NewMapper(nil, APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrLocalTypeNotStruct)
+
+		_, err = pkg.NewMapper(SystemStruct{}, "foreign")
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeNotStruct)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should apply the provided options", func(t *testing.T) {
+		mapper, err := pkg.NewMapper(SystemStruct{}, APIObject{}, pkg.WithStrict())
+		assert.Nil(t, err)
+
+		err = mapper.Decode(APIObject{Metadata: APIMetadata{NameField: "test"}}, &SystemStruct{})
+		var strictErr *pkg.StrictError
+		assert.True(t, errors.As(err, &strictErr))
+		pkg.ClearTypeCache()
+	})
+}
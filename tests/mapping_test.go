@@ -0,0 +1,206 @@
+package pkg_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+// Mock third-party structs whose source can't be tagged
+type VendorConfig struct {
+	Name     string
+	Enabled  bool
+	Count    int
+	Internal string
+}
+type VendorSettings struct {
+	Name  string
+	Count int
+}
+type VendorBroken struct {
+	Name string
+}
+
+func TestMapping(t *testing.T) {
+	t.Run("should map the fields described by a registered mapping", func(t *testing.T) {
+		err := pkg.NewMapping[VendorConfig, APIObject]().
+			Field("Name").To("Metadata.NameField").
+			Field("Enabled").To("Metadata.Flag").
+			Field("Count").To("Config.SomeCount").Options("required").
+			Field("Internal").Skip().
+			Register()
+		assert.Nil(t, err)
+
+		src := APIObject{Metadata: APIMetadata{NameField: "test", Flag: true}, Config: APIConfig{SomeCount: 3}}
+		dst := VendorConfig{}
+		err = pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, VendorConfig{Name: "test", Enabled: true, Count: 3}, dst)
+
+		out := APIObject{}
+		err = pkg.Marshal(dst, &out)
+		assert.Nil(t, err)
+		assert.Equal(t, src, out)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should map per type paths described by a registered mapping", func(t *testing.T) {
+		err := pkg.NewMapping[VendorSettings, SecondaryAPIObject]().
+			Field("Name").To("Metadata.NameField").
+			Field("Count").Types("APIObject:Config.SomeCount", "SecondaryAPIObject:Child.Direction").
+			Register()
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMismatch)
+
+		err = pkg.NewMapping[VendorSettings, SecondaryAPIObject]().
+			Field("Name").To("Child.Direction").Types("SecondaryAPIObject").
+			Field("Count").Skip().
+			Register()
+		assert.Nil(t, err)
+
+		dst := VendorSettings{}
+		err = pkg.Unmarshal(SecondaryAPIObject{Child: SecondaryAPIObjectChild{Direction: "in"}}, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, VendorSettings{Name: "in"}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not register a mapping failing validation", func(t *testing.T) {
+		err := pkg.NewMapping[VendorBroken, APIObject]().Field("Missing").To("Metadata.NameField").Register()
+		assert.ErrorIs(t, err, pkg.ErrUnknownLocalField)
+
+		err = pkg.NewMapping[VendorBroken, APIObject]().Field("Name").To("Metadata.Missing").Register()
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+
+		err = pkg.Unmarshal(APIObject{}, &VendorBroken{})
+		assert.ErrorIs(t, err, pkg.ErrLocalTypeMissingValidTag)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should map the types with the mapping registered last", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: "name"}, Config: APIConfig{SomeCount: 3}}
+		err := pkg.NewMapping[VendorSettings, APIObject]().
+			Field("Name").To("Metadata.NameField").
+			Field("Count").Skip().
+			Register()
+		assert.Nil(t, err)
+		dst := VendorSettings{}
+		assert.Nil(t, pkg.Unmarshal(src, &dst))
+		assert.Equal(t, VendorSettings{Name: "name"}, dst)
+
+		err = pkg.NewMapping[VendorSettings, APIObject]().
+			Field("Name").Skip().
+			Field("Count").To("Config.SomeCount").
+			Register()
+		assert.Nil(t, err)
+		dst = VendorSettings{}
+		assert.Nil(t, pkg.Unmarshal(src, &dst))
+		assert.Equal(t, VendorSettings{Count: 3}, dst)
+
+		err = pkg.NewMapping[VendorSettings, APIObject]().
+			Field("Name").To("Metadata.Missing").
+			Register()
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		dst = VendorSettings{}
+		assert.Nil(t, pkg.Unmarshal(src, &dst))
+		assert.Equal(t, VendorSettings{Count: 3}, dst)
+		pkg.ClearTypeCache()
+	})
+}
+
+// Mock a generated struct whose tags are carried by a shadow struct
+type GeneratedConfig struct {
+	Name    string
+	Enabled bool `se:"Metadata.Flag"`
+	Count   int
+}
+type GeneratedConfigTags struct {
+	Name  string `se:"Metadata.NameField" se_v2:"Config.SomeList[0].Config.Direction"`
+	Count int    `se:"Config.SomeCount"`
+}
+
+func TestTagSource(t *testing.T) {
+	t.Run("should read the tags of the registered shadow struct", func(t *testing.T) {
+		err := pkg.RegisterTagSource[GeneratedConfig, GeneratedConfigTags]()
+		assert.Nil(t, err)
+
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "test", Flag: true},
+			Config:   APIConfig{SomeCount: 2, SomeList: []APIListedObj{{Config: APIListedObjConfig{Direction: "v2"}}}},
+		}
+		dst := GeneratedConfig{}
+		err = pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, GeneratedConfig{Name: "test", Enabled: true, Count: 2}, dst)
+
+		dst = GeneratedConfig{}
+		err = pkg.UnmarshalWithOptions(src, &dst, pkg.WithTagKey("se_v2"))
+		assert.Nil(t, err)
+		assert.Equal(t, GeneratedConfig{Name: "v2"}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when the shadow struct declares unknown fields", func(t *testing.T) {
+		err := pkg.RegisterTagSource[GeneratedConfig, SystemVersioned]()
+		assert.ErrorIs(t, err, pkg.ErrUnknownLocalField)
+
+		err = pkg.RegisterTagSource[GeneratedConfig, string]()
+		assert.ErrorIs(t, err, pkg.ErrLocalTypeNotStruct)
+	})
+}
+
+type LoadedConfig struct {
+	Name     string
+	Enabled  bool
+	Internal string `se:"Metadata.NameField"`
+}
+
+func TestLoadMapping(t *testing.T) {
+	t.Run("should register a mapping loaded from a document", func(t *testing.T) {
+		doc := `{"fields": [
+			{"name": "Name", "to": "Metadata.Missing||Metadata.NameField"},
+			{"name": "Enabled", "to": "Metadata.Flag", "options": ["required"]},
+			{"name": "Internal", "skip": true}
+		]}`
+
+		mapping, err := pkg.LoadMapping[LoadedConfig, APIObject](strings.NewReader(doc))
+		assert.Nil(t, err)
+		assert.Nil(t, mapping.Register())
+
+		dst := LoadedConfig{}
+		err = pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "test", Flag: true}}, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, LoadedConfig{Name: "test", Enabled: true}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error on invalid documents", func(t *testing.T) {
+		_, err := pkg.LoadMapping[LoadedConfig, APIObject](strings.NewReader(`{"fields": [{"field": "Name"}]}`))
+		assert.ErrorIs(t, err, pkg.ErrInvalidMappingSpec)
+
+		_, err = pkg.LoadMapping[LoadedConfig, APIObject](strings.NewReader(`{"fields": `))
+		assert.ErrorIs(t, err, pkg.ErrInvalidMappingSpec)
+
+		_, err = pkg.LoadMapping[LoadedConfig, APIObject](strings.NewReader("fields:\n  - field: Name\n"))
+		assert.ErrorIs(t, err, pkg.ErrInvalidMappingSpec)
+	})
+	t.Run("should register a mapping loaded from a YAML document", func(t *testing.T) {
+		doc := `
+fields:
+  - name: Name
+    to: Metadata.Missing||Metadata.NameField
+  - name: Enabled
+    to: Metadata.Flag
+    options: [required]
+  - name: Internal
+    skip: true
+`
+
+		mapping, err := pkg.LoadMapping[LoadedConfig, APIObject](strings.NewReader(doc))
+		assert.Nil(t, err)
+		assert.Nil(t, mapping.Register())
+
+		dst := LoadedConfig{}
+		err = pkg.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "test", Flag: true}}, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, LoadedConfig{Name: "test", Enabled: true}, dst)
+		pkg.ClearTypeCache()
+	})
+}
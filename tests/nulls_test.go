@@ -0,0 +1,86 @@
+package pkg_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type DBUser struct {
+	Name      sql.NullString
+	Age       sql.NullInt64
+	Nickname  sql.NullString
+	DeletedAt sql.NullTime
+}
+
+type SystemUser struct {
+	Name      string     `se:"Name"`
+	Age       *int64     `se:"Age"`
+	Nickname  *string    `se:"Nickname"`
+	DeletedAt *time.Time `se:"DeletedAt"`
+}
+
+type APIUser struct {
+	Name string
+	Age  int64
+}
+
+type DBUserEntity struct {
+	Name sql.NullString `se:"Name"`
+	Age  sql.NullInt64  `se:"Age"`
+}
+
+func TestNullableTypes(t *testing.T) {
+	deleted := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	t.Run("should unwrap valid values when unmarshaling", func(t *testing.T) {
+		src := DBUser{
+			Name:      sql.NullString{String: "test", Valid: true},
+			Age:       sql.NullInt64{Int64: 0, Valid: true},
+			Nickname:  sql.NullString{String: "ignored", Valid: false},
+			DeletedAt: sql.NullTime{Time: deleted, Valid: true},
+		}
+		dst := SystemUser{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		assert.Equal(t, int64(0), *dst.Age)
+		assert.Nil(t, dst.Nickname)
+		assert.Equal(t, deleted, *dst.DeletedAt)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should set valid values when marshaling", func(t *testing.T) {
+		age := int64(0)
+		dst := DBUser{}
+
+		err := pkg.Marshal(SystemUser{Name: "test", Age: &age}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, DBUser{
+			Name: sql.NullString{String: "test", Valid: true},
+			Age:  sql.NullInt64{Int64: 0, Valid: true},
+		}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should convert nullable local fields", func(t *testing.T) {
+		api := APIUser{}
+		err := pkg.Marshal(DBUserEntity{Name: sql.NullString{String: "test", Valid: true}}, &api)
+		assert.Nil(t, err)
+		assert.Equal(t, APIUser{Name: "test"}, api)
+
+		entity := DBUserEntity{}
+		err = pkg.Unmarshal(APIUser{Name: "test", Age: 3}, &entity)
+		assert.Nil(t, err)
+		assert.Equal(t, DBUserEntity{
+			Name: sql.NullString{String: "test", Valid: true},
+			Age:  sql.NullInt64{Int64: 3, Valid: true},
+		}, entity)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,74 @@
+package pkg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type APINumbers struct {
+	Replicas int64
+	Port     uint16
+	Ratio    float32
+	Score    float64
+}
+
+type SystemNumbers struct {
+	Replicas int     `se:"Replicas"`
+	Port     int     `se:"Port"`
+	Ratio    float64 `se:"Ratio"`
+	Score    int32   `se:"Score"`
+}
+
+func TestNumericConversion(t *testing.T) {
+	codec := pkg.NewCodec(pkg.WithNumericConversion())
+
+	t.Run("should fail without the option", func(t *testing.T) {
+		err := pkg.Unmarshal(APINumbers{}, &SystemNumbers{})
+
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMismatch)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should convert between numeric types", func(t *testing.T) {
+		local := SystemNumbers{}
+		err := codec.Unmarshal(APINumbers{Replicas: 3, Port: 8080, Ratio: 0.5, Score: 42}, &local)
+		assert.Nil(t, err)
+		assert.Equal(t, SystemNumbers{Replicas: 3, Port: 8080, Ratio: 0.5, Score: 42}, local)
+
+		foreign := APINumbers{}
+		err = codec.Marshal(SystemNumbers{Replicas: 5, Port: 443, Ratio: 0.25, Score: -1}, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, APINumbers{Replicas: 5, Port: 443, Ratio: 0.25, Score: -1}, foreign)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when narrowing overflows", func(t *testing.T) {
+		err := codec.Marshal(SystemNumbers{Port: 70000}, &APINumbers{})
+		assert.ErrorIs(t, err, pkg.ErrNumericOverflow)
+
+		err = codec.Marshal(SystemNumbers{Port: -1}, &APINumbers{})
+		assert.ErrorIs(t, err, pkg.ErrNumericOverflow)
+
+		err = codec.Unmarshal(APINumbers{Score: math.MaxInt64}, &SystemNumbers{})
+		assert.ErrorIs(t, err, pkg.ErrNumericOverflow)
+
+		err = codec.Unmarshal(APINumbers{Score: 1.5}, &SystemNumbers{})
+		assert.ErrorIs(t, err, pkg.ErrNumericOverflow)
+
+		err = codec.Marshal(SystemNumbers{Ratio: math.MaxFloat64}, &APINumbers{})
+		assert.ErrorIs(t, err, pkg.ErrNumericOverflow)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep requiring matching types for other kinds", func(t *testing.T) {
+		local := struct {
+			Replicas string `se:"Replicas"`
+		}{}
+
+		err := codec.Unmarshal(APINumbers{}, &local)
+
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMismatch)
+		pkg.ClearTypeCache()
+	})
+}
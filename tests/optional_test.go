@@ -0,0 +1,78 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemOptional struct {
+	Name      string `se:"Metadata.NameField"`
+	Count     int    `se:"Config.SomeCount,optional"`
+	Direction string `se:"Child.Direction,optional"`
+}
+
+func TestOptionalPaths(t *testing.T) {
+	t.Run("should dismiss optional paths missing from the foreign type", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: "test"}, Config: APIConfig{SomeCount: 2}}
+		dst := SystemOptional{}
+
+		err := pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, SystemOptional{Name: "test", Count: 2}, dst)
+
+		secondary := SecondaryAPIObject{}
+		err = pkg.Marshal(SystemOptional{Name: "test", Count: 2, Direction: "up"}, &secondary)
+		assert.Nil(t, err)
+		assert.Equal(t, SecondaryAPIObject{
+			Metadata: APIMetadata{NameField: "test"},
+			Child:    SecondaryAPIObjectChild{Direction: "up"},
+		}, secondary)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report dismissed optional paths as skipped", func(t *testing.T) {
+		report, err := pkg.UnmarshalWithReport(APIObject{}, &SystemOptional{})
+
+		assert.Nil(t, err)
+		assert.Contains(t, report.Skipped, pkg.FieldSkip{
+			Field:  "Direction",
+			Path:   []string{"Child", "Direction"},
+			Reason: pkg.SkipMissingPath,
+		})
+		pkg.ClearTypeCache()
+	})
+	t.Run("should show dismissed optional paths when explaining", func(t *testing.T) {
+		out, err := pkg.Explain(SystemOptional{}, APIObject{})
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "Direction: skipped, optional path Child.Direction not found in APIObject")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep failing on missing paths that aren't optional", func(t *testing.T) {
+		src := struct {
+			Name string `se:"Child.Name"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{}, &src)
+
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should dismiss every missing path with the codec option", func(t *testing.T) {
+		src := struct {
+			Name      string `se:"Metadata.NameField"`
+			Direction string `se:"Child.Direction"`
+		}{}
+		codec := pkg.NewCodec(pkg.WithIgnoreMissingFields())
+
+		err := codec.Unmarshal(APIObject{Metadata: APIMetadata{NameField: "test"}}, &src)
+		assert.Nil(t, err)
+		assert.Equal(t, "test", src.Name)
+
+		err = pkg.Unmarshal(APIObject{}, &src)
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,97 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemPanicking struct {
+	Direction string `se:"Config.Direction,convert<panicking>"`
+}
+
+func init() {
+	pkg.RegisterNamedConverter("panicking",
+		func(direction string) (string, error) { panic("encoding " + direction) },
+		func(direction string) (string, error) { panic("decoding " + direction) },
+	)
+}
+
+func TestPanicRecovery(t *testing.T) {
+	t.Run("should reject unexported local fields", func(t *testing.T) {
+		dst := struct {
+			name string `se:"Metadata.NameField"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrUnexportedField)
+		assert.ErrorContains(t, err, ".name")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should reject unexported foreign fields", func(t *testing.T) {
+		type Foreign struct {
+			name string
+		}
+		src := struct {
+			Name string `se:"name"`
+		}{Name: "test"}
+
+		err := pkg.Marshal(src, &Foreign{})
+
+		assert.ErrorIs(t, err, pkg.ErrUnexportedField)
+		assert.ErrorContains(t, err, "name in pkg_test.Foreign")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return panics as errors naming the field", func(t *testing.T) {
+		type Local struct {
+			Items []SystemPanicking `se:"Config.SomeList"`
+		}
+		src := APIObject{Config: APIConfig{SomeList: []APIListedObj{{Config: APIListedObjConfig{Direction: "up"}}}}}
+
+		err := pkg.Unmarshal(src, &Local{})
+
+		var fieldErr *pkg.FieldError
+		assert.ErrorIs(t, err, pkg.ErrMappingPanic)
+		assert.ErrorContains(t, err, "decoding up")
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Items[0].Direction", fieldErr.Field)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return panics of concurrently mapped elements as errors", func(t *testing.T) {
+		type Local struct {
+			Items []SystemPanicking `se:"Config.SomeList"`
+		}
+		src := Local{Items: []SystemPanicking{{Direction: "up"}, {Direction: "down"}}}
+
+		err := pkg.NewCodec(pkg.WithParallelSlices(2, 2)).Marshal(src, &APIObject{})
+
+		var fieldErr *pkg.FieldError
+		assert.ErrorIs(t, err, pkg.ErrMappingPanic)
+		assert.ErrorContains(t, err, "encoding up")
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Items[0].Direction", fieldErr.Field)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should reject nil inputs instead of panicking", func(t *testing.T) {
+		assert.ErrorIs(t, pkg.Marshal(nil, &APIObject{}), pkg.ErrUnmarshalSrcType)
+		assert.ErrorIs(t, pkg.Marshal(SystemStruct{}, nil), pkg.ErrUnmarshalDestType)
+		assert.ErrorIs(t, pkg.Unmarshal(nil, &SystemStruct{}), pkg.ErrUnmarshalSrcType)
+		assert.ErrorIs(t, pkg.Unmarshal(APIObject{}, nil), pkg.ErrUnmarshalDestType)
+		assert.ErrorIs(t, pkg.UnmarshalStrict(nil, &SystemStruct{}), pkg.ErrUnmarshalSrcType)
+		_, err := pkg.MarshalWithReport(nil, &APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalSrcType)
+
+		assert.ErrorIs(t, pkg.Introspect(nil, APIObject{}), pkg.ErrLocalTypeNotStruct)
+		assert.ErrorIs(t, pkg.Introspect(SystemStruct{}, nil), pkg.ErrForeignTypeNotStruct)
+		_, err = pkg.IntrospectRepr(nil, nil)
+		assert.ErrorIs(t, err, pkg.ErrLocalTypeNotStruct)
+		_, err = pkg.Explain(SystemStruct{}, nil)
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeNotStruct)
+		_, err = pkg.Describe(nil, APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrLocalTypeNotStruct)
+		pkg.ClearTypeCache()
+	})
+}
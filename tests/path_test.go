@@ -0,0 +1,147 @@
+package pkg_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestResolvePath(t *testing.T) {
+	t.Run("should resolve paths against foreign types", func(t *testing.T) {
+		cached := pkg.CacheMemoryUsage().ForeignEntries
+		target, err := pkg.ResolvePath(reflect.TypeFor[*APIObject](), "Config.SomeList[0].Config.Direction")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Config", "SomeList[0]", "Config", "Direction"}, target.Path)
+		assert.Equal(t, []int{1, 1, 1, 1}, target.IndexPath)
+		assert.Equal(t, reflect.String, target.Kind)
+		assert.Equal(t, reflect.TypeFor[string](), target.Type)
+		assert.False(t, target.IsArray)
+
+		target, err = pkg.ResolvePath(reflect.TypeFor[APIObject](), "Config.SomeList")
+		assert.Nil(t, err)
+		assert.True(t, target.IsArray)
+		assert.Equal(t, reflect.Struct, target.Kind)
+		assert.Equal(t, "APIListedObj", target.TypeName)
+		assert.Equal(t, cached, pkg.CacheMemoryUsage().ForeignEntries, "resolved paths should not be cached")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report paths leading to no field", func(t *testing.T) {
+		_, err := pkg.ResolvePath(reflect.TypeFor[APIObject](), "Config.Missing.Direction")
+		var notFound *pkg.FieldNotFoundError
+		assert.True(t, errors.As(err, &notFound))
+		assert.Equal(t, []string{"Missing", "Direction"}, notFound.Path)
+		assert.Equal(t, "APIObject", notFound.ForeignType)
+
+		_, err = pkg.ResolvePath(reflect.TypeFor[string](), "Config")
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeNotStruct)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestGetPath(t *testing.T) {
+	t.Run("should read the values found at paths", func(t *testing.T) {
+		foreign := APIObject{}
+		foreign.Metadata.NameField = "app"
+		foreign.Config.SomeList = []APIListedObj{
+			{Config: APIListedObjConfig{Direction: "north"}},
+			{Config: APIListedObjConfig{Direction: "south"}},
+		}
+
+		value, ok, err := pkg.GetPath(foreign, "Metadata.NameField")
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "app", value)
+
+		value, ok, err = pkg.GetPath(&foreign, "Config.SomeList[last].Config.Direction")
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "south", value)
+
+		value, ok, err = pkg.GetPath(&foreign, "Config.SomeList[0]")
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, foreign.Config.SomeList[0], value)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report values that can't be reached", func(t *testing.T) {
+		value, ok, err := pkg.GetPath(APIObject{}, "Config.SomePointed.Config.Direction")
+		assert.Nil(t, err)
+		assert.False(t, ok)
+		assert.Nil(t, value)
+
+		_, ok, err = pkg.GetPath(APIObject{}, "Config.SomeList[0].List")
+		assert.Nil(t, err)
+		assert.False(t, ok)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail for invalid paths and values", func(t *testing.T) {
+		_, _, err := pkg.GetPath(APIObject{}, "Config.Missing")
+		var notFound *pkg.FieldNotFoundError
+		assert.True(t, errors.As(err, &notFound))
+		var pathErr *pkg.PathError
+		assert.ErrorAs(t, err, &pathErr)
+		assert.Equal(t, "Missing", pathErr.Segment)
+		assert.EqualError(t, err, "field not found in path: segment Missing of path Config.Missing (type APIObject)")
+
+		_, _, err = pkg.GetPath(APIObject{}, "Config.SomeList[3].List")
+		assert.ErrorIs(t, err, pkg.ErrUnsupportedIndex)
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		assert.ErrorContains(t, err, "[3] in segment SomeList[3] of path Config.SomeList[3].List")
+
+		_, _, err = pkg.GetPath(APIObject{}, "Config.SomeList[*].List")
+		assert.ErrorIs(t, err, pkg.ErrWildcardPath)
+
+		var missing *APIObject
+		_, _, err = pkg.GetPath(missing, "Config")
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeNotStruct)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestSetPath(t *testing.T) {
+	t.Run("should write values at paths, allocating them", func(t *testing.T) {
+		foreign := APIObject{}
+		err := pkg.SetPath(&foreign, "Config.SomePointed.Config.Direction", "north")
+		assert.Nil(t, err)
+		assert.Equal(t, "north", foreign.Config.SomePointed.Config.Direction)
+
+		err = pkg.SetPath(&foreign, "Config.SomeList2[0].List", [2]string{"a", "b"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a", "b"}, foreign.Config.SomeList2[0].List)
+
+		err = pkg.SetPath(&foreign, "Config.SomeList[last]", APIListedObj{List: []string{"c"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []APIListedObj{{List: []string{"c"}}}, foreign.Config.SomeList)
+
+		err = pkg.SetPath(&foreign, "Config.SomeCount", int8(3))
+		assert.Nil(t, err)
+		assert.Equal(t, 3, foreign.Config.SomeCount)
+
+		err = pkg.SetPath(&foreign, "Config.SomePointed", nil)
+		assert.Nil(t, err)
+		assert.Nil(t, foreign.Config.SomePointed)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail for invalid destinations and values", func(t *testing.T) {
+		foreign := APIObject{}
+		err := pkg.SetPath(&foreign, "Metadata.NameField", 3)
+		assert.ErrorIs(t, err, pkg.ErrPathValueType)
+		assert.Equal(t, APIObject{}, foreign)
+
+		err = pkg.SetPath(&foreign, "Config.SomePointed.Config.Direction", 3)
+		assert.ErrorIs(t, err, pkg.ErrPathValueType)
+		assert.Nil(t, foreign.Config.SomePointed, "nothing should be allocated for values that can't be set")
+
+		err = pkg.SetPath(foreign, "Metadata.NameField", "app")
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+
+		err = pkg.SetPath(&foreign, "Config.SomeList[last][2]", APIListedObj{})
+		assert.ErrorIs(t, err, pkg.ErrUnsupportedIndex)
+		assert.ErrorContains(t, err, "[2] in segment SomeList[last][2]")
+		pkg.ClearTypeCache()
+	})
+}
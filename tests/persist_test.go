@@ -0,0 +1,97 @@
+package pkg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestExportCache(t *testing.T) {
+	src := SystemStruct{
+		Name:          "test",
+		Count:         3,
+		Flag:          true,
+		Nested:        SystemNested{Direction: "first", DeeepNested: SystemDeepNested{Direction: "deep"}},
+		NestedPointer: &SystemNested{Direction: "pointed"},
+		ListedStuff:   []string{"a", "b"},
+		StructSlice:   []SystemNestedFromSlice{{Direction: "first"}, {Direction: "second"}},
+	}
+
+	t.Run("should map imported types without introspecting them", func(t *testing.T) {
+		pkg.ClearTypeCache()
+		expected := APIObject{}
+		err := pkg.Marshal(src, &expected)
+		assert.Nil(t, err)
+		described, err := pkg.Describe(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		before, err := json.Marshal(described)
+		assert.Nil(t, err)
+		exported := &bytes.Buffer{}
+		err = pkg.ExportCache(exported)
+		assert.Nil(t, err)
+		pkg.ClearTypeCache()
+
+		err = pkg.ImportCache(exported)
+		assert.Nil(t, err)
+		dst := APIObject{}
+		err = pkg.Marshal(src, &dst)
+		assert.Nil(t, err)
+		decoded := SystemStruct{}
+		err = pkg.Unmarshal(dst, &decoded)
+		assert.Nil(t, err)
+		described, err = pkg.Describe(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		after, err := json.Marshal(described)
+		assert.Nil(t, err)
+
+		assert.Equal(t, expected, dst)
+		assert.Equal(t, src, decoded)
+		assert.JSONEq(t, string(before), string(after))
+		assert.Equal(t, uint64(0), pkg.CacheStats().Misses)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep imported representations in the cache of the codec", func(t *testing.T) {
+		pkg.ClearTypeCache()
+		err := pkg.Introspect(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		exported := &bytes.Buffer{}
+		err = pkg.ExportCache(exported)
+		assert.Nil(t, err)
+		pkg.ClearTypeCache()
+		codec := pkg.NewCodec(pkg.WithPrivateCache())
+
+		err = codec.ImportCache(exported)
+		assert.Nil(t, err)
+		dst := APIObject{}
+		err = codec.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.Equal(t, uint64(0), codec.CacheStats().Misses)
+		assert.Equal(t, pkg.CacheStatistics{}, pkg.CacheStats())
+	})
+	t.Run("should introspect types missing from the imported cache", func(t *testing.T) {
+		pkg.ClearTypeCache()
+		err := pkg.ImportCache(strings.NewReader(`{"Version":1,"Locals":{},"Foreigns":{}}`))
+		assert.Nil(t, err)
+
+		dst := APIObject{}
+		err = pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.Greater(t, pkg.CacheStats().Misses, uint64(0))
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail importing a cache written by an incompatible version", func(t *testing.T) {
+		err := pkg.ImportCache(strings.NewReader(`{"Version":0}`))
+
+		assert.ErrorIs(t, err, pkg.ErrCacheVersion)
+		pkg.ClearTypeCache()
+	})
+}
@@ -1,6 +1,7 @@
 package pkg_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,14 +14,14 @@ func TestStructureAnalysis(t *testing.T) {
 		wrongLocal := "asd"
 		err := pkg.Introspect(&wrongLocal, APIObject{})
 		assert.NotNil(t, err, "Expected error when destination pointed value is not a struct")
-		assert.Equal(t, pkg.ErrLocalTypeNotStruct, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrLocalTypeNotStruct)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should error when foreign is a pointer to a non struct value", func(t *testing.T) {
 		wrongForeign := "asd"
 		err := pkg.Introspect(SystemStruct{}, &wrongForeign)
 		assert.NotNil(t, err, "Expected error when foreign pointed value is not a struct")
-		assert.Equal(t, pkg.ErrForeignTypeNotStruct, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeNotStruct)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should error if destination doesnt have any serializable field", func(t *testing.T) {
@@ -30,7 +31,7 @@ func TestStructureAnalysis(t *testing.T) {
 		}{}
 		err := pkg.Introspect(&invalidStruct, &APIObject{})
 		assert.NotNil(t, err, "Expected error when destination is not a pointer")
-		assert.Equal(t, pkg.ErrLocalTypeMissingValidTag, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrLocalTypeMissingValidTag)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should error if foreign type does not have the pointed field by native type", func(t *testing.T) {
@@ -40,7 +41,58 @@ func TestStructureAnalysis(t *testing.T) {
 		alien := struct{ NotSome string }{}
 		err := pkg.Introspect(native, alien)
 		assert.NotNil(t, err, "Expected error when destination is not a pointer")
-		assert.Contains(t, err.Error(), pkg.ErrForeignTypeMissingField)
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return a typed error describing the missing path", func(t *testing.T) {
+		native := struct {
+			Name string `se:"Metadata.Missing"`
+		}{}
+
+		err := pkg.Introspect(native, APIObject{})
+
+		var notFound *pkg.FieldNotFoundError
+		assert.True(t, errors.As(err, &notFound))
+		assert.Equal(t, "Name", notFound.LocalField)
+		assert.Equal(t, []string{"Metadata", "Missing"}, notFound.Path)
+		assert.Equal(t, "APIObject", notFound.ForeignType)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when a wildcard path is mapped to a non slice field", func(t *testing.T) {
+		native := struct {
+			Direction string `se:"Config.SomeList[*].Config.Direction"`
+		}{}
+		err := pkg.Introspect(native, APIObject{})
+		assert.NotNil(t, err)
+		assert.ErrorIs(t, err, pkg.ErrWildcardLocalType)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when mapping arrays of different lengths", func(t *testing.T) {
+		native := struct {
+			Tags [2]string `se:"Tags"`
+		}{}
+		err := pkg.Introspect(native, APIArrayObj{})
+		assert.ErrorIs(t, err, pkg.ErrArrayLengthMismatch)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when nested slices hold mismatching types", func(t *testing.T) {
+		native := struct {
+			Grid [][]int `se:"Grid"`
+		}{}
+		err := pkg.Introspect(native, APIMatrixObj{})
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMismatch)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when the merge key is not a mapped field of the slice elements", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Config.Direction"`
+			Local     string
+		}
+		native := struct {
+			Items []Item `se:"Config.SomeList,mergekey<Local>"`
+		}{}
+		err := pkg.Introspect(native, APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrInvalidMergeKey)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should error if native field points to a missmatching type", func(t *testing.T) {
@@ -50,7 +102,7 @@ func TestStructureAnalysis(t *testing.T) {
 		alien := struct{ Some int }{}
 		err := pkg.Introspect(native, alien)
 		assert.NotNil(t, err, "Expected error when destination is not a pointer")
-		assert.Contains(t, err.Error(), pkg.ErrForeignTypeMismatch)
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMismatch)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should accept a list of types to assert", func(t *testing.T) {
@@ -83,7 +135,7 @@ func TestStructureAnalysis(t *testing.T) {
 		}
 
 		err := pkg.Introspect(LocalStruct{}, APIObject{})
-		assert.ErrorContains(t, err, pkg.ErrInvalidPerTypePath)
+		assert.ErrorIs(t, err, pkg.ErrInvalidPerTypePath)
 		pkg.ClearTypeCache()
 	})
 }
@@ -98,14 +150,14 @@ func TestUnmarshal(t *testing.T) {
 		var emptyDst SystemStruct
 		err := pkg.Unmarshal(APIObject{}, emptyDst)
 		assert.NotNil(t, err, "Expected error when destination is not a pointer")
-		assert.Equal(t, pkg.ErrUnmarshalDestType, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should error when destination is nil", func(t *testing.T) {
 		var emptyDst *SystemStruct
 		err := pkg.Unmarshal(APIObject{}, emptyDst)
 		assert.NotNil(t, err, "Expected error when destination pointed value is nil")
-		assert.Equal(t, pkg.ErrUnmarshalDestType, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should accept a struct value as source", func(t *testing.T) {
@@ -117,14 +169,14 @@ func TestUnmarshal(t *testing.T) {
 		badDst := name
 		err := pkg.Unmarshal(APIObject{}, &badDst)
 		assert.NotNil(t, err, "Expected error when destination pointed value is not a struct")
-		assert.Equal(t, pkg.ErrUnmarshalDestType, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should error when source is a pointer to a non struct value", func(t *testing.T) {
 		badSrc := name
 		err := pkg.Unmarshal(&badSrc, &SystemStruct{})
 		assert.NotNil(t, err, "Expected error when source pointed value is not a struct")
-		assert.Equal(t, pkg.ErrUnmarshalSrcType, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalSrcType)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should accept a pointer to a non nil struct as source", func(t *testing.T) {
@@ -136,7 +188,7 @@ func TestUnmarshal(t *testing.T) {
 		var emptySrc *APIObject
 		err := pkg.Unmarshal(emptySrc, &SystemStruct{})
 		assert.NotNil(t, err, "Expected error when destination pointed value is nil")
-		assert.Equal(t, pkg.ErrUnmarshalSrcType, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalSrcType)
 		pkg.ClearTypeCache()
 	})
 	t.Run("correct unmarshal", func(t *testing.T) {
@@ -290,6 +342,354 @@ func TestUnmarshal(t *testing.T) {
 		assert.Equal(t, dontReplace, dst.NestedPointer.Direction)
 		pkg.ClearTypeCache()
 	})
+	t.Run("should unmarshal every element of a foreign slice into the local slice", func(t *testing.T) {
+		dst := &SystemStruct{
+			StructSlice: []SystemNestedFromSlice{
+				{Direction: "should-replace"},
+				{Direction: "should-replace"},
+				{Direction: "should-replace"},
+				{Direction: "should-drop"},
+			},
+		}
+		src := APIObject{
+			Config: APIConfig{
+				SomeList: []APIListedObj{
+					{Config: APIListedObjConfig{Direction: "first"}},
+					{Config: APIListedObjConfig{Direction: "second"}},
+					{Config: APIListedObjConfig{Direction: "third"}},
+				},
+			},
+		}
+
+		err := pkg.Unmarshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Len(t, dst.StructSlice, 3)
+		assert.Equal(t, "first", dst.StructSlice[0].Direction)
+		assert.Equal(t, "second", dst.StructSlice[1].Direction)
+		assert.Equal(t, "third", dst.StructSlice[2].Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should leave nil elements of foreign slices of pointers as zero values", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Config.Direction"`
+		}
+		type Local struct {
+			Items []Item `se:"Config.SomeList2"`
+		}
+		dst := &Local{}
+		src := APIObject{
+			Config: APIConfig{
+				SomeList2: []*APIListedObj{
+					{Config: APIListedObjConfig{Direction: "first"}},
+					nil,
+					{Config: APIListedObjConfig{Direction: "third"}},
+				},
+			},
+		}
+
+		err := pkg.Unmarshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Item{{Direction: "first"}, {}, {Direction: "third"}}, dst.Items)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should only unmarshal the first element when the path uses an index", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Config.Direction"`
+		}
+		type Local struct {
+			Items []Item `se:"Config.SomeList[0]"`
+		}
+		dst := &Local{}
+		src := APIObject{
+			Config: APIConfig{
+				SomeList: []APIListedObj{
+					{Config: APIListedObjConfig{Direction: "first"}},
+					{Config: APIListedObjConfig{Direction: "second"}},
+				},
+			},
+		}
+
+		err := pkg.Unmarshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Item{{Direction: "first"}}, dst.Items)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should unmarshal a field of every foreign slice element using a wildcard", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Direction"`
+		}
+		type Local struct {
+			Directions []string `se:"Config.SomeList[*].Config.Direction"`
+			Items      []Item   `se:"Config.SomeList2[*].Config"`
+		}
+		dst := &Local{}
+		src := APIObject{
+			Config: APIConfig{
+				SomeList: []APIListedObj{
+					{Config: APIListedObjConfig{Direction: "first"}},
+					{},
+					{Config: APIListedObjConfig{Direction: "third"}},
+				},
+				SomeList2: []*APIListedObj{
+					{Config: APIListedObjConfig{Direction: "up"}},
+					{Config: APIListedObjConfig{Direction: "down"}},
+				},
+			},
+		}
+
+		err := pkg.Unmarshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"first", "", "third"}, dst.Directions)
+		assert.Equal(t, []Item{{Direction: "up"}, {Direction: "down"}}, dst.Items)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should unmarshal the last element of a foreign slice", func(t *testing.T) {
+		type Local struct {
+			Latest   string `se:"Config.SomeList[last].Config.Direction"`
+			Previous string `se:"Config.SomeList2[-1].Config.Direction"`
+		}
+		dst := &Local{}
+		src := APIObject{
+			Config: APIConfig{
+				SomeList: []APIListedObj{
+					{Config: APIListedObjConfig{Direction: "first"}},
+					{Config: APIListedObjConfig{Direction: "last"}},
+				},
+				SomeList2: []*APIListedObj{
+					{Config: APIListedObjConfig{Direction: "up"}},
+					{Config: APIListedObjConfig{Direction: "down"}},
+				},
+			},
+		}
+
+		err := pkg.Unmarshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "last", dst.Latest)
+		assert.Equal(t, "down", dst.Previous)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should copy every entry of foreign maps", func(t *testing.T) {
+		dst := &SystemMapped{
+			Labels: map[string]string{"dropped": "value"},
+		}
+		src := APIMappedObj{
+			Values: map[string]*APIMappedObjValue{
+				"first":  {Direction: "up", Count: 1},
+				"second": {Direction: "down"},
+				"empty":  nil,
+			},
+		}
+		src.Metadata.Labels = map[string]string{"app": "test", "tier": "backend"}
+
+		err := pkg.Unmarshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"app": "test", "tier": "backend"}, dst.Labels)
+		assert.Equal(t, map[string]SystemMappedValue{
+			"first":  {Direction: "up"},
+			"second": {Direction: "down"},
+			"empty":  {},
+		}, dst.Values)
+
+		src.Metadata.Labels["app"] = "changed"
+		assert.Equal(t, "test", dst.Labels["app"])
+		pkg.ClearTypeCache()
+	})
+	t.Run("should unmarshal fixed-size arrays", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Config.Direction"`
+		}
+		type Local struct {
+			Tags      []string `se:"Tags"`
+			Items     []Item   `se:"Items"`
+			Direction string   `se:"Items.Config.Direction"`
+		}
+		type LocalArray struct {
+			Items [3]Item `se:"Config.SomeList"`
+		}
+		dst := &Local{}
+		src := APIArrayObj{
+			Tags: [3]string{"a", "b", "c"},
+			Items: [2]APIListedObj{
+				{Config: APIListedObjConfig{Direction: "up"}},
+				{Config: APIListedObjConfig{Direction: "down"}},
+			},
+		}
+		arrayDst := &LocalArray{Items: [3]Item{{}, {}, {Direction: "stale"}}}
+		arraySrc := APIObject{
+			Config: APIConfig{SomeList: src.Items[:]},
+		}
+
+		err := pkg.Unmarshal(src, dst)
+		arrayErr := pkg.Unmarshal(arraySrc, arrayDst)
+
+		assert.Nil(t, err)
+		assert.Nil(t, arrayErr)
+		assert.Equal(t, []string{"a", "b", "c"}, dst.Tags)
+		assert.Equal(t, []Item{{Direction: "up"}, {Direction: "down"}}, dst.Items)
+		assert.Equal(t, "up", dst.Direction)
+		assert.Equal(t, [3]Item{{Direction: "up"}, {Direction: "down"}, {}}, arrayDst.Items)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when a foreign slice doesn't fit a local array", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Config.Direction"`
+		}
+		type Local struct {
+			Items [1]Item `se:"Config.SomeList"`
+		}
+		src := APIObject{
+			Config: APIConfig{SomeList: []APIListedObj{{}, {}}},
+		}
+
+		err := pkg.Unmarshal(src, &Local{})
+
+		assert.ErrorIs(t, err, pkg.ErrArrayOverflow)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should traverse nested slices", func(t *testing.T) {
+		dst := &SystemMatrix{}
+		src := APIMatrixObj{
+			Matrix: [][]APIListedObj{
+				{{Config: APIListedObjConfig{Direction: "first"}}},
+				{{}, {Config: APIListedObjConfig{Direction: "last"}}},
+			},
+			Grid: [][]string{{"a", "b"}, {"c"}},
+		}
+
+		err := pkg.Unmarshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "first", dst.First)
+		assert.Equal(t, "last", dst.Last)
+		assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, dst.Grid)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should merge slice elements by key into existing destinations", func(t *testing.T) {
+		type Item struct {
+			Direction string   `se:"Config.Direction"`
+			List      []string `se:"List"`
+			State     string
+		}
+		type Local struct {
+			Items []Item `se:"Config.SomeList,mergekey<Direction>"`
+		}
+		dst := &Local{Items: []Item{
+			{Direction: "up", State: "kept"},
+			{Direction: "removed", State: "dropped"},
+			{Direction: "down", State: "kept"},
+		}}
+		src := APIObject{
+			Config: APIConfig{
+				SomeList: []APIListedObj{
+					{List: []string{"new"}, Config: APIListedObjConfig{Direction: "left"}},
+					{List: []string{"down"}, Config: APIListedObjConfig{Direction: "down"}},
+					{List: []string{"up"}, Config: APIListedObjConfig{Direction: "up"}},
+				},
+			},
+		}
+
+		err := pkg.Unmarshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Item{
+			{Direction: "up", List: []string{"up"}, State: "kept"},
+			{Direction: "down", List: []string{"down"}, State: "kept"},
+			{Direction: "left", List: []string{"new"}},
+		}, dst.Items)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should allocate and return the destination when using UnmarshalNew", func(t *testing.T) {
+		src := APIObject{
+			Metadata: APIMetadata{
+				NameField: name,
+				Flag:      flag,
+			},
+			Config: APIConfig{
+				SomeCount: count,
+			},
+		}
+
+		dst, err := pkg.UnmarshalNew[SystemStruct](src)
+
+		assert.Nil(t, err)
+		assert.Equal(t, name, dst.Name)
+		assert.Equal(t, flag, dst.Flag)
+		assert.Equal(t, count, dst.Count)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return the zero value when UnmarshalNew fails", func(t *testing.T) {
+		dst, err := pkg.UnmarshalNew[string](APIObject{})
+
+		assert.NotNil(t, err)
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		assert.Equal(t, "", dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should allocate and return the destination when using MapInto", func(t *testing.T) {
+		src := APIObject{Metadata: APIMetadata{NameField: name, Flag: flag}}
+
+		dst, err := pkg.MapInto[APIObject, SystemStruct](src)
+
+		assert.Nil(t, err)
+		assert.Equal(t, name, dst.Name)
+		assert.Equal(t, flag, dst.Flag)
+
+		_, err = pkg.MapInto[APIObject, string](src)
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should map same-named fields with the = shortcut", func(t *testing.T) {
+		dst := struct {
+			Metadata struct {
+				NameField string `se:"="`
+				Flag      bool   `se:"="`
+			} `se:"Metadata"`
+			Config struct {
+				SomeCount int `se:"="`
+			} `se:"="`
+		}{}
+		src := APIObject{
+			Metadata: APIMetadata{NameField: name, Flag: flag},
+			Config:   APIConfig{SomeCount: count},
+		}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, name, dst.Metadata.NameField)
+		assert.Equal(t, flag, dst.Metadata.Flag)
+		assert.Equal(t, count, dst.Config.SomeCount)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should merge every source when given a slice of sources", func(t *testing.T) {
+		dst := SystemStructWithMultipleDestination{}
+		primary := APIObject{Metadata: APIMetadata{NameField: name, Flag: false}}
+		secondary := SecondaryAPIObject{ConfigFlag: flag}
+		secondary.Child.Direction = direction
+
+		err := pkg.Unmarshal([]any{primary, &secondary}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, name, dst.Name)
+		assert.Equal(t, flag, dst.Flag)
+		assert.Equal(t, direction, dst.DismissNested.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report the source that failed when given a slice of sources", func(t *testing.T) {
+		var nilSrc *SecondaryAPIObject
+
+		err := pkg.Unmarshal([]any{APIObject{}, nilSrc}, &SystemStructWithMultipleDestination{})
+
+		assert.ErrorContains(t, err, "source 1 (*pkg_test.SecondaryAPIObject)")
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalSrcType)
+		pkg.ClearTypeCache()
+	})
 }
 
 func TestMarshal(t *testing.T) {
@@ -315,14 +715,14 @@ func TestMarshal(t *testing.T) {
 		var emptyDst APIObject
 		err := pkg.Marshal(SystemStruct{}, emptyDst)
 		assert.NotNil(t, err, "Expected error when destination is not a pointer")
-		assert.Equal(t, pkg.ErrUnmarshalDestType, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should error when destination is nil", func(t *testing.T) {
 		var emptyDst *APIObject
 		err := pkg.Marshal(SystemStruct{}, emptyDst)
 		assert.NotNil(t, err, "Expected error when destination pointed value is nil")
-		assert.Equal(t, pkg.ErrUnmarshalDestType, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should accept a struct value as source", func(t *testing.T) {
@@ -334,14 +734,14 @@ func TestMarshal(t *testing.T) {
 		emptyDst := name
 		err := pkg.Marshal(APIObject{}, &emptyDst)
 		assert.NotNil(t, err, "Expected error when destination pointed value is not a struct")
-		assert.Equal(t, pkg.ErrUnmarshalDestType, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should error when source is a pointer to a non struct value", func(t *testing.T) {
 		badSrc := name
 		err := pkg.Marshal(&badSrc, &APIObject{})
 		assert.NotNil(t, err, "Expected error when source pointed value is not a struct")
-		assert.Equal(t, pkg.ErrUnmarshalSrcType, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalSrcType)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should accept a pointer to a non nil struct as source", func(t *testing.T) {
@@ -353,7 +753,7 @@ func TestMarshal(t *testing.T) {
 		var emptySrc *APIObject
 		err := pkg.Marshal(emptySrc, &SystemStruct{})
 		assert.NotNil(t, err, "Expected error when destination pointed value is nil")
-		assert.Equal(t, pkg.ErrUnmarshalSrcType, err.Error())
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalSrcType)
 		pkg.ClearTypeCache()
 	})
 	t.Run("should marshal source into destination", func(t *testing.T) {
@@ -369,4 +769,280 @@ func TestMarshal(t *testing.T) {
 		assert.Equal(t, direction, dst.Config.SomeList2[0].Config.Direction)
 		pkg.ClearTypeCache()
 	})
+	t.Run("should marshal every element of a slice into the foreign slice", func(t *testing.T) {
+		dst := &APIObject{}
+		src := SystemStruct{
+			Nested:      SystemNested{Direction: direction},
+			ListedStuff: list,
+			StructSlice: []SystemNestedFromSlice{
+				{Direction: "first"},
+				{Direction: "second"},
+				{Direction: "third"},
+			},
+		}
+
+		err := pkg.Marshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Len(t, dst.Config.SomeList, 3)
+		assert.Equal(t, "first", dst.Config.SomeList[0].Config.Direction)
+		assert.Equal(t, "second", dst.Config.SomeList[1].Config.Direction)
+		assert.Equal(t, "third", dst.Config.SomeList[2].Config.Direction)
+		assert.Equal(t, list, dst.Config.SomeList[0].List)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should grow existing foreign slices and slices of pointers", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Config.Direction"`
+		}
+		type Local struct {
+			Items []Item `se:"Config.SomeList2"`
+		}
+		dst := &APIObject{
+			Config: APIConfig{
+				SomeList2: []*APIListedObj{{List: list}},
+			},
+		}
+
+		err := pkg.Marshal(Local{Items: []Item{{Direction: "first"}, {Direction: "second"}}}, dst)
+
+		assert.Nil(t, err)
+		assert.Len(t, dst.Config.SomeList2, 2)
+		assert.Equal(t, list, dst.Config.SomeList2[0].List)
+		assert.Equal(t, "first", dst.Config.SomeList2[0].Config.Direction)
+		assert.Equal(t, "second", dst.Config.SomeList2[1].Config.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal every slice element into a field of the foreign elements using a wildcard", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Direction"`
+		}
+		type Local struct {
+			Directions []string `se:"Config.SomeList[*].Config.Direction"`
+			Items      []Item   `se:"Config.SomeList2[*].Config"`
+		}
+		dst := &APIObject{
+			Config: APIConfig{
+				SomeList: []APIListedObj{{List: list}},
+			},
+		}
+		src := Local{
+			Directions: []string{"first", "second"},
+			Items:      []Item{{Direction: "up"}, {Direction: "down"}},
+		}
+
+		err := pkg.Marshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Len(t, dst.Config.SomeList, 2)
+		assert.Equal(t, list, dst.Config.SomeList[0].List)
+		assert.Equal(t, "first", dst.Config.SomeList[0].Config.Direction)
+		assert.Equal(t, "second", dst.Config.SomeList[1].Config.Direction)
+		assert.Len(t, dst.Config.SomeList2, 2)
+		assert.Equal(t, "up", dst.Config.SomeList2[0].Config.Direction)
+		assert.Equal(t, "down", dst.Config.SomeList2[1].Config.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal into the last element of a foreign slice", func(t *testing.T) {
+		type Local struct {
+			Latest string `se:"Config.SomeList[last].Config.Direction"`
+			Empty  string `se:"Config.SomeList2[-1].Config.Direction"`
+		}
+		dst := &APIObject{
+			Config: APIConfig{
+				SomeList: []APIListedObj{{List: list}, {List: list}},
+			},
+		}
+
+		err := pkg.Marshal(Local{Latest: direction, Empty: direction}, dst)
+
+		assert.Nil(t, err)
+		assert.Len(t, dst.Config.SomeList, 2)
+		assert.Equal(t, "", dst.Config.SomeList[0].Config.Direction)
+		assert.Equal(t, direction, dst.Config.SomeList[1].Config.Direction)
+		assert.Len(t, dst.Config.SomeList2, 1)
+		assert.Equal(t, direction, dst.Config.SomeList2[0].Config.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should copy every entry of local maps into new foreign maps", func(t *testing.T) {
+		dst := &APIMappedObj{
+			Values: map[string]*APIMappedObjValue{
+				"first":   {Direction: "down", Count: 1},
+				"dropped": {Direction: "down"},
+			},
+		}
+		src := SystemMapped{
+			Labels: map[string]string{"app": "test"},
+			Values: map[string]SystemMappedValue{
+				"first":  {Direction: "up"},
+				"second": {Direction: "down"},
+			},
+		}
+
+		err := pkg.Marshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"app": "test"}, dst.Metadata.Labels)
+		assert.Len(t, dst.Values, 2)
+		assert.Equal(t, APIMappedObjValue{Direction: "up", Count: 1}, *dst.Values["first"])
+		assert.Equal(t, APIMappedObjValue{Direction: "down"}, *dst.Values["second"])
+
+		src.Labels["app"] = "changed"
+		assert.Equal(t, "test", dst.Metadata.Labels["app"])
+		pkg.ClearTypeCache()
+	})
+	t.Run("should convert map keys of named types", func(t *testing.T) {
+		type LabelKey string
+		type Local struct {
+			Labels map[LabelKey]string `se:"Metadata.Labels"`
+		}
+		dst := &APIMappedObj{}
+
+		err := pkg.Marshal(Local{Labels: map[LabelKey]string{"app": "test"}}, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"app": "test"}, dst.Metadata.Labels)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should reject map keys of a different class", func(t *testing.T) {
+		type Foreign struct {
+			Codes map[int]string
+		}
+		type Local struct {
+			Codes map[string]string `se:"Codes"`
+		}
+		dst := &Local{}
+
+		unmarshalErr := pkg.Unmarshal(Foreign{Codes: map[int]string{65: "a"}}, dst)
+		marshalErr := pkg.Marshal(Local{Codes: map[string]string{"A": "a"}}, &Foreign{})
+
+		assert.ErrorIs(t, unmarshalErr, pkg.ErrForeignTypeMismatch)
+		assert.ErrorContains(t, unmarshalErr, "map key int is not string")
+		assert.ErrorIs(t, marshalErr, pkg.ErrForeignTypeMismatch)
+		assert.Nil(t, dst.Codes)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should convert integer map keys of different sizes", func(t *testing.T) {
+		type Foreign struct {
+			Codes map[int64]string
+		}
+		type Local struct {
+			Codes map[int8]string `se:"Codes"`
+		}
+		dst := &Local{}
+
+		err := pkg.Unmarshal(Foreign{Codes: map[int64]string{65: "a"}}, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[int8]string{65: "a"}, dst.Codes)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal into fixed-size arrays", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Config.Direction"`
+		}
+		type Local struct {
+			Tags      []string `se:"Tags"`
+			Items     []Item   `se:"Items"`
+			Direction string   `se:"Items[last].Config.Direction"`
+		}
+		dst := &APIArrayObj{Tags: [3]string{"x", "y", "z"}}
+
+		err := pkg.Marshal(Local{Tags: []string{"a", "b"}, Items: []Item{{Direction: "up"}}, Direction: "down"}, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, [3]string{"a", "b", ""}, dst.Tags)
+		assert.Equal(t, "up", dst.Items[0].Config.Direction)
+		assert.Equal(t, "down", dst.Items[1].Config.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when local elements don't fit a foreign array", func(t *testing.T) {
+		type Local struct {
+			Tags []string `se:"Tags"`
+		}
+
+		err := pkg.Marshal(Local{Tags: []string{"a", "b", "c", "d"}}, &APIArrayObj{})
+
+		assert.ErrorIs(t, err, pkg.ErrArrayOverflow)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should traverse and allocate nested slices", func(t *testing.T) {
+		dst := &APIMatrixObj{}
+		src := SystemMatrix{First: "first", Grid: [][]string{{"a"}, {"b", "c"}}}
+
+		err := pkg.Marshal(src, dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "first", dst.Matrix[0][0].Config.Direction)
+		assert.Equal(t, [][]string{{"a"}, {"b", "c"}}, dst.Grid)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should only marshal the first element when the path uses an index", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Config.Direction"`
+		}
+		type Local struct {
+			Items []Item `se:"Config.SomeList[0]"`
+		}
+		dst := &APIObject{}
+
+		err := pkg.Marshal(Local{Items: []Item{{Direction: "first"}, {Direction: "second"}}}, dst)
+
+		assert.Nil(t, err)
+		assert.Len(t, dst.Config.SomeList, 1)
+		assert.Equal(t, "first", dst.Config.SomeList[0].Config.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should map same-named fields with the = shortcut", func(t *testing.T) {
+		src := struct {
+			Metadata struct {
+				NameField string `se:"="`
+			} `se:"="`
+		}{}
+		src.Metadata.NameField = name
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, name, dst.Metadata.NameField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should allocate and return the destination when using MapTo", func(t *testing.T) {
+		dst, err := pkg.MapTo[APIObject](SystemStruct{Name: name, Count: count})
+
+		assert.Nil(t, err)
+		assert.Equal(t, name, dst.Metadata.NameField)
+		assert.Equal(t, count, dst.Config.SomeCount)
+
+		_, err = pkg.MapTo[string](SystemStruct{Name: name})
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should populate every destination with MarshalAll", func(t *testing.T) {
+		src := SystemStructWithMultipleDestination{Name: name, Flag: flag}
+		src.DismissNested.Direction = "up"
+		primary := APIObject{}
+		secondary := SecondaryAPIObject{}
+
+		err := pkg.MarshalAll(src, &primary, &secondary)
+
+		assert.Nil(t, err)
+		assert.Equal(t, name, primary.Metadata.NameField)
+		assert.Equal(t, flag, primary.Metadata.Flag)
+		assert.Equal(t, name, secondary.Metadata.NameField)
+		assert.Equal(t, flag, secondary.ConfigFlag)
+		assert.False(t, secondary.Metadata.Flag)
+		assert.Equal(t, "up", secondary.Child.Direction)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report the destination MarshalAll failed on", func(t *testing.T) {
+		src := SystemStructWithMultipleDestination{Name: name}
+
+		err := pkg.MarshalAll(src, &APIObject{}, SecondaryAPIObject{})
+
+		assert.ErrorContains(t, err, "destination 1 (pkg_test.SecondaryAPIObject)")
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		pkg.ClearTypeCache()
+	})
 }
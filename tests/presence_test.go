@@ -0,0 +1,73 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestUnmarshalPresence(t *testing.T) {
+	t.Run("should return every populated local field", func(t *testing.T) {
+		dst := SystemStruct{Count: 5}
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "test"},
+			Config: APIConfig{
+				SomeList: []APIListedObj{
+					{Config: APIListedObjConfig{Direction: "up"}},
+					{Config: APIListedObjConfig{Direction: "down"}},
+				},
+			},
+		}
+
+		presence, err := pkg.UnmarshalPresence(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"Name",
+			"Nested.Direction",
+			"StructSlice",
+			"StructSlice[0].Direction",
+			"StructSlice[1].Direction",
+		}, presence.Paths())
+		assert.True(t, presence.Has("Name"))
+		assert.False(t, presence.Has("Count"))
+		assert.Equal(t, 5, dst.Count)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should include maps along with the fields of their values", func(t *testing.T) {
+		dst := SystemMapped{}
+		src := APIMappedObj{
+			Values: map[string]*APIMappedObjValue{"first": {Direction: "up"}},
+		}
+
+		presence, err := pkg.UnmarshalPresence(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Values", "Values[first].Direction"}, presence.Paths())
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return the decoding errors", func(t *testing.T) {
+		presence, err := pkg.UnmarshalPresence(APIObject{}, SystemStruct{})
+
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		assert.Nil(t, presence)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return the fields populated from any of several sources", func(t *testing.T) {
+		dst := SystemStructWithMultipleDestination{}
+		primary := APIObject{Metadata: APIMetadata{NameField: "test"}}
+		secondary := SecondaryAPIObject{ConfigFlag: true}
+		secondary.Child.Direction = "up"
+
+		presence, err := pkg.UnmarshalPresence([]any{primary, &secondary}, &dst)
+
+		assert.Nil(t, err)
+		assert.True(t, presence.Has("Name"))
+		assert.True(t, presence.Has("Flag"))
+		assert.True(t, presence.Has("DismissNested.Direction"))
+		assert.Equal(t, "up", dst.DismissNested.Direction)
+		pkg.ClearTypeCache()
+	})
+}
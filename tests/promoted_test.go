@@ -0,0 +1,121 @@
+package pkg_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+// Mock foreign types embedding their metadata, as Kubernetes API types do
+type APITypeMeta struct {
+	Kind       string
+	APIVersion string
+}
+type APIObjectMeta struct {
+	Name   string
+	Labels map[string]string
+}
+type APIEmbeddedSpec struct {
+	Replicas int
+	Items    []APIListedObj
+}
+type APIEmbedding struct {
+	APITypeMeta
+	*APIObjectMeta
+	Spec APIEmbeddedSpec
+}
+type APINamed struct {
+	Name string
+}
+type APIAmbiguous struct {
+	APIObjectMeta
+	APINamed
+}
+
+type SystemPromoted struct {
+	Kind     string            `se:"Kind"`
+	Name     string            `se:"Name"`
+	Labels   map[string]string `se:"APIObjectMeta.Labels"`
+	Replicas int               `se:"Spec.Replicas"`
+}
+
+func TestPromotedFields(t *testing.T) {
+	t.Run("should decode fields promoted through embedded structs", func(t *testing.T) {
+		src := APIEmbedding{
+			APITypeMeta:   APITypeMeta{Kind: "Deployment"},
+			APIObjectMeta: &APIObjectMeta{Name: "test", Labels: map[string]string{"app": "test"}},
+			Spec:          APIEmbeddedSpec{Replicas: 2},
+		}
+		dst := SystemPromoted{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, SystemPromoted{
+			Kind:     "Deployment",
+			Name:     "test",
+			Labels:   map[string]string{"app": "test"},
+			Replicas: 2,
+		}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should encode fields promoted through embedded structs, allocating pointers", func(t *testing.T) {
+		src := SystemPromoted{Kind: "Deployment", Name: "test", Replicas: 2}
+		dst := APIEmbedding{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Deployment", dst.Kind)
+		assert.Equal(t, "test", dst.Name)
+		assert.Equal(t, 2, dst.Spec.Replicas)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should resolve nested fields under promoted paths", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Config.Direction"`
+		}
+		type Local struct {
+			Items []Item `se:"Items"`
+		}
+		type Foreign struct {
+			APIEmbeddedSpec
+		}
+		src := Foreign{APIEmbeddedSpec{Items: []APIListedObj{{Config: APIListedObjConfig{Direction: "up"}}}}}
+		dst := Local{}
+
+		err := pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, Local{Items: []Item{{Direction: "up"}}}, dst)
+
+		foreign := Foreign{}
+		err = pkg.Marshal(dst, &foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, src, foreign)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report the path fields are promoted through", func(t *testing.T) {
+		target, err := pkg.ResolvePath(reflect.TypeFor[APIEmbedding](), "Name")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"APIObjectMeta", "Name"}, target.Path)
+		value, ok, err := pkg.GetPath(APIEmbedding{APIObjectMeta: &APIObjectMeta{Name: "test"}}, "Name")
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "test", value)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not resolve ambiguous promoted fields", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"Name"`
+		}{}
+
+		err := pkg.Unmarshal(APIAmbiguous{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrForeignTypeMissingField)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,118 @@
+package pkg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+	_ "github.com/ilexPar/struct-marshal/protoconv"
+)
+
+type APIProtoEvent struct {
+	CreateTime *timestamppb.Timestamp
+	DeleteTime *timestamppb.Timestamp
+}
+
+type SystemEvent struct {
+	Created time.Time  `se:"CreateTime"`
+	Deleted *time.Time `se:"DeleteTime"`
+}
+
+func TestProtoTimestamps(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	t.Run("should convert timestamps into times when unmarshaling", func(t *testing.T) {
+		src := APIProtoEvent{CreateTime: timestamppb.New(created), DeleteTime: timestamppb.New(created)}
+		dst := SystemEvent{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, created, dst.Created)
+		assert.Equal(t, created, *dst.Deleted)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should skip nil timestamps", func(t *testing.T) {
+		dst := SystemEvent{}
+
+		err := pkg.Unmarshal(APIProtoEvent{}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, SystemEvent{}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should allocate timestamps when marshaling", func(t *testing.T) {
+		dst := APIProtoEvent{}
+
+		err := pkg.Marshal(SystemEvent{Created: created}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, created, dst.CreateTime.AsTime())
+		assert.Nil(t, dst.DeleteTime)
+
+		equal, err := pkg.MappedEqual(SystemEvent{Created: created}, dst)
+		assert.Nil(t, err)
+		assert.True(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error on invalid timestamps", func(t *testing.T) {
+		src := APIProtoEvent{CreateTime: &timestamppb.Timestamp{Nanos: -1}}
+
+		err := pkg.Unmarshal(src, &SystemEvent{})
+
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+		pkg.ClearTypeCache()
+	})
+}
+
+type APIProtoSettings struct {
+	Name     *wrapperspb.StringValue
+	Replicas *wrapperspb.Int32Value
+	Enabled  *wrapperspb.BoolValue
+	Ratio    *wrapperspb.DoubleValue
+}
+
+type SystemSettings struct {
+	Name     string   `se:"Name"`
+	Replicas *int32   `se:"Replicas"`
+	Enabled  *bool    `se:"Enabled"`
+	Ratio    *float64 `se:"Ratio"`
+}
+
+func TestProtoWrappers(t *testing.T) {
+	t.Run("should unwrap the values when unmarshaling", func(t *testing.T) {
+		src := APIProtoSettings{
+			Name:     wrapperspb.String("test"),
+			Replicas: wrapperspb.Int32(3),
+			Enabled:  wrapperspb.Bool(false),
+		}
+		dst := SystemSettings{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		assert.Equal(t, int32(3), *dst.Replicas)
+		assert.False(t, *dst.Enabled)
+		assert.Nil(t, dst.Ratio)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should allocate the wrappers when marshaling", func(t *testing.T) {
+		enabled := false
+		dst := APIProtoSettings{}
+
+		err := pkg.Marshal(SystemSettings{Name: "test", Enabled: &enabled}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name.GetValue())
+		assert.False(t, dst.Enabled.GetValue())
+		assert.NotNil(t, dst.Enabled)
+		assert.Nil(t, dst.Replicas)
+		assert.Nil(t, dst.Ratio)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,116 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemRelativeChild struct {
+	Direction string `se:"Direction"`
+	Count     int    `se:"^^SomeCount"`
+	Name      string `se:"/Metadata.NameField"`
+	Flag      bool   `se:"../../../Metadata.Flag"`
+}
+
+type SystemRelative struct {
+	Child SystemRelativeChild `se:"Config.SomePointed.Config"`
+}
+
+func TestRelativePaths(t *testing.T) {
+	t.Run("should resolve relative and absolute paths when unmarshaling", func(t *testing.T) {
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "test", Flag: true},
+			Config: APIConfig{
+				SomeCount:   2,
+				SomePointed: &APIListedObj{Config: APIListedObjConfig{Direction: "up"}},
+			},
+		}
+		dst := SystemRelative{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, SystemRelativeChild{Direction: "up", Count: 2, Name: "test", Flag: true}, dst.Child)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should resolve relative and absolute paths when marshaling", func(t *testing.T) {
+		src := SystemRelative{Child: SystemRelativeChild{Direction: "up", Count: 2, Name: "test", Flag: true}}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, APIObject{
+			Metadata: APIMetadata{NameField: "test", Flag: true},
+			Config: APIConfig{
+				SomeCount:   2,
+				SomePointed: &APIListedObj{Config: APIListedObjConfig{Direction: "up"}},
+			},
+		}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should resolve absolute paths from the root of the mapping", func(t *testing.T) {
+		type Child struct {
+			Count int `se:"/SomeCount"`
+		}
+		dst := struct {
+			Child Child `se:"SomePointed.Config"`
+		}{}
+		src := APIObject{Config: APIConfig{SomeCount: 2}}
+
+		err := pkg.NewCodec(pkg.WithRoot("Config")).Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, dst.Child.Count)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should reject paths stripping more segments than inherited", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"^Metadata.NameField"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidRelativePath)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should reject paths leaving the elements of collections", func(t *testing.T) {
+		type Item struct {
+			Direction string `se:"Config.Direction"`
+			Count     int    `se:"^SomeCount"`
+		}
+		dst := struct {
+			Items []Item `se:"Config.SomeList"`
+		}{}
+
+		err := pkg.Unmarshal(APIObject{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidRelativePath)
+		assert.ErrorContains(t, err, "leaves the elements of Config.SomeList")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep relative paths within the elements of collections", func(t *testing.T) {
+		type Config struct {
+			List []string `se:"^List"`
+		}
+		type Item struct {
+			Config Config `se:"Config"`
+		}
+		dst := struct {
+			Items []Item `se:"Config.SomeList"`
+		}{}
+		src := APIObject{Config: APIConfig{SomeList: []APIListedObj{
+			{List: []string{"first"}},
+			{List: []string{"second"}},
+		}}}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Item{{Config{List: []string{"first"}}}, {Config{List: []string{"second"}}}}, dst.Items)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,166 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestUnmarshalWithReport(t *testing.T) {
+	t.Run("should report every skipped field without failing", func(t *testing.T) {
+		dst := SystemStruct{}
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "test"},
+		}
+
+		report, err := pkg.UnmarshalWithReport(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+
+		_, found := findSkip(report.Skipped, "Name")
+		assert.False(t, found)
+
+		skip, found := findSkip(report.Skipped, "Count")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipZeroValue, skip.Reason)
+
+		skip, found = findSkip(report.Skipped, "Nested.Direction")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipEmptySlice, skip.Reason)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return an empty report when every field is transferred", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"Metadata.NameField"`
+		}{}
+		src := APIObject{Metadata: APIMetadata{NameField: "test"}}
+
+		report, err := pkg.UnmarshalWithReport(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Empty(t, report.Skipped)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not return a report when decoding fails", func(t *testing.T) {
+		report, err := pkg.UnmarshalWithReport(APIObject{}, nil)
+
+		assert.Nil(t, report)
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report the fields skipped decoding every one of several sources", func(t *testing.T) {
+		dst := SystemStructWithMultipleDestination{}
+		src := []any{APIObject{Metadata: APIMetadata{NameField: "test"}}, &SecondaryAPIObject{ConfigFlag: true}}
+
+		report, err := pkg.UnmarshalWithReport(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		assert.True(t, dst.Flag)
+		assert.Contains(t, report.Skipped, pkg.FieldSkip{
+			Field:  "DismissNested.Direction",
+			Path:   []string{"Child", "Direction"},
+			Reason: pkg.SkipZeroValue,
+		})
+		assert.ErrorIs(t, pkg.UnmarshalStrict(src, &dst), pkg.ErrStrictSkippedFields)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestMarshalWithReport(t *testing.T) {
+	t.Run("should report every skipped field without failing", func(t *testing.T) {
+		src := SystemStruct{
+			Name:   "test",
+			Nested: SystemNested{Direction: "up"},
+		}
+		dst := APIObject{}
+
+		report, err := pkg.MarshalWithReport(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+
+		_, found := findSkip(report.Skipped, "Name")
+		assert.False(t, found)
+
+		skip, found := findSkip(report.Skipped, "Count")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipZeroValue, skip.Reason)
+		assert.Equal(t, []string{"Config", "SomeCount"}, skip.Path)
+
+		skip, found = findSkip(report.Skipped, "NestedPointer")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipNilPointer, skip.Reason)
+
+		skip, found = findSkip(report.Skipped, "StructSlice")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipEmptySlice, skip.Reason)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report fields dismissed by type matching", func(t *testing.T) {
+		src := struct {
+			Name string `se:"Metadata.NameField,types<SecondaryAPIObject>"`
+			Flag bool   `se:"Metadata.Flag,types<APIObject>"`
+		}{Name: "test", Flag: true}
+		dst := APIObject{}
+
+		report, err := pkg.MarshalWithReport(src, &dst)
+
+		assert.Nil(t, err)
+		assert.True(t, dst.Metadata.Flag)
+		skip, found := findSkip(report.Skipped, "Name")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipTypeMismatch, skip.Reason)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestSkipHandler(t *testing.T) {
+	t.Run("should notify every field skipped when unmarshaling", func(t *testing.T) {
+		skips := []pkg.FieldSkip{}
+		codec := pkg.NewCodec(pkg.WithSkipHandler(func(skip pkg.FieldSkip) { skips = append(skips, skip) }))
+		src := APIObject{Metadata: APIMetadata{NameField: "test"}}
+
+		report, err := pkg.UnmarshalWithReport(src, &SystemStruct{})
+		assert.Nil(t, err)
+
+		err = codec.Unmarshal(src, &SystemStruct{})
+		assert.Nil(t, err)
+		assert.Equal(t, report.Skipped, skips)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should notify fields dismissed by type matching when marshaling", func(t *testing.T) {
+		skips := []pkg.FieldSkip{}
+		codec := pkg.NewCodec(pkg.WithSkipHandler(func(skip pkg.FieldSkip) { skips = append(skips, skip) }))
+		src := struct {
+			Name  string `se:"Metadata.NameField,types<SecondaryAPIObject>"`
+			Flag  bool   `se:"Metadata.Flag"`
+			Count int    `se:"Config.SomeCount"`
+		}{Name: "test", Flag: true}
+
+		err := codec.Marshal(src, &APIObject{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []pkg.FieldSkip{
+			{Field: "Name", Path: []string{"Metadata", "NameField"}, Reason: pkg.SkipTypeMismatch},
+			{Field: "Count", Path: []string{"Config", "SomeCount"}, Reason: pkg.SkipZeroValue},
+		}, skips)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should not notify required fields failing the mapping", func(t *testing.T) {
+		skips := []pkg.FieldSkip{}
+		codec := pkg.NewCodec(pkg.WithSkipHandler(func(skip pkg.FieldSkip) { skips = append(skips, skip) }))
+		src := struct {
+			Name string `se:"Metadata.NameField,required"`
+		}{}
+
+		err := codec.Marshal(src, &APIObject{})
+
+		assert.ErrorIs(t, err, pkg.ErrRequiredField)
+		assert.Empty(t, skips)
+		pkg.ClearTypeCache()
+	})
+}
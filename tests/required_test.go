@@ -0,0 +1,107 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemRequired struct {
+	Name  string   `se:"Metadata.NameField,required"`
+	Count int      `se:"Config.SomeCount"`
+	List  []string `se:"Config.SomeList[0].List,required"`
+}
+
+func TestRequiredFields(t *testing.T) {
+	t.Run("should unmarshal required fields holding a value", func(t *testing.T) {
+		dst := SystemRequired{}
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "test"},
+			Config:   APIConfig{SomeList: []APIListedObj{{List: []string{"a"}}}},
+		}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		assert.Equal(t, []string{"a"}, dst.List)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail unmarshaling a zero required field", func(t *testing.T) {
+		dst := SystemRequired{}
+		src := APIObject{
+			Config: APIConfig{SomeList: []APIListedObj{{List: []string{"a"}}}},
+		}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrRequiredField)
+		assert.ErrorContains(t, err, "Name (Metadata.NameField): zero value")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail unmarshaling a required field behind an empty slice", func(t *testing.T) {
+		dst := SystemRequired{}
+		src := APIObject{Metadata: APIMetadata{NameField: "test"}}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrRequiredField)
+		assert.ErrorContains(t, err, string(pkg.SkipEmptySlice))
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail unmarshaling an empty required map", func(t *testing.T) {
+		dst := struct {
+			Labels map[string]string `se:"Metadata.Labels,required"`
+		}{}
+
+		err := pkg.Unmarshal(APIMappedObj{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrRequiredField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep skipping fields that are not required", func(t *testing.T) {
+		src := SystemRequired{Name: "test", List: []string{"a"}}
+		dst := APIObject{Config: APIConfig{SomeCount: 3}}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		assert.Equal(t, 3, dst.Config.SomeCount)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail marshaling a zero required field", func(t *testing.T) {
+		src := SystemRequired{List: []string{"a"}}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrRequiredField)
+		assert.ErrorContains(t, err, "Name (Metadata.NameField)")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail marshaling an empty required slice", func(t *testing.T) {
+		src := SystemRequired{Name: "test"}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrRequiredField)
+		assert.ErrorContains(t, err, "List")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should fail marshaling a nil required nested pointer", func(t *testing.T) {
+		src := struct {
+			Nested *SystemNested `se:"Config.SomeList[0].Config,required"`
+		}{}
+		dst := APIObject{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrRequiredField)
+		assert.ErrorContains(t, err, string(pkg.SkipNilPointer))
+		pkg.ClearTypeCache()
+	})
+}
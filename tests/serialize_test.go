@@ -0,0 +1,75 @@
+package pkg_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestReprJSON(t *testing.T) {
+	t.Run("should encode the mapping along with its children and targets", func(t *testing.T) {
+		repr, err := pkg.Describe(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+
+		data, err := json.Marshal(repr)
+		assert.Nil(t, err)
+
+		decoded := map[string]any{}
+		assert.Nil(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, "APIObject", decoded["ForeignRootType"])
+		assert.Len(t, decoded["Fields"], 7)
+		assert.Len(t, decoded["Children"], 5)
+
+		nested := decoded["Fields"].([]any)[3].(map[string]any)
+		assert.Equal(t, "Nested", nested["Name"])
+		assert.Equal(t, "struct", nested["Kind"])
+		assert.Equal(t, "pkg_test.SystemNested", nested["Type"])
+
+		target := decoded["Targets"].(map[string]any)[nested["TargetRef"].(string)].(map[string]any)
+		assert.Equal(t, []any{"Config", "SomeList[0]", "Config"}, target["Path"])
+		assert.Equal(t, []any{1.0, 1.0, 1.0}, target["IndexPath"])
+		assert.Equal(t, "pkg_test.APIListedObjConfig", target["Type"])
+		pkg.ClearTypeCache()
+	})
+	t.Run("should encode the same mapping the same way", func(t *testing.T) {
+		repr, err := pkg.Describe(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		first, err := json.Marshal(repr)
+		assert.Nil(t, err)
+		pkg.ClearTypeCache()
+
+		repr, err = pkg.Describe(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		second, err := json.Marshal(repr)
+		assert.Nil(t, err)
+
+		assert.JSONEq(t, string(first), string(second))
+		pkg.ClearTypeCache()
+	})
+	t.Run("should decode an encoded mapping", func(t *testing.T) {
+		repr, err := pkg.Describe(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		data, err := json.Marshal(repr)
+		assert.Nil(t, err)
+
+		decoded := pkg.StructRepr{}
+		err = json.Unmarshal(data, &decoded)
+
+		assert.Nil(t, err)
+		assert.Equal(t, repr.ForeignRootType, decoded.ForeignRootType)
+		assert.Len(t, decoded.Fields, len(repr.Fields))
+		for i, field := range decoded.Fields {
+			assert.Equal(t, repr.Fields[i].Name, field.Name)
+			assert.Equal(t, repr.Fields[i].Kind, field.Kind)
+			assert.Equal(t, repr.Fields[i].Tag.Path, field.Tag.Path)
+			assert.Equal(t, repr.Fields[i].TargetRef, field.TargetRef)
+			assert.Nil(t, field.Type)
+		}
+		assert.Equal(t, reflect.Struct, decoded.Fields[3].Kind)
+		pkg.ClearTypeCache()
+	})
+}
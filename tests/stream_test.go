@@ -0,0 +1,145 @@
+package pkg_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestUnmarshalStream(t *testing.T) {
+	t.Run("should decode every object until the input is closed", func(t *testing.T) {
+		in := make(chan interface{}, 3)
+		out := make(chan SystemStructWithMultipleDestination, 3)
+		in <- APIObject{Metadata: APIMetadata{NameField: "first"}}
+		in <- &APIObject{Metadata: APIMetadata{NameField: "second"}}
+		in <- SecondaryAPIObject{Metadata: APIMetadata{NameField: "third"}}
+		close(in)
+
+		err := pkg.UnmarshalStream(context.Background(), in, out)
+		close(out)
+
+		assert.Nil(t, err)
+		names := []string{}
+		for local := range out {
+			names = append(names, local.Name)
+		}
+		assert.Equal(t, []string{"first", "second", "third"}, names)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should stop when the context is cancelled", func(t *testing.T) {
+		in := make(chan interface{})
+		out := make(chan SystemStruct)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := pkg.UnmarshalStream(ctx, in, out)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return decoding errors", func(t *testing.T) {
+		var nilSrc *APIObject
+		in := make(chan interface{}, 2)
+		out := make(chan SystemStruct, 2)
+		in <- APIObject{}
+		in <- nilSrc
+		close(in)
+
+		err := pkg.UnmarshalStream(context.Background(), in, out)
+
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalSrcType)
+		assert.Len(t, out, 1)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestPipe(t *testing.T) {
+	t.Run("should map every value until the input is closed", func(t *testing.T) {
+		in := make(chan SystemStruct, 100)
+		out := make(chan APIObject, 100)
+		for i := range 100 {
+			in <- SystemStruct{Count: i + 1}
+		}
+		close(in)
+
+		err := pkg.Pipe(context.Background(), in, out, pkg.Marshal, 4)
+		close(out)
+
+		assert.Nil(t, err)
+		counts := []int{}
+		for foreign := range out {
+			counts = append(counts, foreign.Config.SomeCount)
+		}
+		slices.Sort(counts)
+		assert.Len(t, counts, 100)
+		assert.Equal(t, 1, counts[0])
+		assert.Equal(t, 100, counts[99])
+		pkg.ClearTypeCache()
+	})
+	t.Run("should introspect the types from concurrent workers with a cold cache", func(t *testing.T) {
+		pkg.ClearTypeCache()
+		in := make(chan *APIObject, 64)
+		out := make(chan SystemStruct, 64)
+		for i := range 64 {
+			in <- &APIObject{Config: APIConfig{SomeCount: i + 1}}
+		}
+		close(in)
+
+		err := pkg.Pipe(context.Background(), in, out, pkg.Unmarshal, 8)
+		close(out)
+
+		assert.Nil(t, err)
+		assert.Len(t, out, 64)
+		assert.Greater(t, pkg.CacheStats().LocalEntries, 0)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep the order with a single worker", func(t *testing.T) {
+		mapper, err := pkg.NewMapper(SystemStruct{}, APIObject{})
+		assert.Nil(t, err)
+		in := make(chan *APIObject, 3)
+		out := make(chan SystemStruct, 3)
+		for _, name := range []string{"first", "second", "third"} {
+			in <- &APIObject{Metadata: APIMetadata{NameField: name}}
+		}
+		close(in)
+
+		err = pkg.Pipe(context.Background(), in, out, mapper.Decode, 0)
+		close(out)
+
+		assert.Nil(t, err)
+		names := []string{}
+		for local := range out {
+			names = append(names, local.Name)
+		}
+		assert.Equal(t, []string{"first", "second", "third"}, names)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should stop when the context is cancelled", func(t *testing.T) {
+		in := make(chan SystemStruct)
+		out := make(chan APIObject)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := pkg.Pipe(ctx, in, out, pkg.Marshal, 2)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should return mapping errors", func(t *testing.T) {
+		in := make(chan *APIObject, 2)
+		out := make(chan SystemStruct, 2)
+		in <- &APIObject{}
+		in <- nil
+		close(in)
+
+		err := pkg.Pipe(context.Background(), in, out, pkg.Unmarshal, 1)
+
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalSrcType)
+		assert.Len(t, out, 1)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,145 @@
+package pkg_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func findSkip(skips []pkg.FieldSkip, field string) (pkg.FieldSkip, bool) {
+	for _, skip := range skips {
+		if skip.Field == field {
+			return skip, true
+		}
+	}
+	return pkg.FieldSkip{}, false
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	t.Run("should not error when every field is transferred", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"Metadata.NameField"`
+			Flag bool   `se:"Metadata.Flag"`
+		}{}
+		src := APIObject{Metadata: APIMetadata{NameField: "test", Flag: true}}
+
+		err := pkg.UnmarshalStrict(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report every skipped field with its reason", func(t *testing.T) {
+		dst := SystemStruct{}
+		src := APIObject{
+			Metadata: APIMetadata{NameField: "test"},
+		}
+
+		err := pkg.UnmarshalStrict(src, &dst)
+
+		var strictErr *pkg.StrictError
+		assert.True(t, errors.As(err, &strictErr))
+		assert.ErrorIs(t, err, pkg.ErrStrictSkippedFields)
+		assert.Equal(t, "test", dst.Name)
+
+		_, found := findSkip(strictErr.Skips, "Name")
+		assert.False(t, found)
+
+		skip, found := findSkip(strictErr.Skips, "Count")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipZeroValue, skip.Reason)
+		assert.Equal(t, []string{"Config", "SomeCount"}, skip.Path)
+
+		skip, found = findSkip(strictErr.Skips, "Nested.Direction")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipEmptySlice, skip.Reason)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report nil pointers found mid-path", func(t *testing.T) {
+		dst := struct {
+			Direction string `se:"Config.SomePointed.Config.Direction"`
+		}{}
+
+		err := pkg.UnmarshalStrict(APIObject{}, &dst)
+
+		var strictErr *pkg.StrictError
+		assert.True(t, errors.As(err, &strictErr))
+		skip, found := findSkip(strictErr.Skips, "Direction")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipNilPointer, skip.Reason)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report fields dismissed by type matching", func(t *testing.T) {
+		dst := struct {
+			Name string `se:"Metadata.NameField,types<SecondaryAPIObject>"`
+			Flag bool   `se:"ConfigFlag,types<APIObject>"`
+		}{}
+		src := SecondaryAPIObject{
+			Metadata:   APIMetadata{NameField: "test"},
+			ConfigFlag: true,
+		}
+
+		err := pkg.UnmarshalStrict(src, &dst)
+
+		var strictErr *pkg.StrictError
+		assert.True(t, errors.As(err, &strictErr))
+		assert.Len(t, strictErr.Skips, 1)
+		assert.Equal(t, "Flag", strictErr.Skips[0].Field)
+		assert.Equal(t, pkg.SkipTypeMismatch, strictErr.Skips[0].Reason)
+		assert.Equal(t, "test", dst.Name)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep returning input validation errors", func(t *testing.T) {
+		err := pkg.UnmarshalStrict(APIObject{}, SystemStruct{})
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		pkg.ClearTypeCache()
+	})
+}
+
+func TestMarshalStrict(t *testing.T) {
+	t.Run("should not error when every field is transferred", func(t *testing.T) {
+		src := struct {
+			Name string `se:"Metadata.NameField"`
+			Flag bool   `se:"Metadata.Flag"`
+		}{Name: "test", Flag: true}
+		dst := APIObject{}
+
+		err := pkg.MarshalStrict(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report every skipped field with its reason", func(t *testing.T) {
+		src := SystemStruct{Name: "test"}
+		dst := APIObject{}
+
+		err := pkg.MarshalStrict(src, &dst)
+
+		var strictErr *pkg.StrictError
+		assert.True(t, errors.As(err, &strictErr))
+		assert.ErrorIs(t, err, pkg.ErrStrictSkippedFields)
+		assert.Equal(t, "test", dst.Metadata.NameField)
+
+		_, found := findSkip(strictErr.Skips, "Name")
+		assert.False(t, found)
+
+		skip, found := findSkip(strictErr.Skips, "Flag")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipZeroValue, skip.Reason)
+		assert.Equal(t, []string{"Metadata", "Flag"}, skip.Path)
+
+		skip, found = findSkip(strictErr.Skips, "NestedPointer")
+		assert.True(t, found)
+		assert.Equal(t, pkg.SkipNilPointer, skip.Reason)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should keep returning input validation errors", func(t *testing.T) {
+		err := pkg.MarshalStrict(SystemStruct{}, APIObject{})
+		assert.ErrorIs(t, err, pkg.ErrUnmarshalDestType)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,70 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type APIStringly struct {
+	Replicas string
+	Enabled  string
+	Ratio    string
+	Count    int
+}
+
+type SystemStringly struct {
+	Replicas int     `se:"Replicas,stringify"`
+	Enabled  bool    `se:"Enabled,stringify"`
+	Ratio    float64 `se:"Ratio,stringify"`
+	Count    string  `se:"Count,stringify"`
+}
+
+func TestStringify(t *testing.T) {
+	t.Run("should parse the foreign strings when unmarshaling", func(t *testing.T) {
+		src := APIStringly{Replicas: "3", Enabled: "true", Ratio: "0.25", Count: 7}
+		dst := SystemStringly{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, SystemStringly{Replicas: 3, Enabled: true, Ratio: 0.25, Count: "7"}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should format the local values when marshaling", func(t *testing.T) {
+		dst := APIStringly{}
+
+		err := pkg.Marshal(SystemStringly{Replicas: 3, Enabled: true, Ratio: 1500000, Count: "7"}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, APIStringly{Replicas: "3", Enabled: "true", Ratio: "1500000", Count: 7}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error on values that can't be parsed", func(t *testing.T) {
+		err := pkg.Unmarshal(APIStringly{Replicas: "three"}, &SystemStringly{})
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+
+		err = pkg.Marshal(SystemStringly{Count: "seven"}, &APIStringly{})
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should compare the formatted values", func(t *testing.T) {
+		equal, err := pkg.MappedEqual(SystemStringly{Replicas: 3}, APIStringly{Replicas: "3"})
+
+		assert.Nil(t, err)
+		assert.True(t, equal)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when set on other fields", func(t *testing.T) {
+		local := struct {
+			Replicas string `se:"Replicas,stringify"`
+		}{}
+
+		err := pkg.Unmarshal(APIStringly{}, &local)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidStringify)
+		pkg.ClearTypeCache()
+	})
+}
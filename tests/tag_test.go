@@ -0,0 +1,53 @@
+package pkg_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestParseTagValue(t *testing.T) {
+	t.Run("should parse tag values just like field tags", func(t *testing.T) {
+		value := `+,types<Deployment:Spec.Replicas|Service:Port>,required`
+		field := reflect.StructField{Name: "Replicas", Tag: reflect.StructTag(`se:"` + value + `"`)}
+		assert.Equal(t, pkg.ParseTag(field, pkg.FIELD_TAG_KEY), pkg.ParseTagValue("Replicas", value))
+
+		tag := pkg.ParseTagValue("Name", "=||Metadata.OldName,readonly")
+		assert.Equal(t, []string{"Name"}, tag.Path)
+		assert.Equal(t, [][]string{{"Metadata", "OldName"}}, tag.Alternatives)
+		assert.True(t, tag.Opts.ReadOnly)
+
+		assert.True(t, pkg.ParseTagValue("Name", "-").Skip)
+		assert.True(t, pkg.ParseTagValue("Name", "").Skip)
+	})
+}
+
+func TestFieldTagValidate(t *testing.T) {
+	t.Run("should accept valid tags", func(t *testing.T) {
+		for _, value := range []string{
+			"Metadata.Name",
+			"+,types<Deployment:Spec.Replicas|Service:Port>",
+			"Spec.Name||Spec.OldName",
+			"+,join<Metadata.Namespace|/|Metadata.Name>",
+			"-",
+		} {
+			assert.Nil(t, pkg.ParseTagValue("Field", value).Validate(), value)
+		}
+	})
+	t.Run("should report the errors the mapping would fail with", func(t *testing.T) {
+		cases := map[string]error{
+			"Metadata.Name,readonly,writeonly":              pkg.ErrConflictingDirection,
+			"Metadata.Name,types<Deployment:Spec.Replicas>": pkg.ErrInvalidPerTypePath,
+			"Spec.Name||Spec.OldName,kind<Deployment:Spec>": pkg.ErrInvalidFallbackPath,
+			"Metadata.Name,merge<append>":                   pkg.ErrInvalidMapMergeMode,
+			"Metadata.Name,types<~(>":                       pkg.ErrInvalidTypePattern,
+			"+,types<Deployment:Spec.Replicas>,in<Spec>":    pkg.ErrInvalidDirectionalPath,
+		}
+		for value, expected := range cases {
+			assert.ErrorIs(t, pkg.ParseTagValue("Field", value).Validate(), expected, value)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package a
+
+// Mock the foreign types of an API
+type Metadata struct {
+	Name   string
+	Labels map[string]string
+}
+type Container struct {
+	Image string
+}
+type Spec struct {
+	Containers []Container
+	Replicas   *int32
+}
+type Deployment struct {
+	Metadata Metadata
+	Spec     Spec
+}
+type Service struct {
+	Metadata Metadata
+	Port     int
+}
+type TypeMeta struct {
+	Kind string
+}
+type Envelope struct {
+	Object any
+}
+type StatefulSet struct {
+	TypeMeta
+	*Metadata
+	Spec Spec
+}
+
+// Mock the local types mapping them
+type App struct {
+	Name       string            `se:"Metadata.Name,types<Deployment|Service>"`
+	Missing    string            `se:"Metadata.Nmae,types<Deployment>"` // want `se tag path "Metadata.Nmae" of field Missing not found in Deployment`
+	Renamed    string            `se:"Metadata.Old||Metadata.Name,types<Deployment>"`
+	Replicas   int32             `se:"+,types<Deployment:Spec.Replicas|Service:Port>"`
+	Port       string            `se:"Port,types<Service>"` // want `se tag of field Port maps a string into Service field of type int`
+	PortText   string            `se:"Port,types<Service>,stringify"`
+	Labels     map[string]string `se:"Metadata.Labels,types<Deployment>"`
+	Containers []AppContainer    `se:"Spec.Containers,types<Deployment>"`
+	Unknown    string            `se:"Whatever,types<Unknown>"`
+	Pattern    string            `se:"Whatever,types<~^Deploy>"`
+	Skipped    string            `se:"-"`
+	Invalid    string            `se:"Metadata.Name,readonly,writeonly"` // want `invalid se tag of field Invalid: readonly fields can't be writeonly nor constant`
+}
+type AppContainer struct {
+	Image    string `se:"Image"`
+	Tag      string `se:"Tag"` // want `se tag path "Tag" of field Tag not found in Deployment`
+	AppName  string `se:"/Metadata.Name"`
+	Stripped string `se:"^^^Name"` // want `se tag path "\^.\^.\^.Name" of field Stripped not found in Deployment`
+}
+type AppSet struct {
+	Kind     int               `se:"Kind,types<StatefulSet>"` // want `se tag of field Kind maps a int into StatefulSet field of type string`
+	Name     string            `se:"Name,types<StatefulSet>"`
+	Labels   map[string]string `se:"Metadata.Labels,types<StatefulSet>"`
+	Replicas int32             `se:"Spec.Replicas,types<StatefulSet>"`
+}
+type AppEnvelope struct {
+	Replicas int32 `se:"Object.Spec.Replicas,types<Envelope>,as<Deployment>"`
+}
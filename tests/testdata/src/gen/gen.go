@@ -0,0 +1,58 @@
+package gen
+
+import "time"
+
+// Mock the foreign types of an API
+type Metadata struct {
+	Name      string
+	OldName   string
+	Labels    map[string]string
+	CreatedAt time.Time
+}
+type Spec struct {
+	Replicas int32
+	Paused   bool
+}
+type Status struct {
+	Ready int32
+}
+type Deployment struct {
+	Metadata Metadata
+	Spec     *Spec
+	Status   Status
+	Note     string
+}
+type Service struct {
+	Metadata Metadata
+	Port     int
+}
+
+// Mock the local types mapping them
+type App struct {
+	Name      string            `se:"Metadata.Name||Metadata.OldName"`
+	Labels    map[string]string `se:"Metadata.Labels"`
+	CreatedAt time.Time         `se:"Metadata.CreatedAt,readonly"`
+	Scaling   AppScaling        `se:"Spec,types<Deployment>"`
+	Status    AppStatus         `se:"->,types<Deployment>"`
+	Port      int               `se:"Port,types<Service>"`
+	Note      string            `se:"=,types<Deployment>,writeonly"`
+	Skipped   string            `se:"-"`
+	Untagged  string
+}
+type AppScaling struct {
+	Replicas int32 `se:"Replicas"`
+	Paused   bool  `se:"Paused"`
+	Ready    int32 `se:"^Status.Ready,readonly"`
+}
+type AppStatus struct {
+	Ready int32 `se:"Status.Ready"`
+}
+type Release struct {
+	AppStatus
+	Name string `se:"Metadata.Name"`
+}
+
+// Mock a local type the mappers can't be generated for
+type Unsupported struct {
+	Name string `se:"Metadata.Name,required"`
+}
@@ -0,0 +1,85 @@
+package pkg_test
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type SystemColor int
+
+const (
+	ColorRed SystemColor = iota + 1
+	ColorBlue
+)
+
+func (this SystemColor) MarshalText() ([]byte, error) {
+	switch this {
+	case ColorRed:
+		return []byte("red"), nil
+	case ColorBlue:
+		return []byte("blue"), nil
+	}
+	return nil, fmt.Errorf("unknown color %d", int(this))
+}
+
+func (this *SystemColor) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "red":
+		*this = ColorRed
+	case "blue":
+		*this = ColorBlue
+	default:
+		return fmt.Errorf("unknown color %q", text)
+	}
+	return nil
+}
+
+type APINode struct {
+	Address string
+	Color   string
+	Gateway netip.Addr
+}
+
+type SystemNode struct {
+	Address net.IP      `se:"Address"`
+	Color   SystemColor `se:"Color"`
+	Gateway string      `se:"Gateway"`
+}
+
+func TestTextConversion(t *testing.T) {
+	t.Run("should unmarshal text from the foreign strings", func(t *testing.T) {
+		src := APINode{Address: "10.0.0.1", Color: "blue", Gateway: netip.MustParseAddr("10.0.0.254")}
+		dst := SystemNode{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "10.0.0.1", dst.Address.String())
+		assert.Equal(t, ColorBlue, dst.Color)
+		assert.Equal(t, "10.0.0.254", dst.Gateway)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal text into the foreign strings", func(t *testing.T) {
+		dst := APINode{}
+
+		err := pkg.Marshal(SystemNode{Address: net.ParseIP("10.0.0.1"), Color: ColorRed, Gateway: "10.0.0.254"}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, APINode{Address: "10.0.0.1", Color: "red", Gateway: netip.MustParseAddr("10.0.0.254")}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should report text errors", func(t *testing.T) {
+		err := pkg.Unmarshal(APINode{Color: "green"}, &SystemNode{})
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+
+		err = pkg.Marshal(SystemNode{Color: 7}, &APINode{})
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,72 @@
+package pkg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type APITimestamps struct {
+	CreatedAt string
+	Day       string
+	Updated   time.Time
+}
+
+type SystemTimestamps struct {
+	CreatedAt time.Time `se:"CreatedAt,time<RFC3339>"`
+	Day       time.Time `se:"Day,time<2006/01/02>"`
+	Updated   string    `se:"Updated,time<DateOnly>"`
+}
+
+func TestTimeLayouts(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	day := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should parse the foreign strings when unmarshaling", func(t *testing.T) {
+		src := APITimestamps{CreatedAt: "2024-05-01T10:30:00Z", Day: "2024/05/02", Updated: day}
+		dst := SystemTimestamps{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, SystemTimestamps{CreatedAt: created, Day: day, Updated: "2024-05-02"}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should format the local times when marshaling", func(t *testing.T) {
+		dst := APITimestamps{}
+
+		err := pkg.Marshal(SystemTimestamps{CreatedAt: created, Day: day, Updated: "2024-05-02"}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, APITimestamps{CreatedAt: "2024-05-01T10:30:00Z", Day: "2024/05/02", Updated: day}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should skip zero times", func(t *testing.T) {
+		dst := APITimestamps{CreatedAt: "kept"}
+
+		err := pkg.Marshal(SystemTimestamps{}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "kept", dst.CreatedAt)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error on strings not matching the layout", func(t *testing.T) {
+		err := pkg.Unmarshal(APITimestamps{Day: "2024-05-02"}, &SystemTimestamps{})
+
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when set on other fields", func(t *testing.T) {
+		local := struct {
+			CreatedAt string `se:"CreatedAt,time<RFC3339>"`
+		}{}
+
+		err := pkg.Unmarshal(APITimestamps{}, &local)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidTimeLayout)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,84 @@
+package pkg_test
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+// Mock a persistence type stored as a comma separated column
+type DBTags []string
+
+func (this DBTags) Value() (driver.Value, error) {
+	return strings.Join(this, ","), nil
+}
+
+func (this *DBTags) Scan(src any) error {
+	text, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("unsupported tags value %T", src)
+	}
+	*this = strings.Split(text, ",")
+	return nil
+}
+
+// Mock a persistence type stored as an integer column
+type DBCents int64
+
+func (this DBCents) Value() (driver.Value, error) {
+	return int64(this), nil
+}
+
+func (this *DBCents) Scan(src any) error {
+	cents, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("unsupported cents value %T", src)
+	}
+	*this = DBCents(cents)
+	return nil
+}
+
+type APIProduct struct {
+	Tags  string
+	Price int32
+	Owner DBTags
+}
+
+type DBProduct struct {
+	Tags  DBTags  `se:"Tags"`
+	Price DBCents `se:"Price"`
+	Owner string  `se:"Owner"`
+}
+
+func TestValuerConversion(t *testing.T) {
+	t.Run("should scan the foreign values when unmarshaling", func(t *testing.T) {
+		src := APIProduct{Tags: "a,b", Price: 1250, Owner: DBTags{"team", "ops"}}
+		dst := DBProduct{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, DBProduct{Tags: DBTags{"a", "b"}, Price: 1250, Owner: "team,ops"}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should write the database values when marshaling", func(t *testing.T) {
+		dst := APIProduct{}
+
+		err := pkg.Marshal(DBProduct{Tags: DBTags{"a", "b"}, Price: 1250, Owner: "team,ops"}, &dst)
+
+		assert.Nil(t, err)
+		assert.Equal(t, APIProduct{Tags: "a,b", Price: 1250, Owner: DBTags{"team", "ops"}}, dst)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when the database value doesn't fit", func(t *testing.T) {
+		err := pkg.Marshal(DBProduct{Price: 1 << 40}, &APIProduct{})
+
+		assert.ErrorIs(t, err, pkg.ErrConvertFunc)
+		pkg.ClearTypeCache()
+	})
+}
@@ -0,0 +1,93 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+func TestPerVersionPaths(t *testing.T) {
+	t.Run("should unmarshal from the path selected by the api version", func(t *testing.T) {
+		v1 := APIScaler{APIVersion: "autoscaling/v1"}
+		v1.Spec.MinReplicas = 2
+		v1.Spec.Scale.Min = 9
+		v2 := APIScaler{APIVersion: "autoscaling/v2"}
+		v2.Spec.MinReplicas = 9
+		v2.Spec.Scale.Min = 3
+
+		dst := SystemScaler{}
+		err := pkg.Unmarshal(v1, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, dst.MinReplicas)
+
+		dst = SystemScaler{}
+		err = pkg.Unmarshal(v2, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "autoscaling/v2", dst.APIVersion)
+		assert.Equal(t, 3, dst.MinReplicas)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should marshal into the path selected by the api version", func(t *testing.T) {
+		src := SystemScaler{APIVersion: "autoscaling/v2", MinReplicas: 3}
+		dst := APIScaler{}
+
+		err := pkg.Marshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Zero(t, dst.Spec.MinReplicas)
+		assert.Equal(t, 3, dst.Spec.Scale.Min)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should skip the field for unknown api versions", func(t *testing.T) {
+		src := APIScaler{APIVersion: "autoscaling/v3"}
+		src.Spec.MinReplicas = 2
+		dst := SystemScaler{}
+
+		err := pkg.Unmarshal(src, &dst)
+
+		assert.Nil(t, err)
+		assert.Zero(t, dst.MinReplicas)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should use the version field and main path set on the tag", func(t *testing.T) {
+		src := APIWorkload{}
+		src.Meta.Type = "v1beta1"
+		src.Spec.Template.Image = "nginx"
+		src.Spec.JobTemplate.Spec.Template.Image = "busybox"
+		dst := struct {
+			Image string `se:"Spec.Template.Image,version<v1|v1beta1:Spec.JobTemplate.Spec.Template.Image>,versionfield<Meta.Type>"`
+		}{}
+
+		err := pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "busybox", dst.Image)
+
+		src.Meta.Type = "v1"
+		err = pkg.Unmarshal(src, &dst)
+		assert.Nil(t, err)
+		assert.Equal(t, "nginx", dst.Image)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when an entry has no path to map to", func(t *testing.T) {
+		dst := struct {
+			MinReplicas int `se:"+,version<autoscaling/v1>"`
+		}{}
+
+		err := pkg.Unmarshal(APIScaler{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrMissingVersionPath)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should error when combined with per-kind paths", func(t *testing.T) {
+		dst := struct {
+			Image string `se:"+,version<v1:Spec.Template.Image>,kind<Deployment:Spec.Template.Image>"`
+		}{}
+
+		err := pkg.Unmarshal(APIWorkload{}, &dst)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidVersionPath)
+		pkg.ClearTypeCache()
+	})
+}
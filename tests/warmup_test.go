@@ -0,0 +1,64 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilexPar/struct-marshal/pkg"
+)
+
+type BrokenWarmupStruct struct {
+	Name string `se:"Metadata.Missing"`
+}
+
+func TestIntrospectAll(t *testing.T) {
+	t.Run("should introspect every pair up front", func(t *testing.T) {
+		pkg.ClearTypeCache()
+
+		err := pkg.IntrospectAll(
+			pkg.PairOf[SystemStruct, APIObject](),
+			pkg.Pair{Local: SystemMapped{}, Foreign: &APIMappedObj{}},
+		)
+		assert.Nil(t, err)
+		misses := pkg.CacheStats().Misses
+		err = pkg.Marshal(SystemMapped{Labels: map[string]string{"a": "b"}}, &APIMappedObj{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, misses, pkg.CacheStats().Misses)
+		pkg.ClearTypeCache()
+	})
+	t.Run("should join the errors of every failing pair", func(t *testing.T) {
+		err := pkg.IntrospectAll(
+			pkg.PairOf[BrokenWarmupStruct, APIObject](),
+			pkg.PairOf[SystemStruct, APIObject](),
+			pkg.Pair{Local: 1, Foreign: APIObject{}},
+			pkg.Pair{Local: SystemStruct{}},
+		)
+
+		assert.ErrorContains(t, err, "pair 0 (*pkg_test.BrokenWarmupStruct, *pkg_test.APIObject): ")
+		assert.ErrorContains(t, err, "pair 2 (int, pkg_test.APIObject): "+pkg.ErrLocalTypeNotStruct.Error())
+		assert.ErrorContains(t, err, "pair 3 (pkg_test.SystemStruct, <nil>): "+pkg.ErrForeignTypeNotStruct.Error())
+		assert.NotContains(t, err.Error(), "pair 1")
+		pkg.ClearTypeCache()
+	})
+	t.Run("should introspect pairs in parallel into the cache of the codec", func(t *testing.T) {
+		pkg.ClearTypeCache()
+		codec := pkg.NewCodec(pkg.WithParallelism(4))
+
+		err := codec.IntrospectAll(
+			pkg.PairOf[SystemStruct, APIObject](),
+			pkg.PairOf[SystemMapped, APIMappedObj](),
+			pkg.PairOf[SystemNested, APIListedObjConfig](),
+			pkg.PairOf[BrokenWarmupStruct, APIObject](),
+		)
+		assert.ErrorContains(t, err, "pair 3 ")
+		stats := pkg.CacheStats()
+		err = codec.Marshal(SystemStruct{Name: "test"}, &APIObject{})
+
+		assert.Nil(t, err)
+		assert.Greater(t, stats.Misses, uint64(0))
+		assert.Equal(t, stats.Misses, pkg.CacheStats().Misses)
+		pkg.ClearTypeCache()
+	})
+}